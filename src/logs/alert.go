@@ -0,0 +1,116 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AlertEvent describes a triggered alert.
+type AlertEvent struct {
+	Level  level         // The level watched by the alert
+	Count  int           // Entries seen in the window
+	Window time.Duration // The window of the alert
+	Last   Entry         // The entry that triggered the alert
+}
+
+// String returns a one line description of the alert.
+func (a AlertEvent) String() string {
+	return fmt.Sprintf("%d %s entries in %s, last: %s", a.Count,
+		levelName(a.Level), a.Window, a.Last.Message)
+}
+
+// Alert fires when more than Threshold entries at Level or above are written
+// within Window. After firing, the alert stays quiet for Cooldown to avoid
+// alert storms. Func is called and, if WebhookURL is set, a JSON message is
+// posted to it. The message has a "text" key, so it can be sent to Slack
+// style incoming webhooks as is. Both run in their own goroutine.
+//
+//	logs.AddAlert(&logs.Alert{
+//		Level:      logs.LEVEL_ERROR,
+//		Threshold:  10,
+//		Window:     time.Minute,
+//		Cooldown:   15 * time.Minute,
+//		WebhookURL: "https://hooks.slack.com/services/...",
+//	})
+type Alert struct {
+	Level      level
+	Threshold  int
+	Window     time.Duration
+	Cooldown   time.Duration
+	Func       func(AlertEvent)
+	WebhookURL string
+	Client     *http.Client // Used for webhooks, http.DefaultClient if nil
+
+	mu    sync.Mutex
+	times []time.Time // Times of the entries in the window
+	quiet time.Time   // No alerts are fired before this time
+}
+
+// observe counts e and fires the alert if the threshold is exceeded.
+func (a *Alert) observe(e *Entry) {
+	if e.Level < a.Level || e.Level == LEVEL_PRINT {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	start := e.Time.Add(-a.Window)
+	i := 0
+	for i < len(a.times) && !a.times[i].After(start) {
+		i++
+	}
+	a.times = append(a.times[i:], e.Time)
+	if len(a.times) <= a.Threshold || e.Time.Before(a.quiet) {
+		return
+	}
+	ev := AlertEvent{Level: a.Level, Count: len(a.times), Window: a.Window, Last: *e}
+	a.quiet = e.Time.Add(a.Cooldown)
+	a.times = nil
+	if a.Func != nil {
+		go a.Func(ev)
+	}
+	if a.WebhookURL != "" {
+		go a.post(ev)
+	}
+}
+
+// post sends ev to the webhook. Errors are ignored since there is nowhere
+// sensible to log them.
+func (a *Alert) post(ev AlertEvent) {
+	b, _ := json.Marshal(map[string]interface{}{
+		"text":    ev.String(),
+		"level":   levelName(ev.Level),
+		"count":   ev.Count,
+		"window":  ev.Window.String(),
+		"message": ev.Last.Message,
+		"time":    ev.Last.Time.Format(time.RFC3339Nano),
+	})
+	client := a.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Post(a.WebhookURL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// AddAlert adds an alert to the standard logging object.
+func AddAlert(a *Alert) { std.AddAlert(a) }
+
+// AddAlert adds an alert watching the entries written by l. Entries filtered
+// by the logging level are not counted.
+func (l *Logger) AddAlert(a *Alert) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.alerts = append(l.alerts, a)
+}
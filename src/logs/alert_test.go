@@ -0,0 +1,65 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAlert(t *testing.T) {
+	fired := make(chan AlertEvent, 10)
+	logr := New(LEVEL_DEBUG, ioutil.Discard)
+	logr.AddAlert(&Alert{
+		Level:     LEVEL_ERROR,
+		Threshold: 2,
+		Window:    time.Minute,
+		Cooldown:  time.Hour,
+		Func:      func(ev AlertEvent) { fired <- ev },
+	})
+	logr.Warningln("not counted")
+	logr.Errorln("one")
+	logr.Errorln("two")
+	logr.Criticalln("three")
+	logr.Errorln("cooling down")
+	logr.Errorln("cooling down")
+	logr.Errorln("cooling down")
+
+	ev := <-fired
+	if ev.Count != 3 || ev.Last.Message != "three" {
+		t.Errorf("\nGot:\t%d %q\nExpect:\t%d %q\n", ev.Count, ev.Last.Message, 3, "three")
+	}
+	select {
+	case ev := <-fired:
+		t.Errorf("Alert fired during cooldown: %v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAlertWebhook(t *testing.T) {
+	posted := make(chan map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&m)
+		posted <- m
+	}))
+	defer srv.Close()
+
+	logr := New(LEVEL_DEBUG, ioutil.Discard)
+	logr.AddAlert(&Alert{Level: LEVEL_ERROR, Window: time.Minute, WebhookURL: srv.URL})
+	logr.Errorln("database down")
+
+	select {
+	case m := <-posted:
+		if expect := "1 error entries in 1m0s, last: database down"; m["text"] != expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", m["text"], expect)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not called")
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ObjectStore stores objects in a bucket. Adapters for the S3 and Cloud
+// Storage clients only need a few lines, so neither is a dependency of this
+// package.
+type ObjectStore interface {
+	// Put stores size bytes read from r as key.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+
+	// Stat returns the size and checksum of the stored object key.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+}
+
+// ObjectInfo describes a stored object. Uploads are verified with SHA256 if it
+// is set, or else with ETag if it is the MD5 of the object, as it is for
+// objects stored in one part.
+type ObjectInfo struct {
+	Size   int64
+	SHA256 string // Hex encoded, empty if the store does not keep it
+	ETag   string
+}
+
+// archiveRecord is a line of the manifest.
+type archiveRecord struct {
+	File   string    `json:"file"`
+	Key    string    `json:"key"`
+	Size   int64     `json:"size"`
+	SHA256 string    `json:"sha256"`
+	Time   time.Time `json:"time"`
+}
+
+// Archiver uploads rotated log files to an object store and removes them
+// locally once the size and checksum of the upload are verified. Files not ending in ".gz" are
+// compressed first. Every upload is recorded as a JSON line in the manifest
+// file, if set.
+//
+//	a := &logs.Archiver{
+//		Store:    s3Store,
+//		Pattern:  "/var/log/app/app.log.*",
+//		Prefix:   "app/host1/",
+//		Manifest: "/var/log/app/archive.manifest",
+//	}
+//	uploaded, err := a.ArchiveNow(ctx)
+//
+// ArchiveNow is meant to be called after every rotation, which
+// ReopenableFile.SetArchiver arranges. Run it before Retention so files are
+// uploaded before they are pruned.
+type Archiver struct {
+	Store    ObjectStore
+	Pattern  string        // Glob matching the rotated files
+	Prefix   string        // Prepended to the file names to create keys
+	Manifest string        // Path of the manifest, none if empty
+	Retries  int           // Attempts per file after the first, 3 if zero
+	Backoff  time.Duration // Wait before the first retry, doubled each time
+}
+
+// ArchiveNow uploads all files matching the pattern and returns the keys of
+// the uploaded files. Files that fail after all retries are kept and the
+// first error is returned; the other files are still uploaded.
+func (a *Archiver) ArchiveNow(ctx context.Context) ([]string, error) {
+	paths, err := filepath.Glob(a.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var keys []string
+	var firstErr error
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+		key, err := a.archive(ctx, p)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, firstErr
+}
+
+// archive compresses, uploads, verifies, records, and removes one file.
+func (a *Archiver) archive(ctx context.Context, file string) (string, error) {
+	src, cleanup, err := gzipFile(file)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	key := a.Prefix + filepath.Base(file)
+	if src != file {
+		key += ".gz"
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h, m := sha256.New(), md5.New()
+	size, err := io.Copy(io.MultiWriter(h, m), f)
+	if err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	etag := hex.EncodeToString(m.Sum(nil))
+
+	retries, wait := a.Retries, a.Backoff
+	if retries == 0 {
+		retries = 3
+	}
+	if wait == 0 {
+		wait = time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		if err = a.upload(ctx, f, key, size, sum, etag); err == nil {
+			break
+		}
+		if attempt == retries {
+			return "", fmt.Errorf("logs: archiving %s: %v", file, err)
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		wait *= 2
+	}
+
+	if a.Manifest != "" {
+		b, _ := json.Marshal(archiveRecord{File: file, Key: key, Size: size,
+			SHA256: sum, Time: time.Now()})
+		m, err := os.OpenFile(a.Manifest, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return "", err
+		}
+		_, err = m.Write(append(b, '\n'))
+		if cerr := m.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return key, os.Remove(file)
+}
+
+// upload puts f and checks the size and checksum of the stored object against
+// size, the hex SHA-256 sum, and the hex MD5 etag of f. An object that cannot
+// be verified is an error, so the file is kept.
+func (a *Archiver) upload(ctx context.Context, f *os.File, key string, size int64,
+	sum, etag string) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := a.Store.Put(ctx, key, f, size); err != nil {
+		return err
+	}
+	info, err := a.Store.Stat(ctx, key)
+	if err != nil {
+		return err
+	}
+	if info.Size != size {
+		return fmt.Errorf("stored %d bytes of %d", info.Size, size)
+	}
+	switch tag := strings.ToLower(strings.Trim(info.ETag, `"`)); {
+	case info.SHA256 != "":
+		if !strings.EqualFold(info.SHA256, sum) {
+			return fmt.Errorf("stored SHA-256 %s, expected %s", info.SHA256, sum)
+		}
+	case len(tag) == md5.Size*2 && !strings.Contains(tag, "-"):
+		if tag != etag {
+			return fmt.Errorf("stored ETag %s, expected %s", info.ETag, etag)
+		}
+	default:
+		return fmt.Errorf("no checksum to verify %s", key)
+	}
+	return nil
+}
+
+// gzipFile returns file if it is compressed already, or a compressed copy in
+// the temporary directory, so neither an existing file.gz is overwritten nor
+// the copy matched by the pattern. cleanup removes the copy.
+func gzipFile(file string) (string, func(), error) {
+	if strings.HasSuffix(file, ".gz") {
+		return file, func() {}, nil
+	}
+	in, err := os.Open(file)
+	if err != nil {
+		return "", nil, err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp("", filepath.Base(file)+".*.gz")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(out.Name()) }
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return out.Name(), cleanup, nil
+}
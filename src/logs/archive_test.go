@@ -0,0 +1,197 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memStore struct {
+	objects map[string][]byte
+	fails   int
+	short   bool
+	corrupt bool
+	etag    bool // Return the MD5 as ETag instead of the SHA-256
+}
+
+func (m *memStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if m.fails > 0 {
+		m.fails--
+		return errors.New("unavailable")
+	}
+	b, err := ioutil.ReadAll(r)
+	if m.short {
+		b = b[:len(b)-1]
+	}
+	if m.corrupt {
+		b[0]++
+	}
+	m.objects[key] = b
+	return err
+}
+
+func (m *memStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	b := m.objects[key]
+	info := ObjectInfo{Size: int64(len(b))}
+	if m.etag {
+		sum := md5.Sum(b)
+		info.ETag = `"` + hex.EncodeToString(sum[:]) + `"`
+	} else {
+		sum := sha256.Sum256(b)
+		info.SHA256 = hex.EncodeToString(sum[:])
+	}
+	return info, nil
+}
+
+func TestArchiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "app.log.1"), []byte("first\n"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "app.log.2"), []byte("second\n"), 0644)
+
+	store := &memStore{objects: map[string][]byte{}, fails: 2}
+	a := &Archiver{
+		Store:    store,
+		Pattern:  filepath.Join(dir, "app.log.*"),
+		Prefix:   "host1/",
+		Manifest: filepath.Join(dir, "manifest"),
+		Backoff:  time.Millisecond,
+	}
+	keys, err := a.ArchiveNow(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "host1/app.log.1.gz host1/app.log.2.gz"
+	if got := strings.Join(keys, " "); got != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(store.objects["host1/app.log.1.gz"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadAll(zr); string(b) != "first\n" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", b, "first\n")
+	}
+	if left, _ := filepath.Glob(filepath.Join(dir, "app.log*")); len(left) != 0 {
+		t.Errorf("files left behind: %v", left)
+	}
+	m, _ := ioutil.ReadFile(a.Manifest)
+	if n := strings.Count(string(m), `"key":"host1/`); n != 2 {
+		t.Errorf("\nGot:\t%d manifest records\nExpect:\t2\n%s", n, m)
+	}
+}
+
+func TestArchiverVerifyFails(t *testing.T) {
+	var tests = []struct {
+		name  string
+		store *memStore
+	}{
+		{"short", &memStore{short: true}},
+		{"corrupt", &memStore{corrupt: true}},
+		{"corrupt etag", &memStore{corrupt: true, etag: true}},
+	}
+	for _, test := range tests {
+		dir, err := ioutil.TempDir("", "logs-archive")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "app.log.1.gz")
+		ioutil.WriteFile(file, []byte("compressed"), 0644)
+
+		test.store.objects = map[string][]byte{}
+		a := &Archiver{
+			Store:   test.store,
+			Pattern: filepath.Join(dir, "*.gz"),
+			Retries: 1,
+			Backoff: time.Millisecond,
+		}
+		if _, err := a.ArchiveNow(context.Background()); err == nil {
+			t.Errorf("%s: expected a verification error", test.name)
+		}
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("%s: file removed after failed upload: %v", test.name, err)
+		}
+	}
+}
+
+func TestArchiverETag(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.log.1.gz")
+	ioutil.WriteFile(file, []byte("compressed"), 0644)
+
+	a := &Archiver{
+		Store:   &memStore{objects: map[string][]byte{}, etag: true},
+		Pattern: filepath.Join(dir, "*.gz"),
+	}
+	if _, err := a.ArchiveNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("file kept after verified upload: %v", err)
+	}
+}
+
+func TestArchiverKeepsExistingGzip(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.log.1")
+	ioutil.WriteFile(file, []byte("first\n"), 0644)
+	ioutil.WriteFile(file+".gz", []byte("older upload"), 0644)
+
+	a := &Archiver{
+		Store:   &memStore{objects: map[string][]byte{}},
+		Pattern: filepath.Join(dir, "app.log.1"),
+	}
+	if _, err := a.ArchiveNow(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(file + ".gz"); string(b) != "older upload" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", b, "older upload")
+	}
+}
+
+func TestReopenableFileArchiver(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewReopenableFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	store := &memStore{objects: map[string][]byte{}}
+	f.SetArchiver(&Archiver{Store: store, Pattern: path + ".*"})
+	f.Write([]byte("before rotate\n"))
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.objects["app.log.1.gz"]; !ok {
+		t.Errorf("rotated file not uploaded: %v", store.objects)
+	}
+	if _, err := os.Stat(path + ".1"); !os.IsNotExist(err) {
+		t.Errorf("rotated file kept after upload: %v", err)
+	}
+}
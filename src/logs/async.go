@@ -0,0 +1,330 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// DropPolicy selects what happens to an entry when the async queue is full.
+type DropPolicy int
+
+const (
+	// Block waits until the queue has room, so no entry is lost.
+	Block DropPolicy = iota
+	// DropNewest discards the entry being written.
+	DropNewest
+	// DropOldest discards the oldest queued entry to make room.
+	DropOldest
+	// DropBelowLevel discards the entry being written if its level is below
+	// AsyncOptions.MinLevel, and waits for room otherwise.
+	DropBelowLevel
+)
+
+// AsyncOptions configures asynchronous output. See SetAsync.
+type AsyncOptions struct {
+	QueueSize      int           // Entries kept before the policy applies
+	Policy         DropPolicy    // Block if not set
+	MinLevel       level         // Used by DropBelowLevel
+	ReportInterval time.Duration // How often drops are reported, 10s if zero
+
+	// Context, if set, cancels the queue when it is done. Queued entries
+	// are discarded and new entries are dropped instead of waiting for
+	// room, so shutdown is not held up by a slow stream.
+	Context context.Context
+}
+
+// defaultCloseTimeout is the longest time Close waits by default.
+var defaultCloseTimeout = 5 * time.Second
+
+// asyncItem is queued output.
+type asyncItem struct {
+	cfg     outputConfig
+	streams []io.Writer
+	p       []byte
+	e       *Entry
+}
+
+// asyncQueue is the bounded queue between the logging calls and the worker
+// writing to the streams.
+type asyncQueue struct {
+	opts     AsyncOptions
+	mu       sync.Mutex
+	cond     *sync.Cond
+	items    []asyncItem
+	busy     bool   // The worker is writing an item
+	closed   bool   // No more items are accepted
+	canceled bool   // The context is done
+	dropped  uint64 // Total number of dropped entries
+	reported uint64 // Dropped count at the last report
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// push queues it, applying the drop policy if the queue is full. It must be
+// called without the logger lock held, since it waits for room under the Block
+// policy. It returns false if the queue is closed, in which case the caller
+// writes it.
+func (q *asyncQueue) push(it asyncItem) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) >= q.opts.QueueSize && !q.closed && !q.canceled {
+		lvl := LEVEL_PRINT
+		if it.e != nil {
+			lvl = it.e.Level
+		}
+		switch {
+		case q.opts.Policy == DropNewest,
+			q.opts.Policy == DropBelowLevel && lvl < q.opts.MinLevel:
+			q.dropped++
+			return true
+		case q.opts.Policy == DropOldest:
+			q.items = q.items[1:]
+			q.dropped++
+		default:
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+	if q.canceled {
+		q.dropped++
+		return true
+	}
+	q.items = append(q.items, it)
+	q.cond.Broadcast()
+	return true
+}
+
+// run writes the queued items until the queue is closed and empty.
+func (q *asyncQueue) run(l *Logger) {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.items) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		it := q.items[0]
+		q.items = q.items[1:]
+		q.busy = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		it.cfg.output(it.streams, nil, it.p, it.e)
+
+		q.mu.Lock()
+		q.busy = false
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// cancel discards the queued items and stops waiting for room.
+func (q *asyncQueue) cancel() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.canceled = true
+	q.dropped += uint64(len(q.items))
+	q.items = nil
+	q.cond.Broadcast()
+}
+
+// watch cancels the queue when ctx is done.
+func (q *asyncQueue) watch(ctx context.Context) {
+	select {
+	case <-ctx.Done():
+		q.cancel()
+	case <-q.stop:
+	}
+}
+
+// report logs a WARNING entry with the number of entries dropped since the
+// last report, if any, every ReportInterval.
+func (q *asyncQueue) report(l *Logger) {
+	interval := q.opts.ReportInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			q.mu.Lock()
+			n := q.dropped - q.reported
+			q.reported = q.dropped
+			q.mu.Unlock()
+			if n > 0 {
+				if e := l.WarningE(); e != nil {
+					e.Int64("dropped", int64(n)).Msg("async queue full, entries dropped")
+				}
+			}
+		case <-q.stop:
+			return
+		}
+	}
+}
+
+// flush waits until all queued items are written.
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) > 0 || q.busy {
+		q.cond.Wait()
+	}
+}
+
+// close writes the queued items and stops the worker.
+func (q *asyncQueue) close() {
+	close(q.stop)
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+	<-q.done
+}
+
+// SetAsync enables or disables asynchronous output of the standard logging
+// object. See (*Logger).SetAsync for details.
+func SetAsync(opts AsyncOptions) { std.SetAsync(opts) }
+
+// Flush waits until the queued output of the standard logging object is
+// written.
+func Flush() { std.Flush() }
+
+// Dropped returns the number of entries dropped by the standard logging
+// object because its async queue was full.
+func Dropped() uint64 { return std.Dropped() }
+
+// SetAsync makes the logging functions return once the entry is formatted
+// and queued, while a separate goroutine writes it to the streams. When the
+// queue holds opts.QueueSize entries, opts.Policy decides between latency and
+// completeness:
+//
+//	logr.SetAsync(logs.AsyncOptions{
+//		QueueSize: 1024,
+//		Policy:    logs.DropBelowLevel,
+//		MinLevel:  logs.LEVEL_WARNING,
+//	})
+//	defer logr.Flush()
+//
+// Dropped entries are counted, and the count since the last report is logged
+// as a WARNING entry every opts.ReportInterval. A QueueSize of zero or less
+// writes the queued entries and returns to synchronous output.
+func (l *Logger) SetAsync(opts AsyncOptions) {
+	l.mu.Lock()
+	q := l.async
+	l.async = nil
+	if opts.QueueSize > 0 {
+		l.async = &asyncQueue{opts: opts, stop: make(chan struct{}),
+			done: make(chan struct{})}
+		l.async.cond = sync.NewCond(&l.async.mu)
+		go l.async.run(l)
+		go l.async.report(l)
+		if opts.Context != nil {
+			go l.async.watch(opts.Context)
+		}
+	}
+	l.mu.Unlock()
+	if q != nil {
+		q.close()
+	}
+}
+
+// Flush waits until all queued output is written. It returns immediately if
+// async output is not enabled.
+func (l *Logger) Flush() {
+	l.mu.Lock()
+	q := l.async
+	l.mu.Unlock()
+	if q != nil {
+		q.flush()
+	}
+}
+
+// Dropped returns the number of entries dropped because the async queue was
+// full.
+func (l *Logger) Dropped() uint64 {
+	l.mu.Lock()
+	q := l.async
+	l.mu.Unlock()
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// SetCloseTimeout sets how long Close of the standard logging object waits.
+func SetCloseTimeout(d time.Duration) { std.SetCloseTimeout(d) }
+
+// Close writes the queued output of the standard logging object and closes
+// its streams. See (*Logger).Close for details.
+func Close() error { return std.Close() }
+
+// SetCloseTimeout sets how long Close waits for the queued output to be
+// written and the streams to close. The default is five seconds, and zero
+// waits as long as it takes.
+func (l *Logger) SetCloseTimeout(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closeTimeout = d
+}
+
+// Close ends async output after writing the queued entries, and closes every
+// stream that is an io.Closer, other than os.Stdout and os.Stderr. Errors
+// from closing streams are returned as a WriteError. If this takes longer
+// than the close timeout, Close returns an error without waiting further;
+// the contexts given to sinks such as NewOTLPExporterContext can be canceled
+// to abort their sends. The logging object should not be used after Close.
+func (l *Logger) Close() error {
+	l.ResumeOutput()
+	l.mu.Lock()
+	q := l.async
+	l.async = nil
+	streams := l.streams
+	timeout := l.closeTimeout
+	l.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		if q != nil {
+			q.close()
+		}
+		var errs WriteError
+		for i, w := range streams {
+			c, ok := w.(io.Closer)
+			if !ok || isStdStream(w) || containsStream(streams[:i], w) {
+				continue
+			}
+			if err := c.Close(); err != nil {
+				errs = append(errs, &StreamError{Stream: w, Err: err})
+			}
+		}
+		if len(errs) > 0 {
+			done <- errs
+			return
+		}
+		done <- nil
+	}()
+	if timeout <= 0 {
+		return <-done
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("logs: close did not complete within %v", timeout)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// gateWriter blocks writes until open is closed.
+type gateWriter struct {
+	syncBuffer
+	open chan struct{}
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	<-g.open
+	return g.syncBuffer.Write(p)
+}
+
+// queued returns the number of items waiting to be written.
+func (q *asyncQueue) queued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+func TestAsync(t *testing.T) {
+	var buf syncBuffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(0)
+	l.SetAsync(AsyncOptions{QueueSize: 4})
+	for i := 0; i < 10; i++ {
+		l.Printf("%d\n", i)
+	}
+	l.Flush()
+	if expect := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	l.SetAsync(AsyncOptions{})
+	l.Println("sync")
+	if !strings.HasSuffix(buf.String(), "9\nsync\n") {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), "...9\nsync\n")
+	}
+}
+
+func TestAsyncDropPolicies(t *testing.T) {
+	skipNoDebug(t)
+	tests := []struct {
+		policy DropPolicy
+		expect string
+	}{
+		{DropNewest, "first\nd1\nd2\n"},
+		{DropOldest, "first\ne\nd4\n"},
+		{DropBelowLevel, "first\nd1\nd2\ne\n"},
+	}
+	for _, test := range tests {
+		w := &gateWriter{open: make(chan struct{})}
+		l := New(LEVEL_DEBUG, w)
+		l.SetFlags(0)
+		l.SetAsync(AsyncOptions{QueueSize: 2, Policy: test.policy,
+			MinLevel: LEVEL_ERROR, ReportInterval: time.Hour})
+		l.Println("first")
+		// Wait until the worker is blocked writing the first entry.
+		for i := 0; i < 100 && l.async.queued() > 0; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		l.Debugln("d1")
+		l.Debugln("d2")
+		l.Debugln("d3")
+		if test.policy == DropBelowLevel {
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				close(w.open)
+			}()
+			l.Errorln("e")
+		} else {
+			l.Errorln("e")
+			l.Debugln("d4")
+			close(w.open)
+		}
+		l.Flush()
+		got := strings.Replace(w.String(), "[ERROR]    ", "", -1)
+		got = strings.Replace(got, "[DEBUG]    ", "", -1)
+		if got != test.expect {
+			t.Errorf("policy %d\nGot:\t%q\nExpect:\t%q\n", test.policy, got, test.expect)
+		}
+		if l.Dropped() == 0 {
+			t.Errorf("policy %d: no drops counted", test.policy)
+		}
+		l.SetAsync(AsyncOptions{})
+	}
+}
+
+func TestAsyncBlockReleasesLock(t *testing.T) {
+	w := &gateWriter{open: make(chan struct{})}
+	l := New(LEVEL_DEBUG, w)
+	l.SetFlags(0)
+	l.SetAsync(AsyncOptions{QueueSize: 1, Policy: Block, ReportInterval: time.Hour})
+	l.Println("first")
+	for i := 0; i < 100 && l.async.queued() > 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	l.Println("second")
+	blocked := make(chan struct{})
+	go func() {
+		l.Println("third")
+		close(blocked)
+	}()
+	// The producer waits for room in the queue without the logger lock.
+	done := make(chan struct{})
+	go func() {
+		l.SetLevel(LEVEL_INFO)
+		_ = l.Level()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetLevel blocked by a full queue")
+	}
+	close(w.open)
+	<-blocked
+	l.Flush()
+	if expect := "first\nsecond\nthird\n"; w.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", w.String(), expect)
+	}
+	l.SetAsync(AsyncOptions{})
+}
+
+func TestAsyncReport(t *testing.T) {
+	w := &gateWriter{open: make(chan struct{})}
+	l := New(LEVEL_DEBUG, w)
+	l.SetFlags(0)
+	l.SetAsync(AsyncOptions{QueueSize: 1, Policy: DropNewest,
+		ReportInterval: 20 * time.Millisecond})
+	for i := 0; i < 5; i++ {
+		l.Println("x")
+	}
+	close(w.open)
+	time.Sleep(50 * time.Millisecond)
+	l.SetAsync(AsyncOptions{})
+	if !strings.Contains(w.String(), "async queue full, entries dropped") {
+		t.Errorf("\nGot:\t%q\n", w.String())
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "io"
+
+// SetAutoFormat chooses the format of streams of the standard logging object
+// by destination. See (*Logger).SetAutoFormat.
+func SetAutoFormat(streams ...io.Writer) { std.SetAutoFormat(streams...) }
+
+// SetAutoFormat chooses the format of each of streams, or of all streams of l
+// if none are given, by its destination: terminals receive the colored output
+// of the template, other streams, such as files and pipes read by systemd or
+// docker, receive JSON lines from JSONEncoder. The choice is made once, when
+// SetAutoFormat is called.
+func (l *Logger) SetAutoFormat(streams ...io.Writer) {
+	if len(streams) == 0 {
+		streams = l.Streams()
+	}
+	for _, w := range streams {
+		if isTerminal(w) {
+			l.SetStreamEncoder(w, nil)
+		} else {
+			l.SetStreamEncoder(w, JSONEncoder{})
+		}
+	}
+}
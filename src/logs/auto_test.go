@@ -0,0 +1,27 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSetAutoFormat(t *testing.T) {
+	var term, file bytes.Buffer
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(w io.Writer) bool { return w == &term }
+	logr := New(LEVEL_DEBUG, &term, &file)
+	logr.SetFlags(Llabel)
+	logr.SetAutoFormat()
+	logr.Infoln("started")
+	if expect := "[INFO]     started\n"; term.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", term.String(), expect)
+	}
+	if !strings.HasPrefix(file.String(), `{"level":"info","msg":"started","time":`) {
+		t.Errorf("\nGot:\t%q\n", file.String())
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aybabtme/rgbterm"
+)
+
+var (
+	// bannerRule is the character used to draw banner rules.
+	bannerRule = "="
+
+	// bannerColor is the color of banner rules, the same as the seperator.
+	bannerColor = [3]uint8{0, 255, 135}
+)
+
+// defaultBannerWidth returns the width of the terminal from the COLUMNS
+// environment variable, or 80 if it is not set.
+func defaultBannerWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// banner returns a rule width characters wide with text centered in it.
+func banner(text string, width int) string {
+	if text != "" {
+		text = " " + text + " "
+	}
+	n := width - utf8.RuneCountInString(text)
+	if n < 2 {
+		n = 2
+	}
+	left := strings.Repeat(bannerRule, n/2)
+	right := strings.Repeat(bannerRule, n-n/2)
+	return rgbterm.FgString(left, bannerColor[0], bannerColor[1], bannerColor[2]) +
+		text + rgbterm.FgString(right, bannerColor[0], bannerColor[1], bannerColor[2])
+}
+
+// Banner writes a full width rule with text centered in it using the standard
+// logging object. See (*Logger).Banner for details.
+func Banner(text string) {
+	std.Fprint(std.Flags(), LEVEL_PRINT, 2, banner(text, std.BannerWidth())+"\n", nil)
+}
+
+// BannerWidth returns the width of banners written by the standard logging
+// object.
+func BannerWidth() int { return std.BannerWidth() }
+
+// SetBannerWidth sets the width of banners written by the standard logging
+// object.
+func SetBannerWidth(width int) { std.SetBannerWidth(width) }
+
+// Banner writes a rule with text centered in it, for example to separate the
+// phases of a program. The rule is colored like the seperator when the Lcolor
+// flag is used. Banners are written regardless of the logging level.
+func (l *Logger) Banner(text string) {
+	l.Fprint(l.Flags(), LEVEL_PRINT, 2, banner(text, l.BannerWidth())+"\n", nil)
+}
+
+// BannerWidth returns the width of banners. Unless set with SetBannerWidth,
+// the width of the terminal in the COLUMNS environment variable is used, or
+// 80 if it is not set.
+func (l *Logger) BannerWidth() int {
+	if l.bannerWidth > 0 {
+		return l.bannerWidth
+	}
+	return defaultBannerWidth()
+}
+
+// SetBannerWidth sets the width of banners. Zero restores the default.
+func (l *Logger) SetBannerWidth(width int) { l.bannerWidth = width }
@@ -0,0 +1,22 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBanner(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_CRITICAL, &buf)
+	logr.SetFlags(0)
+	logr.SetBannerWidth(20)
+	logr.Banner("DEPLOY")
+	logr.Banner("")
+	expect := "====== DEPLOY ======\n====================\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
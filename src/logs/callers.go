@@ -0,0 +1,104 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// IncludeCallers only allows output from callers matching patterns in the
+// standard logging object. See (*Logger).IncludeCallers.
+func IncludeCallers(patterns ...string) error { return std.IncludeCallers(patterns...) }
+
+// ExcludeCallers suppresses output from callers matching patterns in the
+// standard logging object. See (*Logger).ExcludeCallers.
+func ExcludeCallers(patterns ...string) error { return std.ExcludeCallers(patterns...) }
+
+// IncludeCallers only allows output from callers matching one of patterns.
+// Patterns are matched like those of ExcludeCallers, which is applied after
+// IncludeCallers. Calling IncludeCallers without arguments allows output from
+// all callers again.
+func (l *Logger) IncludeCallers(patterns ...string) error {
+	if err := checkCallerPatterns(patterns); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.includeCallers = patterns
+	return nil
+}
+
+// ExcludeCallers suppresses output from callers matching one of patterns, so
+// noisy third-party or generated code can be silenced without changing it:
+//
+//	logr.ExcludeCallers("vendor/*", "*_generated.go", "github.com/noisy/*")
+//
+// A pattern uses the syntax of path.Match and is matched against the source
+// file and the package path of the caller. A pattern of n elements matches if
+// it matches any n consecutive elements, so "*_generated.go" matches the
+// file name in any directory and "vendor/*" matches anything in a vendor
+// directory. Calling ExcludeCallers without arguments removes the rules.
+func (l *Logger) ExcludeCallers(patterns ...string) error {
+	if err := checkCallerPatterns(patterns); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.excludeCallers = patterns
+	return nil
+}
+
+func checkCallerPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil || p == "" {
+			return fmt.Errorf("logs: invalid caller pattern %q", p)
+		}
+	}
+	return nil
+}
+
+// callerEnabled reports whether output from the function named fn in file is
+// allowed by the caller rules. It must be called with the lock held.
+func (l *Logger) callerEnabled(file, fn string) bool {
+	pkg := funcPackage(fn)
+	if len(l.includeCallers) > 0 && !matchCaller(l.includeCallers, file, pkg) {
+		return false
+	}
+	return !matchCaller(l.excludeCallers, file, pkg)
+}
+
+// matchCaller reports whether one of patterns matches file or pkg.
+func matchCaller(patterns []string, file, pkg string) bool {
+	for _, p := range patterns {
+		if matchElements(p, file) || (pkg != "" && matchElements(p, pkg)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchElements reports whether pattern matches consecutive elements of the
+// slash separated name.
+func matchElements(pattern, name string) bool {
+	n := strings.Count(pattern, "/") + 1
+	elems := strings.Split(strings.Trim(name, "/"), "/")
+	for i := 0; i+n <= len(elems); i++ {
+		if ok, _ := path.Match(pattern, strings.Join(elems[i:i+n], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// funcPackage returns the package path of the function named fn, for example
+// "github.com/a/b" for "github.com/a/b.(*T).Method".
+func funcPackage(fn string) string {
+	slash := strings.LastIndexByte(fn, '/') + 1
+	if i := strings.IndexByte(fn[slash:], '.'); i >= 0 {
+		return fn[:slash+i]
+	}
+	return fn
+}
@@ -0,0 +1,57 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestExcludeCallers(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+
+	if err := logr.ExcludeCallers("*_test.go"); err != nil {
+		t.Fatal(err)
+	}
+	logr.Debugln("excluded")
+	logr.ExcludeCallers()
+	logr.IncludeCallers("vendor/*")
+	logr.Debugln("not included")
+	logr.IncludeCallers("logs")
+	logr.Debugln("included")
+	logr.IncludeCallers()
+	logr.Debugln("all")
+
+	if expect := "included\nall\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if err := logr.ExcludeCallers("["); err == nil {
+		t.Errorf("\nGot:\tnil\nExpect:\terror\n")
+	}
+}
+
+func TestMatchCaller(t *testing.T) {
+	tests := []struct {
+		pattern, file, pkg string
+		match              bool
+	}{
+		{"vendor/*", "/src/app/vendor/x/y.go", "app/vendor/x", true},
+		{"*_generated.go", "/src/app/api/z_generated.go", "app/api", true},
+		{"*_generated.go", "/src/app/api/z.go", "app/api", false},
+		{"github.com/noisy/*", "/go/pkg/mod/github.com/noisy@v1/a.go", "github.com/noisy/a", true},
+		{"app/api", "/src/app/api/z.go", "app/api", true},
+		{"app/db", "/src/app/api/z.go", "app/api", false},
+	}
+	for _, tt := range tests {
+		if got := matchCaller([]string{tt.pattern}, tt.file, tt.pkg); got != tt.match {
+			t.Errorf("\nGot:\t%q %v\nExpect:\t%q %v\n", tt.pattern, got, tt.pattern, tt.match)
+		}
+	}
+	if pkg := funcPackage("github.com/a/b.(*T).Method"); pkg != "github.com/a/b" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", pkg, "github.com/a/b")
+	}
+}
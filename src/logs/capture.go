@@ -0,0 +1,33 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+// CaptureCmd writes the output of cmd to the standard logging object. See
+// (*Logger).CaptureCmd for details.
+func CaptureCmd(cmd *exec.Cmd, stdoutLevel, stderrLevel level) {
+	std.CaptureCmd(cmd, stdoutLevel, stderrLevel)
+}
+
+// CaptureCmd sets the standard output and error of cmd so that every line the
+// command writes becomes an entry, at stdoutLevel for standard output and
+// stderrLevel for standard error. Each entry is prefixed with the base name of
+// the command:
+//
+//	cmd := exec.Command("git", "fetch")
+//	logr.CaptureCmd(cmd, logs.LEVEL_INFO, logs.LEVEL_WARNING)
+//	err := cmd.Run()
+//
+// CaptureCmd must be called before the command is started. Output not ending
+// in a newline is written once the line is complete, or after the flush
+// timeout of a LineWriter.
+func (l *Logger) CaptureCmd(cmd *exec.Cmd, stdoutLevel, stderrLevel level) {
+	prefix := filepath.Base(cmd.Path) + ": "
+	cmd.Stdout = l.NewLineWriter(stdoutLevel, prefix)
+	cmd.Stderr = l.NewLineWriter(stderrLevel, prefix)
+}
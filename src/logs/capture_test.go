@@ -0,0 +1,38 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCaptureCmd(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip(err)
+	}
+	var buf bytes.Buffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(Llabel)
+	cmd := exec.Command(sh, "-c", "echo one; echo two >&2; printf 'three\\r\\n'")
+	l.CaptureCmd(cmd, LEVEL_INFO, LEVEL_ERROR)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	// Standard output and error are read separately, so only the order of
+	// the lines of each is known.
+	out := buf.String()
+	for _, expect := range []string{"[INFO]     sh: one\n", "[ERROR]    sh: two\n",
+		"[INFO]     sh: three\n"} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", out, expect)
+		}
+	}
+	if strings.Index(out, "one") > strings.Index(out, "three") {
+		t.Errorf("\nGot:\t%q\nExpect:\tone before three\n", out)
+	}
+}
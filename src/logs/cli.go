@@ -0,0 +1,23 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "os"
+
+// NewCLI returns a logging object for command line tools following the Unix
+// conventions. Output of the Print functions and INFO entries go to
+// os.Stdout as plain messages, so the output of the tool can be piped to
+// other programs. DEBUG, WARNING, ERROR, and CRITICAL entries go to os.Stderr
+// with labels, colored if os.Stderr is a terminal. The level is LEVEL_INFO.
+func NewCLI() *Logger {
+	l := New(LEVEL_INFO, os.Stdout, os.Stderr)
+	l.SetFlags(Lcolor | Llabel | LshortLabel)
+	l.SetStreamEncoder(os.Stdout, MessageEncoder{})
+	l.SetStreamLevels(os.Stdout, LEVEL_INFO, LEVEL_PRINT)
+	l.SetStreamLevels(os.Stderr, LEVEL_DEBUG, LEVEL_WARNING, LEVEL_ERROR, LEVEL_CRITICAL)
+	if !isTerminal(os.Stderr) {
+		l.SetColorMode(os.Stderr, ColorNone)
+	}
+	return l
+}
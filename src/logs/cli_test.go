@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestNewCLI(t *testing.T) {
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oStdout, oStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = outW, errW
+	logr := NewCLI()
+	logr.Println("result")
+	logr.WithField("files", 3).Infoln("copied")
+	logr.Debugln("not shown")
+	logr.Warningln("disk almost full")
+	os.Stdout, os.Stderr = oStdout, oStderr
+	outW.Close()
+	errW.Close()
+
+	stdout, _ := ioutil.ReadAll(outR)
+	stderr, _ := ioutil.ReadAll(errR)
+	if expect := "result\ncopied files=3\n"; string(stdout) != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", stdout, expect)
+	}
+	if expect := "[WARN] disk almost full\n"; string(stderr) != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", stderr, expect)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type closeRecorder struct {
+	syncBuffer
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestClose(t *testing.T) {
+	w := &closeRecorder{}
+	l := New(LEVEL_DEBUG, w, w)
+	l.SetFlags(0)
+	l.SetAsync(AsyncOptions{QueueSize: 8})
+	l.Println("queued")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.closed != 1 || w.String() != "queued\nqueued\n" {
+		t.Errorf("\nGot:\t%d, %q\nExpect:\t1, %q\n", w.closed, w.String(), "queued\nqueued\n")
+	}
+}
+
+// closeFunc is a closer that cannot be used as a map key.
+type closeFunc struct {
+	*syncBuffer
+	close func() error
+}
+
+func (c closeFunc) Close() error { return c.close() }
+
+func TestCloseUncomparable(t *testing.T) {
+	closed := 0
+	w := closeFunc{&syncBuffer{}, func() error { closed++; return nil }}
+	l := New(LEVEL_DEBUG, w)
+	if err := l.Close(); err != nil || closed != 1 {
+		t.Errorf("Close() = %v, closed %d times; want: nil, 1", err, closed)
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	w := &gateWriter{open: make(chan struct{})}
+	defer close(w.open)
+	l := New(LEVEL_DEBUG, w)
+	l.SetAsync(AsyncOptions{QueueSize: 8})
+	l.SetCloseTimeout(20 * time.Millisecond)
+	l.Println("stuck")
+	start := time.Now()
+	if err := l.Close(); err == nil || !strings.Contains(err.Error(), "within") {
+		t.Errorf("\nGot:\t%v\nExpect:\tclose did not complete within 20ms\n", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Close took %v", d)
+	}
+}
+
+func TestAsyncContext(t *testing.T) {
+	w := &gateWriter{open: make(chan struct{})}
+	defer close(w.open)
+	ctx, cancel := context.WithCancel(context.Background())
+	l := New(LEVEL_DEBUG, w)
+	l.SetAsync(AsyncOptions{QueueSize: 1, Context: ctx})
+	l.Println("one")
+	l.Println("two")
+	cancel()
+	// Blocks without the context since the queue is full.
+	l.Println("three")
+	if n := l.Dropped(); n == 0 {
+		t.Errorf("\nGot:\t%d dropped\nExpect:\tdropped entries\n", n)
+	}
+}
+
+func TestOTLPExporterContext(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	x := NewOTLPExporterContext(ctx, srv.URL, "api")
+	x.Write([]byte(`{}`))
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if err := x.Close(); err == nil {
+		t.Error("expected an error from the canceled request")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Close took %v", d)
+	}
+}
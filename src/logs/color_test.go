@@ -4,7 +4,10 @@
 package logs
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"testing"
 
 	"github.com/aybabtme/rgbterm"
@@ -31,3 +34,116 @@ func TestColors(t *testing.T) {
 		}
 	}
 }
+
+func TestColorLabelsOnly(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LcolorLabelsOnly | Llabel | Lseperator | Lindent | LshowIndent)
+	text := rgbterm.FgString("own colors", 0, 0, 255)
+	logr.Warningln(text)
+	logr.SetIndent(1).Warningln("indented")
+	expect := rgbterm.FgString("[WARNING] ", 255, 255, 135) + " " +
+		defaultSeperatorColor + " " + text + "\n" +
+		rgbterm.FgString("[WARNING] ", 255, 255, 135) + " " +
+		defaultSeperatorColor + " |...indented\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		style  Style
+		output string
+	}{
+		{Style{}, "text"},
+		{Style{Fg: RGB(255, 0, 0)}, "\x1b[38;2;255;0;0mtext\x1b[0;00m"},
+		{Style{Bg: RGB(0, 0, 255), Attrs: AttrBold},
+			"\x1b[1m\x1b[48;2;0;0;255mtext\x1b[0;00m"},
+		{Style{Fg: RGB(255, 255, 255), Bg: RGB(255, 0, 0)},
+			"\x1b[38;2;255;255;255;48;2;255;0;0mtext\x1b[0;00m"},
+		{Style{Attrs: AttrBold | AttrUnderline}, "\x1b[1;4mtext\x1b[0m"},
+	}
+	for i, v := range tests {
+		if out := v.style.Render("text"); out != v.output {
+			t.Errorf("Test Number: %d\nGot:\t%q\nExpect:\t%q\n", i, out, v.output)
+		}
+	}
+}
+
+func TestLabelStyle(t *testing.T) {
+	defer SetLabelStyle(LEVEL_CRITICAL, LabelStyle(LEVEL_CRITICAL))
+	SetLabelStyle(LEVEL_CRITICAL, Style{Fg: RGB(0, 255, 0), Bg: RGB(255, 0, 0),
+		Attrs: AttrBold})
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lcolor | Llabel)
+	logr.SetTemplate("{{.LogLabel}} {{bold .Text}}{{.Level | fg 0 0 255}}")
+	logr.Criticalln("on fire")
+	expect := "\x1b[1m\x1b[38;5;46;48;5;196m[CRITICAL]\x1b[0;00m" +
+		" \x1b[1mon fire\x1b[0m\n" + rgbterm.FgString("CRITICAL", 0, 0, 255)
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestColorMode(t *testing.T) {
+	text := Style{Fg: RGB(255, 0, 0), Bg: RGB(0, 0, 255)}.Render("a") +
+		rgbterm.FgString("b", 0, 255, 0)
+	tests := []struct {
+		mode   ColorMode
+		output string
+	}{
+		{ColorTrue, text},
+		{Color256, "\x1b[38;5;196;48;5;21ma\x1b[0;00m\x1b[38;5;46mb\x1b[0;00m"},
+		{Color16, "\x1b[91;44ma\x1b[0;00m\x1b[92mb\x1b[0;00m"},
+		{ColorNone, "ab"},
+	}
+	for i, v := range tests {
+		var buf bytes.Buffer
+		logr := New(LEVEL_DEBUG, &buf)
+		logr.SetFlags(Lcolor)
+		logr.SetColorMode(&buf, v.mode)
+		logr.Print(text)
+		if buf.String() != v.output {
+			t.Errorf("Test Number: %d\nGot:\t%q\nExpect:\t%q\n", i, buf.String(), v.output)
+		}
+	}
+}
+
+func TestColorModeRedirected(t *testing.T) {
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(io.Writer) bool { return false }
+	var c outputConfig
+	if m := c.colorMode(os.Stdout); m != Color256 {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", m, Color256)
+	}
+	isTerminal = func(io.Writer) bool { return true }
+	if m := c.colorMode(os.Stdout); m != detectColorMode() {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", m, detectColorMode())
+	}
+}
+
+func TestTheme(t *testing.T) {
+	defer SetTheme(CurrentTheme())
+	if err := SetThemeNamed("colorblind"); err != nil {
+		t.Fatal(err)
+	}
+	theme := ColorblindTheme
+	theme[LEVEL_INFO] = Style{Fg: RGB(0, 0, 255)}
+	SetTheme(theme)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lcolor | Llabel | LshortLabel)
+	logr.SetColorMode(&buf, ColorTrue)
+	logr.Infoln("info")
+	logr.Criticalln("critical")
+	expect := "\x1b[38;2;0;0;255m[INFO]\x1b[0;00m info\n" +
+		"\x1b[1;4m\x1b[38;2;213;94;0m[CRIT]\x1b[0;00m critical\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if err := SetThemeNamed("sepia"); err == nil {
+		t.Error("Expected error for unknown theme")
+	}
+}
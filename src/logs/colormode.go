@@ -0,0 +1,220 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aybabtme/rgbterm"
+)
+
+// ColorMode is the kind of color escape sequences a stream supports. Styles
+// are rendered with 24-bit colors, which are converted to the closest colors
+// of the mode of each stream when written.
+type ColorMode int
+
+const (
+	// ColorAuto detects the mode of os.Stdout and os.Stderr from the
+	// COLORTERM and TERM environment variables, and uses Color256 for other
+	// streams, or ColorNone if the LnoFileAnsi flag is set.
+	ColorAuto ColorMode = iota
+
+	// ColorNone removes all escape sequences.
+	ColorNone
+
+	// Color16 uses the 16 standard terminal colors.
+	Color16
+
+	// Color256 uses the 256 color palette of xterm.
+	Color256
+
+	// ColorTrue uses 24-bit colors, 38;2;r;g;b.
+	ColorTrue
+)
+
+// sgrRegexp matches SGR escape sequences and captures their parameters.
+var sgrRegexp = regexp.MustCompile("\x1b\\[([\\d;]*)m")
+
+// ansi16 is the xterm palette of the 16 standard colors.
+var ansi16 = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// basicTerms are the values of TERM of terminals limited to 16 colors. Most
+// other terminals, including those reporting "xterm", support 256 colors.
+var basicTerms = map[string]bool{
+	"linux": true, "vt100": true, "vt220": true, "ansi": true, "cons25": true,
+	"cygwin": true,
+}
+
+var (
+	detectOnce sync.Once
+	detected   ColorMode
+)
+
+// detectColorMode returns the color mode of the terminal described by the
+// environment.
+func detectColorMode() ColorMode {
+	detectOnce.Do(func() {
+		term := os.Getenv("TERM")
+		switch ct := os.Getenv("COLORTERM"); {
+		case ct == "truecolor" || ct == "24bit":
+			detected = ColorTrue
+		case term == "dumb":
+			detected = ColorNone
+		case basicTerms[term] || strings.HasSuffix(term, "-16color"):
+			detected = Color16
+		default:
+			detected = Color256
+		}
+	})
+	return detected
+}
+
+// SetColorMode sets the color mode of stream for the standard logging
+// object.
+func SetColorMode(stream io.Writer, mode ColorMode) { std.SetColorMode(stream, mode) }
+
+// SetColorMode sets the color mode used for stream, overriding the detection
+// and the LnoFileAnsi flag. ColorAuto restores the default.
+func (l *Logger) SetColorMode(stream io.Writer, mode ColorMode) {
+	if !comparable(stream) {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The map is replaced rather than modified since output in progress
+	// may still use the old one.
+	modes := make(map[io.Writer]ColorMode, len(l.colorModes)+1)
+	for k, v := range l.colorModes {
+		modes[k] = v
+	}
+	if mode == ColorAuto {
+		delete(modes, stream)
+	} else {
+		modes[stream] = mode
+	}
+	l.colorModes = modes
+}
+
+// colorMode returns the color mode of w.
+func (c outputConfig) colorMode(w io.Writer) ColorMode {
+	if len(c.colorModes) > 0 && comparable(w) {
+		if m, ok := c.colorModes[w]; ok {
+			return m
+		}
+	}
+	std := isStdStream(w)
+	if c.flags&LnoFileAnsi != 0 && !std {
+		return ColorNone
+	}
+	// The environment only describes the terminal. Redirected output is
+	// written like that of other streams.
+	if std && isTerminal(w) {
+		return detectColorMode()
+	}
+	return Color256
+}
+
+// recolor converts the color escape sequences in p to mode.
+func recolor(p []byte, mode ColorMode) []byte {
+	if bytes.IndexByte(p, 0x1b) < 0 || mode == ColorTrue {
+		return p
+	}
+	if mode == ColorNone {
+		return stripAnsiByte(p)
+	}
+	return sgrRegexp.ReplaceAllFunc(p, func(seq []byte) []byte {
+		params := strings.Split(string(seq[2:len(seq)-1]), ";")
+		out := make([]string, 0, len(params))
+		for i := 0; i < len(params); i++ {
+			p := params[i]
+			if (p != "38" && p != "48") || i+1 >= len(params) {
+				out = append(out, p)
+				continue
+			}
+			var rgb [3]uint8
+			switch {
+			case params[i+1] == "2" && i+4 < len(params):
+				for j := range rgb {
+					v, _ := strconv.Atoi(params[i+2+j])
+					rgb[j] = uint8(v)
+				}
+				i += 4
+			case params[i+1] == "5" && i+2 < len(params):
+				n, _ := strconv.Atoi(params[i+2])
+				i += 2
+				if mode == Color256 {
+					out = append(out, p, "5", strconv.Itoa(n))
+					continue
+				}
+				rgb = xterm256RGB(n)
+			default:
+				out = append(out, p)
+				continue
+			}
+			if mode == Color256 {
+				out = append(out, p, "5", rgb256(rgb))
+			} else {
+				out = append(out, ansi16Code(rgb, p == "48"))
+			}
+		}
+		return []byte("\x1b[" + strings.Join(out, ";") + "m")
+	})
+}
+
+// rgb256 returns the xterm 256 color palette index closest to rgb, as chosen
+// by rgbterm.
+func rgb256(rgb [3]uint8) string {
+	s := rgbterm.FgString("x", rgb[0], rgb[1], rgb[2])
+	s = s[:strings.IndexByte(s, 'x')]
+	return strings.TrimSuffix(s[strings.LastIndexByte(s, ';')+1:], "m")
+}
+
+// xterm256RGB returns the color of the xterm 256 color palette index n.
+func xterm256RGB(n int) [3]uint8 {
+	switch {
+	case n < 16:
+		return ansi16[n&15]
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return [3]uint8{levels[n/36], levels[n/6%6], levels[n%6]}
+	default:
+		g := uint8(8 + 10*(n-232))
+		return [3]uint8{g, g, g}
+	}
+}
+
+// ansi16Code returns the SGR parameter of the standard color closest to rgb.
+func ansi16Code(rgb [3]uint8, bg bool) string {
+	best, dist := 0, -1
+	for i, c := range ansi16 {
+		d := 0
+		for j := range c {
+			x := int(c[j]) - int(rgb[j])
+			d += x * x
+		}
+		if dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+	code := 30 + best
+	if best >= 8 {
+		code = 90 + best - 8
+	}
+	if bg {
+		code += 10
+	}
+	return strconv.Itoa(code)
+}
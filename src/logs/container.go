@@ -0,0 +1,23 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "os"
+
+// ContainerEncoder is the encoder used by NewContainerLogger. Its keys match
+// the parsers commonly used with fluent-bit and Kubernetes.
+var ContainerEncoder = JSONEncoder{MessageKey: "message", CallerKey: "caller", UTC: true}
+
+// NewContainerLogger returns a logging object for services running in docker
+// or Kubernetes. Entries at LEVEL_INFO and above are written to os.Stdout as
+// JSON lines without escape sequences, with the keys "time", an RFC 3339 UTC
+// time with nanoseconds, "level", "message", and "caller":
+//
+//	{"caller":"server/main.go:42","level":"info","message":"listening","time":"2009-11-10T23:00:00.123456789Z"}
+func NewContainerLogger() *Logger {
+	l := New(LEVEL_INFO, os.Stdout)
+	l.SetFlags(0)
+	l.SetStreamEncoder(os.Stdout, ContainerEncoder)
+	return l
+}
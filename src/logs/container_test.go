@@ -0,0 +1,41 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewContainerLogger(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	oStdout := os.Stdout
+	os.Stdout = w
+	logr := NewContainerLogger()
+	logr.Debugln("not shown")
+	logr.WithField("port", 8080).Infoln("listening")
+	os.Stdout = oStdout
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatalf("%v: %q", err, out)
+	}
+	if m["level"] != "info" || m["message"] != "listening" || m["port"] != 8080.0 ||
+		!strings.Contains(m["caller"].(string), "/container_test.go:") {
+		t.Errorf("\nGot:\t%q\n", out)
+	}
+	ts, err := time.Parse(time.RFC3339Nano, m["time"].(string))
+	if err != nil || ts.Location() != time.UTC {
+		t.Errorf("Time: %v %v", m["time"], err)
+	}
+}
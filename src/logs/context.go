@@ -0,0 +1,128 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// FieldRequestID is the key of the request id field. The value is also
+// available to templates as {{.RequestID}}.
+const FieldRequestID = "request_id"
+
+// requestIDKey is the context key of request ids.
+type requestIDKey struct{}
+
+// WithRequestID returns ctx if it already carries a request id, or a copy of
+// ctx carrying a new random request id. Events with the context are stamped
+// with the id.
+func WithRequestID(ctx context.Context) context.Context {
+	if RequestID(ctx) != "" {
+		return ctx
+	}
+	return ContextWithRequestID(ctx, newRequestID())
+}
+
+// ContextWithRequestID returns a copy of ctx carrying id, for example an id
+// received from another service.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id carried by ctx, or an empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID returns 16 random hex characters.
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// SetTraceExtractor sets the function used by the standard logging object to
+// find the trace and span ids in a context. See (*Logger).SetTraceExtractor.
+func SetTraceExtractor(f func(ctx context.Context) (traceID, spanID string)) {
+	std.SetTraceExtractor(f)
+}
+
+// SetSpanEventHook sets a function called with every event written by the
+// standard logging object that has a context. See (*Logger).SetSpanEventHook.
+func SetSpanEventHook(f func(ctx context.Context, e Entry)) { std.SetSpanEventHook(f) }
+
+// SetTraceExtractor sets the function used to find the trace and span ids in
+// the context of an event. The ids are added as the trace_id and span_id
+// fields. Empty ids are not added. To use OpenTelemetry spans:
+//
+//	logr.SetTraceExtractor(func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsValid() {
+//			return "", ""
+//		}
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	})
+func (l *Logger) SetTraceExtractor(f func(ctx context.Context) (traceID, spanID string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.traceExtractor = f
+}
+
+// SetSpanEventHook sets a function called with every event that has a
+// context after it is written, for example to add the entry as an event of
+// the active span:
+//
+//	logr.SetSpanEventHook(func(ctx context.Context, e logs.Entry) {
+//		trace.SpanFromContext(ctx).AddEvent(e.Message)
+//	})
+func (l *Logger) SetSpanEventHook(f func(ctx context.Context, e Entry)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spanEventHook = f
+}
+
+// Ctx sets the context of the event. The request id of the context is added
+// as a field. If the logging object has a trace extractor, the trace and span
+// ids of the context are added as fields.
+func (e *Event) Ctx(ctx context.Context) *Event {
+	if e == nil {
+		return nil
+	}
+	e.ctx = ctx
+	if id := RequestID(ctx); id != "" {
+		e.add(FieldRequestID, id)
+	}
+	e.logr.mu.Lock()
+	extract := e.logr.traceExtractor
+	e.logr.mu.Unlock()
+	if extract == nil {
+		return e
+	}
+	traceID, spanID := extract(ctx)
+	if traceID != "" {
+		e.add(FieldTraceID, traceID)
+	}
+	if spanID != "" {
+		e.add(FieldSpanID, spanID)
+	}
+	return e
+}
+
+// spanEvent calls the span event hook for an event with a context.
+func (e *Event) spanEvent(msg string) {
+	if e.ctx == nil {
+		return
+	}
+	e.logr.mu.Lock()
+	hook := e.logr.spanEventHook
+	e.logr.mu.Unlock()
+	if hook != nil {
+		hook(e.ctx, Entry{Time: time.Now(), Level: e.level, Message: msg,
+			Fields: e.fields})
+	}
+}
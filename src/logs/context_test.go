@@ -0,0 +1,41 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type spanKey struct{}
+
+func TestEventCtxTrace(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.SetTraceExtractor(func(ctx context.Context) (string, string) {
+		if ids, ok := ctx.Value(spanKey{}).([2]string); ok {
+			return ids[0], ids[1]
+		}
+		return "", ""
+	})
+	var events []string
+	logr.SetSpanEventHook(func(ctx context.Context, e Entry) {
+		events = append(events, e.Message)
+	})
+
+	ctx := context.WithValue(context.Background(), spanKey{}, [2]string{"abc", "def"})
+	logr.InfoE().Ctx(ctx).Msg("traced")
+	logr.InfoE().Ctx(context.Background()).Msg("untraced")
+	logr.InfoE().Msg("no context")
+
+	expect := "traced trace_id=abc span_id=def\nuntraced\nno context\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if len(events) != 2 || events[0] != "traced" {
+		t.Errorf("Span events = %q; want: [traced untraced]", events)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// exit is called by the Fatal functions. It is replaced in tests.
+var exit = os.Exit
+
+// ring holds the most recent entries written by a logging object.
+type ring struct {
+	entries []string
+	next    int
+	full    bool
+}
+
+func newRing(size int) *ring { return &ring{entries: make([]string, size)} }
+
+// add stores text, replacing the oldest entry if the ring is full.
+func (r *ring) add(text string) {
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = text
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// all returns the stored entries from oldest to newest.
+func (r *ring) all() []string {
+	if !r.full {
+		return append([]string(nil), r.entries[:r.next]...)
+	}
+	return append(append([]string(nil), r.entries[r.next:]...),
+		r.entries[:r.next]...)
+}
+
+// writeCrashReport writes the recent entries, the stacks of all goroutines,
+// and the build info to a timestamped file in the crash directory. Nothing is
+// done if a crash directory is not set. The path of the report is returned.
+func (l *Logger) writeCrashReport() (string, error) {
+	l.mu.Lock()
+	dir := l.crashDir
+	var recent []string
+	if l.recent != nil {
+		recent = l.recent.all()
+	}
+	l.mu.Unlock()
+	if dir == "" {
+		return "", nil
+	}
+
+	stack := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(stack, true)
+		if n < len(stack) {
+			stack = stack[:n]
+			break
+		}
+		stack = make([]byte, len(stack)*2)
+	}
+
+	var report strings.Builder
+	now := time.Now()
+	fmt.Fprintf(&report, "Crash report %s\n\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&report, "Last %d entries:\n\n", len(recent))
+	for _, e := range recent {
+		report.WriteString(stripAnsi(e))
+		if !strings.HasSuffix(e, "\n") {
+			report.WriteString("\n")
+		}
+	}
+	report.WriteString("\nGoroutines:\n\n")
+	report.Write(stack)
+	report.WriteString("\n\nBuild info:\n\n")
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		report.WriteString(bi.String())
+	} else {
+		report.WriteString("not available\n")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s-%d.log",
+		now.Format("20060102T150405.000000000"), os.Getpid()))
+	return path, os.WriteFile(path, []byte(report.String()), 0644)
+}
@@ -0,0 +1,57 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	r := newRing(2)
+	r.add("a")
+	r.add("b")
+	r.add("c")
+	if out := strings.Join(r.all(), ""); out != "bc" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", out, "bc")
+	}
+}
+
+func TestFatalCrashReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go_test_crash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var code int
+	defer func(f func(int)) { exit = f }(exit)
+	exit = func(c int) { code = c }
+
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	logr.SetCrashDir(dir, 1)
+	logr.Infoln("First entry")
+	logr.Fatalln("Last entry")
+
+	if code != 1 {
+		t.Errorf("exit code = %d; want: 1", code)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "crash-*.log"))
+	if len(files) != 1 {
+		t.Fatalf("Found %d crash reports; want: 1", len(files))
+	}
+	report, _ := ioutil.ReadFile(files[0])
+	if !strings.Contains(string(report), "Last 1 entries:\n\n[CRITICAL] Last entry\n") ||
+		strings.Contains(string(report), "First entry") {
+		t.Errorf("Crash report has wrong entries:\n%s", report)
+	}
+	if !strings.Contains(string(report), "TestFatalCrashReport") {
+		t.Errorf("Crash report does not contain goroutine stacks:\n%s", report)
+	}
+}
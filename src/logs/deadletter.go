@@ -0,0 +1,191 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Fields added to the entries in a dead-letter file.
+const (
+	FieldDeadLetterReason = "dead_letter_reason" // The rejection error
+	FieldDeadLetterStream = "dead_letter_stream" // Type of the rejecting stream
+	FieldDeadLetterText   = "dead_letter_text"   // Output for streams without an encoder
+)
+
+// PermanentError marks a stream error that will not go away by retrying, for
+// example an entry rejected by a remote sink as malformed or too large.
+// Entries rejected with a PermanentError are written to the dead-letter file
+// if one is set.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+// Unwrap returns the wrapped error.
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent wraps err in a PermanentError. Streams and Publishers return it
+// to reject an entry for good:
+//
+//	if resp.StatusCode == http.StatusBadRequest {
+//		return 0, logs.Permanent(fmt.Errorf("rejected: %s", resp.Status))
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{err}
+}
+
+// IsPermanent reports whether err is or wraps a PermanentError.
+func IsPermanent(err error) bool {
+	var pe *PermanentError
+	return errors.As(err, &pe)
+}
+
+// deadLetter is the file rejected entries are written to.
+type deadLetter struct {
+	mu   sync.Mutex
+	path string
+}
+
+// add appends e with the rejection reason to the file. text is the output
+// for streams without an encoder.
+func (d *deadLetter) add(e *Entry, w io.Writer, text []byte, reason error) error {
+	x := *e
+	x.Fields = append(append([]Field(nil), e.Fields...),
+		Field{FieldDeadLetterReason, reason.Error()},
+		Field{FieldDeadLetterStream, fmt.Sprintf("%T", w)})
+	if text != nil {
+		x.Fields = append(x.Fields, Field{FieldDeadLetterText, string(text)})
+	}
+	b, err := JSONEncoder{}.Encode(&x)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.OpenFile(d.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// SetDeadLetterFile sets the file entries permanently rejected by a stream of
+// the standard logging object are written to.
+func SetDeadLetterFile(path string) { std.SetDeadLetterFile(path) }
+
+// ReplayDeadLetters writes the entries in the dead-letter file of the
+// standard logging object to sink.
+func ReplayDeadLetters(sink io.Writer) (int, error) { return std.ReplayDeadLetters(sink) }
+
+// SetDeadLetterFile sets the file entries are written to when a stream
+// rejects them with a PermanentError. The entries are written as JSON lines,
+// with the fields FieldDeadLetterReason and FieldDeadLetterStream added. For
+// streams without an encoder the formatted output is kept in
+// FieldDeadLetterText. An empty path disables the dead-letter file.
+func (l *Logger) SetDeadLetterFile(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if path == "" {
+		l.deadLetter = nil
+		return
+	}
+	l.deadLetter = &deadLetter{path: path}
+}
+
+// ReplayDeadLetters writes the entries in the dead-letter file to sink and
+// returns the number written. Entries are encoded with the encoder set for
+// sink, or written as the original output if it has none. Entries that fail
+// again stay in the file with the new reason; the others are removed.
+func (l *Logger) ReplayDeadLetters(sink io.Writer) (int, error) {
+	l.mu.Lock()
+	d := l.deadLetter
+	var enc Encoder
+	if comparable(sink) {
+		enc = l.encoders[sink]
+	}
+	l.mu.Unlock()
+	if d == nil {
+		return 0, errors.New("logs: no dead-letter file set")
+	}
+
+	// Entries failing again are appended to a new file at the same path.
+	d.mu.Lock()
+	tmp := d.path + ".replay"
+	err := os.Rename(d.path, tmp)
+	d.mu.Unlock()
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var n int
+	var firstErr error
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<24)
+	for sc.Scan() {
+		var m map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		text, _ := m[FieldDeadLetterText].(string)
+		delete(m, FieldDeadLetterReason)
+		delete(m, FieldDeadLetterStream)
+		delete(m, FieldDeadLetterText)
+		e := entryFromMap(m)
+		b, werr := []byte(text), error(nil)
+		if enc != nil {
+			b, werr = enc.Encode(e)
+		}
+		if werr == nil {
+			_, werr = sink.Write(b)
+		}
+		if werr == nil {
+			n++
+			continue
+		}
+		if firstErr == nil {
+			firstErr = werr
+		}
+		var keep []byte
+		if enc == nil {
+			keep = b
+		}
+		if err := d.add(e, sink, keep, werr); err != nil {
+			// Keep the replay file so no entry is lost.
+			return n, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return n, err
+	}
+	f.Close()
+	if err := os.Remove(tmp); err != nil {
+		return n, err
+	}
+	return n, firstErr
+}
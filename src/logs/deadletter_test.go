@@ -0,0 +1,84 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type rejectingWriter struct {
+	bytes.Buffer
+	reject bool
+}
+
+func (r *rejectingWriter) Write(p []byte) (int, error) {
+	if r.reject {
+		return 0, Permanent(errors.New("payload too large"))
+	}
+	return r.Buffer.Write(p)
+}
+
+func TestDeadLetter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs-deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dead.jsonl")
+
+	text := &rejectingWriter{reject: true}
+	sink := &rejectingWriter{reject: true}
+	l := New(LEVEL_DEBUG, text, sink)
+	l.SetFlags(0)
+	l.SetStreamEncoder(sink, JSONEncoder{})
+	l.SetDeadLetterFile(path)
+	l.ErrorE().Str("user", "bob").Msg("rejected")
+
+	b, _ := ioutil.ReadFile(path)
+	for _, s := range []string{`"dead_letter_reason":"payload too large"`,
+		`"dead_letter_stream":"*logs.rejectingWriter"`,
+		`"dead_letter_text":"rejected user=bob\n"`, `"user":"bob"`} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", b, s)
+		}
+	}
+
+	// Nothing is replayed while the sink still rejects entries.
+	if n, err := l.ReplayDeadLetters(sink); n != 0 || !IsPermanent(err) {
+		t.Errorf("\nGot:\t%d, %v\nExpect:\t0, payload too large\n", n, err)
+	}
+	sink.reject = false
+	if n, err := l.ReplayDeadLetters(sink); n != 2 || err != nil {
+		t.Errorf("\nGot:\t%d, %v\nExpect:\t2, <nil>\n", n, err)
+	}
+	out := sink.String()
+	if strings.Count(out, `"msg":"rejected"`) != 2 || strings.Contains(out, "dead_letter") {
+		t.Errorf("\nGot:\t%q\n", out)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("dead-letter file left after replay: %v", err)
+	}
+}
+
+func TestDeadLetterTransient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs-deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dead.jsonl")
+
+	l := New(LEVEL_DEBUG, &flakyWriter{down: true})
+	l.SetDeadLetterFile(path)
+	l.Errorln("retry later")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("transient error written to the dead-letter file: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build !logs_nodebug
+// +build !logs_nodebug
+
+package logs
+
+import "fmt"
+
+// DebugEnabled is false if the package was built with the logs_nodebug build
+// tag. Guard expensive debug arguments with it so that the compiler removes
+// them along with the call:
+//
+//	if logs.DebugEnabled {
+//		logs.Debugf("state: %s\n", expensiveState())
+//	}
+const DebugEnabled = true
+
+// Debugf is similar to Printf(), except the colorized LEVEL_DEBUG label is
+// prefixed to the output.
+func Debugf(format string, v ...interface{}) {
+	std.fprintf(std.Flags(), LEVEL_DEBUG, 2, 0, nil, format, v...)
+}
+
+// Debug is similar to Print(), except the colorized LEVEL_DEBUG label is
+// prefixed to the output.
+func Debug(v ...interface{}) {
+	std.Fprint(std.Flags(), LEVEL_DEBUG, 2, fmt.Sprint(v...), nil)
+}
+
+// Debugln is similar to Println(), except the colorized LEVEL_DEBUG label is
+// prefixed to the output.
+func Debugln(v ...interface{}) {
+	std.Fprint(std.Flags(), LEVEL_DEBUG, 2, fmt.Sprintln(v...), nil)
+}
+
+// DebugDump pretty prints v at the LEVEL_DEBUG level. The output shows the
+// type of every value and spans multiple lines for structs, slices, and maps.
+// Pointer cycles are detected and printed as "<cycle>".
+func DebugDump(v interface{}) {
+	if !std.mayWrite(LEVEL_DEBUG) {
+		return
+	}
+	std.Fprint(std.Flags(), LEVEL_DEBUG, 2, dump(v)+"\n", nil)
+}
+
+// DebugHex writes a canonical hex dump of b at the LEVEL_DEBUG level, in the
+// same format as "hexdump -C". Dumps longer than HexDumpMax() bytes are
+// truncated.
+func DebugHex(label string, b []byte) {
+	if !std.mayWrite(LEVEL_DEBUG) {
+		return
+	}
+	std.Fprint(std.Flags(), LEVEL_DEBUG, 2, hexDump(label, b, std.hexDumpMax), nil)
+}
+
+// Debugf is equivalent to log.Debugf().
+func (l *Logger) Debugf(format string, v ...interface{}) {
+	l.fprintf(l.Flags(), LEVEL_DEBUG, 2, 0, nil, format, v...)
+}
+
+// Debug is equivalent to log.Debug().
+func (l *Logger) Debug(v ...interface{}) {
+	l.Fprint(l.Flags(), LEVEL_DEBUG, 2, fmt.Sprint(v...), nil)
+}
+
+// Debugln is equivalent to log.Debugln().
+func (l *Logger) Debugln(v ...interface{}) {
+	l.Fprint(l.Flags(), LEVEL_DEBUG, 2, fmt.Sprintln(v...), nil)
+}
+
+// DebugDump pretty prints v at the LEVEL_DEBUG level. See DebugDump() for
+// details.
+func (l *Logger) DebugDump(v interface{}) {
+	if !l.mayWrite(LEVEL_DEBUG) {
+		return
+	}
+	l.Fprint(l.Flags(), LEVEL_DEBUG, 2, dump(v)+"\n", nil)
+}
+
+// DebugHex writes a canonical hex dump of b at the LEVEL_DEBUG level. See
+// DebugHex() for details.
+func (l *Logger) DebugHex(label string, b []byte) {
+	if !l.mayWrite(LEVEL_DEBUG) {
+		return
+	}
+	l.Fprint(l.Flags(), LEVEL_DEBUG, 2, hexDump(label, b, l.hexDumpMax), nil)
+}
+
+// DebugE starts an event at the LEVEL_DEBUG level using the standard logging
+// object.
+func DebugE() *Event { return std.newEvent(LEVEL_DEBUG) }
+
+// DebugE starts an event at the LEVEL_DEBUG level.
+func (l *Logger) DebugE() *Event { return l.newEvent(LEVEL_DEBUG) }
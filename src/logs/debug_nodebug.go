@@ -0,0 +1,47 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_nodebug
+// +build logs_nodebug
+
+package logs
+
+// DebugEnabled is false if the package was built with the logs_nodebug build
+// tag. In that case all debug functions are empty and V is never enabled.
+const DebugEnabled = false
+
+// Debugf does nothing in logs_nodebug builds.
+func Debugf(format string, v ...interface{}) {}
+
+// Debug does nothing in logs_nodebug builds.
+func Debug(v ...interface{}) {}
+
+// Debugln does nothing in logs_nodebug builds.
+func Debugln(v ...interface{}) {}
+
+// DebugDump does nothing in logs_nodebug builds.
+func DebugDump(v interface{}) {}
+
+// DebugHex does nothing in logs_nodebug builds.
+func DebugHex(label string, b []byte) {}
+
+// DebugE returns nil in logs_nodebug builds, so the event does nothing.
+func DebugE() *Event { return nil }
+
+// Debugf does nothing in logs_nodebug builds.
+func (l *Logger) Debugf(format string, v ...interface{}) {}
+
+// Debug does nothing in logs_nodebug builds.
+func (l *Logger) Debug(v ...interface{}) {}
+
+// Debugln does nothing in logs_nodebug builds.
+func (l *Logger) Debugln(v ...interface{}) {}
+
+// DebugDump does nothing in logs_nodebug builds.
+func (l *Logger) DebugDump(v interface{}) {}
+
+// DebugHex does nothing in logs_nodebug builds.
+func (l *Logger) DebugHex(label string, b []byte) {}
+
+// DebugE returns nil in logs_nodebug builds, so the event does nothing.
+func (l *Logger) DebugE() *Event { return nil }
@@ -0,0 +1,39 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_nodebug
+// +build logs_nodebug
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNoDebug(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.Debugln("debug")
+	logr.DebugE().Str("k", "v").Msg("event")
+	logr.V(0).Infoln("verbose")
+	if buf.Len() != 0 {
+		t.Errorf("Debug output in logs_nodebug build: %q", buf.String())
+	}
+}
+
+func TestStdNoDebug(t *testing.T) {
+	var buf bytes.Buffer
+	std = New(LEVEL_DEBUG, &buf)
+	SetFlags(Llabel)
+	Debugf("%s\n", "debug")
+	Debug("debug")
+	Debugln("debug")
+	DebugDump(map[string]int{"k": 1})
+	DebugHex("bytes", []byte("debug"))
+	DebugE().Str("k", "v").Msg("event")
+	Infoln("info")
+	if expect := "[INFO]     info\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
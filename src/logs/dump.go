@@ -0,0 +1,143 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// dumper formats arbitrary values as indented, type annotated text.
+type dumper struct {
+	buf     bytes.Buffer
+	depth   int
+	tabStop int
+	seen    map[uintptr]bool // Pointers on the current path, used to detect cycles
+}
+
+// dump returns a multi-line representation of v. Pointer cycles are printed
+// as "<cycle>".
+func dump(v interface{}) string {
+	d := &dumper{tabStop: 4, seen: make(map[uintptr]bool)}
+	d.value(reflect.ValueOf(v))
+	return d.buf.String()
+}
+
+func (d *dumper) indent() {
+	d.buf.WriteString(strings.Repeat(" ", d.depth*d.tabStop))
+}
+
+func (d *dumper) value(v reflect.Value) {
+	if !v.IsValid() {
+		d.buf.WriteString("<nil>")
+		return
+	}
+	fmt.Fprintf(&d.buf, "(%s) ", v.Type())
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			d.buf.WriteString("<nil>")
+			return
+		}
+		if d.seen[v.Pointer()] {
+			d.buf.WriteString("<cycle>")
+			return
+		}
+		d.seen[v.Pointer()] = true
+		d.value(v.Elem())
+		delete(d.seen, v.Pointer())
+	case reflect.Interface:
+		d.value(v.Elem())
+	case reflect.Struct:
+		d.buf.WriteString("{\n")
+		d.depth++
+		for i := 0; i < v.NumField(); i++ {
+			d.indent()
+			d.buf.WriteString(v.Type().Field(i).Name + ": ")
+			d.value(v.Field(i))
+			d.buf.WriteString(",\n")
+		}
+		d.depth--
+		d.indent()
+		d.buf.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			d.buf.WriteString("<nil>")
+			return
+		}
+		if v.Kind() == reflect.Slice {
+			if d.seen[v.Pointer()] && v.Len() > 0 {
+				d.buf.WriteString("<cycle>")
+				return
+			}
+			d.seen[v.Pointer()] = true
+			defer delete(d.seen, v.Pointer())
+		}
+		fmt.Fprintf(&d.buf, "(len=%d) {\n", v.Len())
+		d.depth++
+		for i := 0; i < v.Len(); i++ {
+			d.indent()
+			d.value(v.Index(i))
+			d.buf.WriteString(",\n")
+		}
+		d.depth--
+		d.indent()
+		d.buf.WriteString("}")
+	case reflect.Map:
+		if v.IsNil() {
+			d.buf.WriteString("<nil>")
+			return
+		}
+		if d.seen[v.Pointer()] {
+			d.buf.WriteString("<cycle>")
+			return
+		}
+		d.seen[v.Pointer()] = true
+		defer delete(d.seen, v.Pointer())
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		fmt.Fprintf(&d.buf, "(len=%d) {\n", v.Len())
+		d.depth++
+		for _, k := range keys {
+			d.indent()
+			d.value(k)
+			d.buf.WriteString(": ")
+			d.value(v.MapIndex(k))
+			d.buf.WriteString(",\n")
+		}
+		d.depth--
+		d.indent()
+		d.buf.WriteString("}")
+	case reflect.String:
+		fmt.Fprintf(&d.buf, "%q", v.String())
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			d.buf.WriteString("<nil>")
+			return
+		}
+		fmt.Fprintf(&d.buf, "%#x", v.Pointer())
+	default:
+		if v.CanInterface() {
+			fmt.Fprintf(&d.buf, "%v", v.Interface())
+		} else {
+			fmt.Fprintf(&d.buf, "%v", v)
+		}
+	}
+}
+
+// hexDump returns label followed by an offset/hex/ASCII dump of b on the
+// following lines. At most max bytes are dumped if max is greater than zero.
+func hexDump(label string, b []byte, max int) string {
+	out := fmt.Sprintf("%s (%d bytes):\n", label, len(b))
+	if max > 0 && len(b) > max {
+		return out + hex.Dump(b[:max]) + fmt.Sprintf("… %d more bytes\n", len(b)-max)
+	}
+	return out + hex.Dump(b)
+}
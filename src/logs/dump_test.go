@@ -0,0 +1,106 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+type dumpNode struct {
+	Name string
+	Tags []string
+	Next *dumpNode
+}
+
+func TestDump(t *testing.T) {
+	n := &dumpNode{Name: "a", Tags: []string{"x"}}
+	n.Next = n
+	expect := `(*logs.dumpNode) (logs.dumpNode) {
+    Name: (string) "a",
+    Tags: ([]string) (len=1) {
+        (string) "x",
+    },
+    Next: (*logs.dumpNode) <cycle>,
+}`
+	if out := dump(n); out != expect {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", out, expect)
+	}
+}
+
+func TestDumpMap(t *testing.T) {
+	expect := `(map[string]int) (len=2) {
+    (string) "a": (int) 1,
+    (string) "b": (int) 2,
+}`
+	if out := dump(map[string]int{"b": 2, "a": 1}); out != expect {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", out, expect)
+	}
+}
+
+func TestDebugDump(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.DebugDump([]int{1})
+	expect := "([]int) (len=1) {\n    (int) 1,\n}\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestDebugDumpLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	var v interface{} = map[string][]int{"a": {1, 2}}
+	// The value is not dumped if the entry is not written.
+	if n := testing.AllocsPerRun(10, func() { logr.DebugDump(v) }); n != 0 {
+		t.Errorf("\nGot:\t%v allocations\nExpect:\t0\n", n)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), "")
+	}
+}
+
+func TestDumpTemplateFunc(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	if err := logr.SetTemplate("{{dump .LineNumber}}"); err != nil {
+		t.Fatal(err)
+	}
+	logr.Print("text")
+	if expect := "(int) 0"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestDebugHex(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.SetHexDumpMax(16)
+	logr.DebugHex("packet", []byte("Hello, world! This is a packet."))
+	expect := "packet (31 bytes):\n" +
+		"00000000  48 65 6c 6c 6f 2c 20 77  6f 72 6c 64 21 20 54 68  |Hello, world! Th|\n" +
+		"… 15 more bytes\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestDebugHexLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	b := []byte("Hello, world! This is a packet.")
+	// The hex dump is not built if the entry is not written.
+	if n := testing.AllocsPerRun(10, func() { logr.DebugHex("packet", b) }); n != 0 {
+		t.Errorf("\nGot:\t%v allocations\nExpect:\t0\n", n)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), "")
+	}
+}
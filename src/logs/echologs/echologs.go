@@ -0,0 +1,53 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_echo
+// +build logs_echo
+
+// Package echologs adapts the HTTP logging of package logs to echo. It is
+// only built with the logs_echo build tag because it needs the echo module,
+// which is not a dependency of package logs:
+//
+//	e := echo.New()
+//	e.Use(echologs.Middleware(logr))
+package echologs
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"logs"
+)
+
+// Middleware returns an echo middleware that logs every request like
+// (*logs.Logger).Middleware, including the request id, the request entry, the
+// access log line, and the recovery of panics, which are answered with status
+// 500. Errors returned by the handler are passed to the echo error handler
+// first, so the logged status is the one sent.
+func Middleware(l *logs.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			start := time.Now()
+			r := c.Request()
+			ctx := l.RequestContext(c.Response(), r)
+			c.SetRequest(r.WithContext(ctx))
+			defer func() {
+				if v := recover(); v != nil {
+					if v == http.ErrAbortHandler {
+						panic(v)
+					}
+					l.RecoverRequest(ctx, c.Request(), v)
+					err = echo.NewHTTPError(http.StatusInternalServerError)
+				}
+				if err != nil {
+					c.Error(err)
+				}
+				res := c.Response()
+				l.LogRequest(ctx, c.Request(), res.Status, int(res.Size), start)
+			}()
+			return next(c)
+		}
+	}
+}
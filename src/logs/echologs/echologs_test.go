@@ -0,0 +1,95 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_echo
+// +build logs_echo
+
+package echologs
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"logs"
+)
+
+func newServer(logr *logs.Logger) *echo.Echo {
+	e := echo.New()
+	e.Use(Middleware(logr))
+	e.GET("/pot", func(c echo.Context) error {
+		return c.String(http.StatusTeapot, "short and stout")
+	})
+	e.GET("/gone", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusGone)
+	})
+	e.GET("/boom", func(c echo.Context) error {
+		panic("nil map")
+	})
+	return e
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	e := newServer(logr)
+
+	req := httptest.NewRequest("GET", "/pot", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusTeapot)
+	}
+	if id := rec.Header().Get("X-Request-ID"); id != "abc123" {
+		t.Errorf("X-Request-ID = %q; want: %q", id, "abc123")
+	}
+	expect := regexp.MustCompile(`^abc123 request method=GET path=/pot status=418 size=15 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestMiddlewareError(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	e := newServer(logr)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/gone", nil))
+	if rec.Code != http.StatusGone {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusGone)
+	}
+	expect := regexp.MustCompile(`^\S+ request method=GET path=/gone status=410 size=\d+ duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	e := newServer(logr)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusInternalServerError)
+	}
+	expect := regexp.MustCompile(`(?s)^\S+ panic: nil map method=GET path=/boom stack=.*
+\S+ request method=GET path=/boom status=500 size=\d+ duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// An Encoder converts entries into the bytes written to a stream. Streams
+// without an encoder receive the output of the template. Encoders are set
+// per stream with SetStreamEncoder.
+type Encoder interface {
+	Encode(e *Entry) ([]byte, error)
+}
+
+// Keys used by the JSON and msgpack encoders. Fields with the same keys are
+// overwritten by the entry values.
+const (
+	keyTime    = "time"
+	keyLevel   = "level"
+	keyMessage = "msg"
+	keySeq     = "seq"
+	keyPid     = "pid"
+	keyPPid    = "ppid"
+	keyUser    = "username"
+	keyID      = "entry_id"
+)
+
+// levelName returns the short lower case name of lvl, for example "warning".
+func levelName(lvl level) string {
+	return strings.ToLower(strings.TrimPrefix(lvl.String(), "LEVEL_"))
+}
+
+// levelFromName is the inverse of levelName.
+func levelFromName(name string) level { return LevelFromString(name) }
+
+// JSONEncoder encodes each entry as a single line JSON object containing the
+// time, level, message, and fields of the entry. The logger name is included
+// as the FieldLogger field unless the entry has one. The zero value uses the
+// keys "time", "level", and "msg".
+type JSONEncoder struct {
+	TimeKey    string // Key of the time instead of "time"
+	LevelKey   string // Key of the level instead of "level"
+	MessageKey string // Key of the message instead of "msg"
+	CallerKey  string // Key of the caller, dir/file.go:line, omitted if empty
+	UTC        bool   // Write the time in UTC
+}
+
+// key returns k, or def if k is empty.
+func key(k, def string) string {
+	if k == "" {
+		return def
+	}
+	return k
+}
+
+// Encode satisfies the Encoder interface.
+func (j JSONEncoder) Encode(e *Entry) ([]byte, error) {
+	t := e.Time
+	if j.UTC {
+		t = t.UTC()
+	}
+	fields := encodedFields(e, t.Format(time.RFC3339Nano), j.TimeKey, j.LevelKey,
+		j.MessageKey, j.CallerKey)
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		m[f.Key] = jsonValue(f.Value)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// encodedFields returns the keys and values written by JSONEncoder and
+// MsgpackEncoder: the fields of e, the logger name as the FieldLogger field
+// unless e has one, and the time, level, message, caller, and other values of
+// the entry, which replace fields with the same keys. If fields repeat a key,
+// the last one is used. Empty keys are replaced by the defaults, and the
+// caller is only included if callerKey is not empty.
+func encodedFields(e *Entry, t interface{}, timeKey, levelKey, msgKey, callerKey string) []Field {
+	fields := make([]Field, 0, len(e.Fields)+8)
+	index := make(map[string]int, cap(fields))
+	set := func(k string, v interface{}) {
+		if i, ok := index[k]; ok {
+			fields[i].Value = v
+			return
+		}
+		index[k] = len(fields)
+		fields = append(fields, Field{k, v})
+	}
+	for _, f := range e.Fields {
+		set(f.Key, f.Value)
+	}
+	if _, ok := index[FieldLogger]; !ok && e.LoggerName != "" {
+		set(FieldLogger, e.LoggerName)
+	}
+	set(key(timeKey, keyTime), t)
+	set(key(levelKey, keyLevel), levelName(e.Level))
+	set(key(msgKey, keyMessage), e.Message)
+	if callerKey != "" && e.Caller.File != "" {
+		set(callerKey, fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(e.Caller.File)),
+			filepath.Base(e.Caller.File), e.Caller.Line))
+	}
+	if e.Seq > 0 {
+		set(keySeq, e.Seq)
+	}
+	if e.Pid > 0 {
+		set(keyPid, e.Pid)
+		set(keyPPid, e.PPid)
+		set(keyUser, e.User)
+	}
+	if e.ID != "" {
+		set(keyID, e.ID)
+	}
+	return fields
+}
+
+// MessageEncoder encodes each entry as its message followed by its fields,
+// without the date, label, or other parts of the template, for output read
+// by other programs.
+type MessageEncoder struct{}
+
+// Encode satisfies the Encoder interface.
+func (MessageEncoder) Encode(e *Entry) ([]byte, error) {
+	return []byte(appendFields(e.Message, e.Fields, nil) + "\n"), nil
+}
+
+// jsonValue returns v in a form that encodes well as JSON. Errors and
+// Stringers are encoded using their text.
+func jsonValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case error:
+		return x.Error()
+	case time.Duration:
+		return x.String()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+// MsgpackEncoder encodes each entry as a msgpack map with the same keys and
+// values as JSONEncoder, except that the time is in nanoseconds since the Unix
+// epoch. It is more compact than JSON for high volume shipping to collectors.
+// The output can be read back with NewMsgpackDecoder.
+type MsgpackEncoder struct {
+	CallerKey string // Key of the caller, dir/file.go:line, omitted if empty
+}
+
+// Encode satisfies the Encoder interface.
+func (m MsgpackEncoder) Encode(e *Entry) ([]byte, error) {
+	fields := encodedFields(e, e.Time.UnixNano(), "", "", "", m.CallerKey)
+	b := make([]byte, 0, 64+len(e.Message))
+	b = msgpackMapHeader(b, len(fields))
+	for _, f := range fields {
+		b = msgpackString(b, f.Key)
+		b = msgpackValue(b, f.Value)
+	}
+	return b, nil
+}
+
+func msgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n < 1<<16:
+		return append(b, 0xde, byte(n>>8), byte(n))
+	}
+	return append(b, 0xdf, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+func msgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n < 1<<8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1<<16:
+		b = append(b, 0xda, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0xdb, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, s...)
+}
+
+func msgpackInt(b []byte, i int64) []byte {
+	if i >= 0 && i < 128 {
+		return append(b, byte(i))
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(i))
+	return append(append(b, 0xd3), buf[:]...)
+}
+
+func msgpackUint(b []byte, u uint64) []byte {
+	if u < 128 {
+		return append(b, byte(u))
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], u)
+	return append(append(b, 0xcf), buf[:]...)
+}
+
+func msgpackValue(b []byte, v interface{}) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if x {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case int:
+		return msgpackInt(b, int64(x))
+	case int8:
+		return msgpackInt(b, int64(x))
+	case int16:
+		return msgpackInt(b, int64(x))
+	case int32:
+		return msgpackInt(b, int64(x))
+	case int64:
+		return msgpackInt(b, x)
+	case uint:
+		return msgpackUint(b, uint64(x))
+	case uint8:
+		return msgpackUint(b, uint64(x))
+	case uint16:
+		return msgpackUint(b, uint64(x))
+	case uint32:
+		return msgpackUint(b, uint64(x))
+	case uint64:
+		return msgpackUint(b, x)
+	case uintptr:
+		return msgpackUint(b, uint64(x))
+	case float32:
+		return msgpackFloat(b, float64(x))
+	case float64:
+		return msgpackFloat(b, x)
+	case string:
+		return msgpackString(b, x)
+	case []byte:
+		return msgpackString(b, string(x))
+	}
+	return msgpackString(b, fmt.Sprint(jsonValue(v)))
+}
+
+func msgpackFloat(b []byte, f float64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], math.Float64bits(f))
+	return append(append(b, 0xcb), buf[:]...)
+}
+
+// errMsgpack is returned when decoding unsupported or corrupt msgpack data.
+var errMsgpack = errors.New("logs: invalid msgpack entry")
+
+// MsgpackDecoder reads entries written by MsgpackEncoder.
+type MsgpackDecoder struct {
+	r *bufio.Reader
+}
+
+// NewMsgpackDecoder returns a decoder reading entries from r.
+func NewMsgpackDecoder(r io.Reader) *MsgpackDecoder {
+	return &MsgpackDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode returns the next entry. io.EOF is returned when there are no more
+// entries.
+func (d *MsgpackDecoder) Decode() (*Entry, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var n uint64
+	switch {
+	case c&0xf0 == 0x80:
+		n = uint64(c & 0x0f)
+	case c == 0xde || c == 0xdf:
+		if n, err = d.uint(2 << (c - 0xde)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errMsgpack
+	}
+	m, err := d.mapValue(n)
+	if err != nil {
+		return nil, err
+	}
+	return entryFromMap(m), nil
+}
+
+// entryFromMap converts a decoded map back into an entry. Keys other than the
+// keys of the encoders become fields, sorted by key.
+func entryFromMap(m map[string]interface{}) *Entry {
+	e := new(Entry)
+	for k, v := range m {
+		switch k {
+		case keyTime:
+			switch t := v.(type) {
+			case int64:
+				e.Time = time.Unix(0, t)
+			case string:
+				e.Time, _ = time.Parse(time.RFC3339Nano, t)
+			}
+		case keyLevel:
+			s, _ := v.(string)
+			e.Level = levelFromName(s)
+		case keyMessage:
+			e.Message, _ = v.(string)
+		case keySeq:
+			switch n := v.(type) {
+			case int64:
+				e.Seq = uint64(n)
+			case uint64:
+				e.Seq = n
+			case float64:
+				e.Seq = uint64(n)
+			}
+		case keyPid, keyPPid:
+			var n int
+			switch x := v.(type) {
+			case int64:
+				n = int(x)
+			case uint64:
+				n = int(x)
+			case float64:
+				n = int(x)
+			}
+			if k == keyPid {
+				e.Pid = n
+			} else {
+				e.PPid = n
+			}
+		case keyUser:
+			e.User, _ = v.(string)
+		case keyID:
+			e.ID, _ = v.(string)
+		default:
+			e.Fields = append(e.Fields, Field{k, v})
+		}
+	}
+	sortFields(e.Fields)
+	return e
+}
+
+// value reads a value of a map. Maps are only valid as entries, so nested
+// maps are rejected.
+func (d *MsgpackDecoder) value() (interface{}, error) {
+	c, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return d.str(uint64(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcb:
+		n, err := d.uint(8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := d.uint(size)
+		// Sign extend the value from size bytes.
+		shift := uint(64 - 8*size)
+		return int64(n<<shift) >> shift, err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	}
+	return nil, errMsgpack
+}
+
+func (d *MsgpackDecoder) uint(size int) (uint64, error) {
+	var n uint64
+	for i := 0; i < size; i++ {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+// str reads a string of n bytes. The buffer grows with the bytes read, so a
+// corrupt length does not allocate more memory than the input holds.
+func (d *MsgpackDecoder) str(n uint64) (string, error) {
+	var b strings.Builder
+	if n < 512 {
+		b.Grow(int(n))
+	}
+	if _, err := io.CopyN(&b, d.r, int64(n)); err != nil {
+		return "", io.ErrUnexpectedEOF
+	}
+	return b.String(), nil
+}
+
+// mapValue reads the n keys and values of a map. The map is not sized by n,
+// which is read from the input.
+func (d *MsgpackDecoder) mapValue(n uint64) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	for i := uint64(0); i < n; i++ {
+		k, err := d.value()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, errMsgpack
+		}
+		if m[key], err = d.value(); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	var text, js bytes.Buffer
+	logr := New(LEVEL_DEBUG, &text, &js)
+	logr.SetFlags(Llabel)
+	logr.SetStreamEncoder(&js, JSONEncoder{})
+	logr.InfoE().Str("user", "bob").Int("attempt", 2).Msg("login")
+
+	if expect := "[INFO]     login user=bob attempt=2\n"; text.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", text.String(), expect)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(js.Bytes(), &m); err != nil {
+		t.Fatalf("Unmarshal(%q) = %v", js.String(), err)
+	}
+	if m["level"] != "info" || m["msg"] != "login" || m["user"] != "bob" ||
+		m["attempt"] != 2.0 || m["time"] == nil {
+		t.Errorf("Got %v; want: level, msg, user, attempt, and time keys", m)
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetStreamEncoder(&buf, MsgpackEncoder{})
+	logr.WarningE().Str("path", "/tmp").Int("code", -3).Float64("ratio", 0.5).
+		Bool("retry", true).Msg("disk almost full")
+	logr.Println("plain text")
+
+	d := NewMsgpackDecoder(&buf)
+	e, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Level != LEVEL_WARNING || e.Message != "disk almost full" ||
+		time.Since(e.Time) > time.Minute {
+		t.Errorf("Got %+v", e)
+	}
+	expect := []Field{{"code", int64(-3)}, {"path", "/tmp"}, {"ratio", 0.5},
+		{"retry", true}}
+	if !reflect.DeepEqual(e.Fields, expect) {
+		t.Errorf("\nGot:\t%v\nExpect:\t%v\n", e.Fields, expect)
+	}
+	if e, err = d.Decode(); err != nil || e.Message != "plain text" ||
+		e.Level != LEVEL_PRINT {
+		t.Errorf("Decode() = %+v, %v; want: plain text entry", e, err)
+	}
+	if _, err = d.Decode(); err != io.EOF {
+		t.Errorf("Decode() error = %v; want: io.EOF", err)
+	}
+}
+
+// sliceWriter is a stream that cannot be used as a map key.
+type sliceWriter []*bytes.Buffer
+
+func (w sliceWriter) Write(p []byte) (int, error) { return w[0].Write(p) }
+
+func TestEncoderUncomparableStream(t *testing.T) {
+	var js, buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &js, sliceWriter{&buf})
+	logr.SetFlags(0)
+	logr.SetStreamEncoder(&js, JSONEncoder{})
+	logr.SetStreamEncoder(sliceWriter{&buf}, JSONEncoder{})
+	logr.Infoln("entry")
+	if buf.String() != "entry\n" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), "entry\n")
+	}
+	if js.Len() == 0 || js.Bytes()[0] != '{' {
+		t.Errorf("Got %q; want: JSON entry", js.String())
+	}
+}
+
+func TestMsgpackJSONAgree(t *testing.T) {
+	var mp, js bytes.Buffer
+	logr := New(LEVEL_DEBUG, &mp, &js)
+	logr.SetName("api")
+	logr.SetStreamEncoder(&mp, MsgpackEncoder{})
+	logr.SetStreamEncoder(&js, JSONEncoder{})
+	logr.InfoE().Interface("big", uint64(math.MaxUint64)).Str("msg", "field").Str("k", "one").
+		Str("k", "two").Msg("entry")
+
+	e, err := NewMsgpackDecoder(&mp).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(js.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	expect := []Field{{"big", uint64(math.MaxUint64)}, {"k", "two"}, {FieldLogger, "api"}}
+	if e.Message != "entry" || !reflect.DeepEqual(e.Fields, expect) {
+		t.Errorf("\nGot:\t%q %v\nExpect:\t%q %v\n", e.Message, e.Fields, "entry", expect)
+	}
+	if m["msg"] != "entry" || m["k"] != "two" || m[FieldLogger] != "api" {
+		t.Errorf("\nGot:\t%v\nExpect:\tmsg=entry k=two logger=api\n", m)
+	}
+}
+
+func TestMsgpackDecodeCorrupt(t *testing.T) {
+	var tests = [][]byte{
+		{0x81, 0xdb, 0xff, 0xff, 0xff, 0xff, 'a'},       // String longer than the input
+		{0xdf, 0xff, 0xff, 0xff, 0xff, 0xa1, 'k', 0xc0}, // Map longer than the input
+		{0x81, 0xa1, 'k', 0x81, 0xa1, 'k', 0xc0},        // Nested map
+	}
+	for _, test := range tests {
+		if e, err := NewMsgpackDecoder(bytes.NewReader(test)).Decode(); err == nil {
+			t.Errorf("Decode(%x) = %+v; want: an error", test, e)
+		}
+	}
+}
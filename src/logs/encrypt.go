@@ -0,0 +1,239 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// encryptMagic starts every stream written by EncryptingWriter.
+const encryptMagic = "GOLOGSE1"
+
+// ErrNotEncrypted is returned by Decrypt if the input does not start with the
+// header written by EncryptingWriter.
+var ErrNotEncrypted = errors.New("logs: input is not an encrypted log")
+
+// ErrTruncated is returned by Decrypt if a writer session ends before the end
+// frame written by Close, so frames may have been removed from its end. This
+// is also the case for a session that was never closed, such as one cut
+// short by a crash before the file was appended to again.
+var ErrTruncated = errors.New("logs: encrypted log is truncated")
+
+// Kinds of frames, bound to every frame together with its number.
+const (
+	frameData byte = iota
+	frameEnd
+	frameStart
+)
+
+// maxFrame is the most plain text sealed into one frame. Longer writes are
+// split, so Decrypt can reject larger frame lengths before allocating.
+const maxFrame = 1 << 20
+
+// frameAAD returns the additional data authenticated with frame n.
+func frameAAD(n uint64, kind byte) []byte {
+	var b [9]byte
+	binary.BigEndian.PutUint64(b[:8], n)
+	b[8] = kind
+	return b[:]
+}
+
+// EncryptingWriter is a stream wrapper that encrypts output at rest with
+// AES-GCM. Every write is sealed into its own frame:
+//
+//	4 byte big endian frame length | 12 byte nonce | ciphertext and tag
+//
+// The frames follow an 8 byte header, which is left out when appending to a
+// file that is not empty. Writes longer than 1 MiB are split into several
+// frames. Every writer starts a session with an empty start frame, so the
+// frames of a writer appended to the same file, even after a crash, are told
+// apart from the earlier ones. Frames are numbered from zero within their
+// session and the number is authenticated with each frame, so Decrypt detects
+// removed or reordered frames. Close writes an empty end frame, so Decrypt
+// also detects frames removed from the end.
+type EncryptingWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	aead    cipher.AEAD
+	header  bool   // Set once the header is written
+	started bool   // Set once the start frame is written
+	n       uint64 // Number of the next frame
+	closed  bool
+}
+
+// NewEncryptingWriter returns a writer encrypting all output written to w
+// with key, which must be 16, 24, or 32 bytes long to select AES-128, AES-192,
+// or AES-256. If w is a file that is not empty, such as one opened with
+// os.O_APPEND, the header is not written again.
+func NewEncryptingWriter(w io.Writer, key []byte) (*EncryptingWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &EncryptingWriter{w: w, aead: aead}
+	if f, ok := w.(interface{ Stat() (os.FileInfo, error) }); ok {
+		if fi, err := f.Stat(); err == nil && fi.Size() > 0 {
+			e.header = true
+		}
+	}
+	return e, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Write encrypts p into a single frame, or into several if p is longer than
+// 1 MiB.
+func (e *EncryptingWriter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return 0, os.ErrClosed
+	}
+	n := 0
+	for {
+		chunk := p[n:]
+		if len(chunk) > maxFrame {
+			chunk = chunk[:maxFrame]
+		}
+		if err := e.seal(chunk, frameData); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		if n == len(p) {
+			return n, nil
+		}
+	}
+}
+
+// Close writes the end frame and closes the underlying writer if it is an
+// io.Closer.
+func (e *EncryptingWriter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	err := e.seal(nil, frameEnd)
+	if c, ok := e.w.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// seal writes p as the next frame, preceded by the header and the start frame
+// if they are not written yet. It must be called with e.mu held.
+func (e *EncryptingWriter) seal(p []byte, kind byte) error {
+	var out []byte
+	if !e.header {
+		out = []byte(encryptMagic)
+	}
+	n := e.n
+	if !e.started {
+		start, err := e.frame(nil, 0, frameStart)
+		if err != nil {
+			return err
+		}
+		out, n = append(out, start...), 1
+	}
+	frame, err := e.frame(p, n, kind)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.Write(append(out, frame...)); err != nil {
+		return err
+	}
+	e.header, e.started = true, true
+	e.n = n + 1
+	return nil
+}
+
+// frame returns p sealed as frame n of the given kind.
+func (e *EncryptingWriter) frame(p []byte, n uint64, kind byte) ([]byte, error) {
+	size := e.aead.NonceSize()
+	frame := make([]byte, 4+size, 4+size+len(p)+e.aead.Overhead())
+	if _, err := rand.Read(frame[4 : 4+size]); err != nil {
+		return nil, err
+	}
+	frame = e.aead.Seal(frame, frame[4:4+size], p, frameAAD(n, kind))
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+	return frame, nil
+}
+
+// Decrypt reads the output of an EncryptingWriter from r and writes the plain
+// text to w. An error is returned if a frame has been modified, removed, or
+// reordered, or if the input is truncated. The plain text of the frames
+// before the error has been written to w. ErrTruncated for a session that was
+// not closed is only returned once the rest of the input is written.
+func Decrypt(r io.Reader, w io.Writer, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	magic := make([]byte, len(encryptMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != encryptMagic {
+		return ErrNotEncrypted
+	}
+	k := aead.NonceSize()
+	var size [4]byte
+	var n uint64
+	session := false   // Inside a session, after its start frame
+	truncated := false // A session ended without its end frame
+	for {
+		if _, err := io.ReadFull(r, size[:]); err == io.EOF {
+			if session || truncated {
+				return ErrTruncated
+			}
+			return nil
+		} else if err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		length := binary.BigEndian.Uint32(size[:])
+		if length < uint32(k) || length > uint32(k+maxFrame+aead.Overhead()) {
+			return fmt.Errorf("logs: frame %d has an invalid length of %d bytes", n, length)
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return io.ErrUnexpectedEOF
+		}
+		nonce, sealed := frame[:k], frame[k:]
+		if session {
+			if plain, err := aead.Open(nil, nonce, sealed, frameAAD(n, frameData)); err == nil {
+				n++
+				if _, err := w.Write(plain); err != nil {
+					return err
+				}
+				continue
+			}
+			if _, err := aead.Open(nil, nonce, sealed, frameAAD(n, frameEnd)); err == nil {
+				session = false
+				continue
+			}
+		}
+		if _, err := aead.Open(nil, nonce, sealed, frameAAD(0, frameStart)); err != nil {
+			return fmt.Errorf("logs: frame %d has been modified, removed, or reordered", n)
+		}
+		// The session of a writer appended later.
+		if session {
+			truncated = true
+		}
+		session, n = true, 1
+	}
+}
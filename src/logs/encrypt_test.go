@@ -0,0 +1,183 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptingWriter(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	var enc bytes.Buffer
+	w, err := NewEncryptingWriter(&enc, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logr := New(LEVEL_DEBUG, w)
+	logr.SetFlags(0)
+	logr.Println("card number 4111")
+	logr.Println("second entry")
+	if bytes.Contains(enc.Bytes(), []byte("4111")) {
+		t.Errorf("Encrypted output contains plain text")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var plain bytes.Buffer
+	if err := Decrypt(bytes.NewReader(enc.Bytes()), &plain, key); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "card number 4111\nsecond entry\n"; plain.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", plain.String(), expect)
+	}
+
+	b := enc.Bytes()
+	b[len(b)-1] ^= 1
+	if err := Decrypt(bytes.NewReader(b), &plain, key); err == nil {
+		t.Errorf("Decrypt() of modified input = nil; want: error")
+	}
+}
+
+// encryptFrames returns the header and the frames of an encrypted log.
+func encryptFrames(b []byte) (header []byte, frames [][]byte) {
+	header, b = b[:len(encryptMagic)], b[len(encryptMagic):]
+	for len(b) > 0 {
+		n := 4 + int(binary.BigEndian.Uint32(b))
+		frames = append(frames, b[:n])
+		b = b[n:]
+	}
+	return header, frames
+}
+
+func TestEncryptingWriterFrames(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	var enc bytes.Buffer
+	w, err := NewEncryptingWriter(&enc, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"one\n", "two\n", "three\n"} {
+		w.Write([]byte(s))
+	}
+	w.Close()
+	header, f := encryptFrames(enc.Bytes())
+
+	tests := []struct {
+		name   string
+		frames [][]byte
+	}{
+		{"removed", [][]byte{f[0], f[1], f[3], f[4]}},
+		{"reordered", [][]byte{f[0], f[2], f[1], f[3], f[4]}},
+		{"truncated", [][]byte{f[0], f[1], f[2], f[3]}},
+		{"end removed early", [][]byte{f[0], f[1], f[4]}},
+		{"start removed", [][]byte{f[1], f[2], f[3], f[4]}},
+	}
+	for _, tt := range tests {
+		in := append([]byte(nil), header...)
+		in = append(in, bytes.Join(tt.frames, nil)...)
+		var plain bytes.Buffer
+		if err := Decrypt(bytes.NewReader(in), &plain, key); err == nil {
+			t.Errorf("%s: Decrypt() = nil; want: error", tt.name)
+		}
+	}
+}
+
+func TestEncryptingWriterAppend(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "app.log.enc")
+	for _, s := range []string{"first run\n", "second run\n"} {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := NewEncryptingWriter(f, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(s))
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plain bytes.Buffer
+	if err := Decrypt(bytes.NewReader(b), &plain, key); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "first run\nsecond run\n"; plain.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", plain.String(), expect)
+	}
+}
+
+func TestEncryptingWriterAppendAfterCrash(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	path := filepath.Join(t.TempDir(), "app.log.enc")
+	for i, s := range []string{"crashed run\n", "next run\n"} {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := NewEncryptingWriter(f, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(s))
+		w.Write([]byte(s))
+		if i == 0 {
+			// No end frame, as if the process died.
+			f.Close()
+			continue
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plain bytes.Buffer
+	if err := Decrypt(bytes.NewReader(b), &plain, key); err != ErrTruncated {
+		t.Errorf("Decrypt() = %v; want: %v", err, ErrTruncated)
+	}
+	if expect := "crashed run\ncrashed run\nnext run\nnext run\n"; plain.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", plain.String(), expect)
+	}
+}
+
+func TestEncryptingWriterLargeWrite(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	var enc bytes.Buffer
+	w, err := NewEncryptingWriter(&enc, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := bytes.Repeat([]byte("0123456789\n"), maxFrame/4)
+	if n, err := w.Write(p); n != len(p) || err != nil {
+		t.Fatalf("Write() = %d, %v; want: %d, nil", n, err, len(p))
+	}
+	w.Close()
+	var plain bytes.Buffer
+	if err := Decrypt(bytes.NewReader(enc.Bytes()), &plain, key); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plain.Bytes(), p) {
+		t.Errorf("Decrypt() returned %d bytes; want: %d", plain.Len(), len(p))
+	}
+
+	// A forged length is rejected before the frame is read.
+	in := append([]byte(encryptMagic), 0xff, 0xff, 0xff, 0xff)
+	if err := Decrypt(bytes.NewReader(in), &plain, key); err == nil || err == io.ErrUnexpectedEOF {
+		t.Errorf("Decrypt() of an oversized frame = %v; want: length error", err)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"runtime"
+	"time"
+)
+
+// Entry describes a single logging event. Entries are passed to hooks and
+// encoders.
+type Entry struct {
+	Time    time.Time // When the entry was created
+	Level   level     // The level of the entry
+	Message string    // The text of the entry without formatting
+	Fields  []Field   // Structured data attached to the entry
+	Seq     uint64    // Sequence number in audit mode, otherwise zero
+	ID      string    // Unique id of the entry with the Lulid flag, a ULID
+
+	// LoggerName is the name of the logging object set with SetName.
+	LoggerName string
+
+	// Pid, PPid, and User are the process id, the parent process id, and
+	// the user name. They are only set with the Lprocess flag.
+	Pid  int
+	PPid int
+	User string
+
+	// Caller is the location of the logging call. It is only set if the
+	// flags, or an encoder set with SetStreamEncoder, need it.
+	Caller runtime.Frame
+}
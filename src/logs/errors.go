@@ -0,0 +1,57 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// formatError returns msg and err on the first line followed by each error
+// in the unwrap chain of err on its own line, indented by tabStop spaces per
+// level. Stack traces from errors that expose them through a StackTrace() or
+// Stack() method are printed below the error they belong to.
+func formatError(msg string, err error, tabStop int) string {
+	if err == nil {
+		return msg + ": <nil>\n"
+	}
+	var buf strings.Builder
+	if msg != "" {
+		buf.WriteString(msg + ": ")
+	}
+	buf.WriteString(err.Error() + "\n")
+	writeStack(&buf, err, strings.Repeat(" ", tabStop))
+	for depth, e := 1, errors.Unwrap(err); e != nil; depth, e = depth+1, errors.Unwrap(e) {
+		pad := strings.Repeat(" ", depth*tabStop)
+		buf.WriteString(pad + "caused by: " + e.Error() + "\n")
+		writeStack(&buf, e, pad+strings.Repeat(" ", tabStop))
+	}
+	return buf.String()
+}
+
+// writeStack writes the stack trace of err to buf with every line prefixed by
+// pad. Nothing is written if err does not expose a stack trace.
+func writeStack(buf *strings.Builder, err error, pad string) {
+	var stack string
+	switch e := err.(type) {
+	case interface{ Stack() []byte }:
+		stack = string(e.Stack())
+	default:
+		// The StackTrace() method of github.com/pkg/errors returns a
+		// package specific type, so it is called using reflection and
+		// formatted with %+v.
+		m := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			return
+		}
+		stack = fmt.Sprintf("%+v", m.Call(nil)[0].Interface())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stack), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			buf.WriteString(pad + line + "\n")
+		}
+	}
+}
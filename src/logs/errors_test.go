@@ -0,0 +1,32 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type stackError struct{ error }
+
+func (e stackError) Stack() []byte { return []byte("main.go:10\nmain.go:20\n") }
+
+func TestErrorErr(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	base := stackError{errors.New("permission denied")}
+	err := fmt.Errorf("load config: %w", fmt.Errorf("open file: %w", base))
+	logr.ErrorErr(err, "startup failed")
+	expect := "[ERROR]    startup failed: load config: open file: permission denied\n" +
+		"    caused by: open file: permission denied\n" +
+		"        caused by: permission denied\n" +
+		"            main.go:10\n" +
+		"            main.go:20\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is a key/value pair attached to an entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns the field in key=value form. Values containing spaces,
+// quotes, or an equals sign are quoted.
+func (f Field) String() string {
+	v := valueText(f.Value)
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	return f.Key + "=" + v
+}
+
+// valueText returns the unquoted text of a field value.
+func valueText(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// Event is an entry under construction. Events are created with the *E
+// functions, for example:
+//
+//	logr.ErrorE().Str("user", u).Int("attempt", n).Err(err).Msg("login failed")
+//
+// If the level of the event is disabled, a nil *Event is returned. All
+// methods of a nil *Event do nothing, so a disabled event allocates nothing.
+type Event struct {
+	logr   *Logger
+	level  level
+	fields []Field
+	ctx    context.Context
+}
+
+// newEvent returns an event at lvl, or nil if lvl is disabled.
+func (l *Logger) newEvent(lvl level) *Event {
+	if !l.mayWrite(lvl) {
+		return nil
+	}
+	return &Event{logr: l, level: lvl}
+}
+
+// InfoE starts an event at the LEVEL_INFO level using the standard logging
+// object.
+func InfoE() *Event { return std.newEvent(LEVEL_INFO) }
+
+// WarningE starts an event at the LEVEL_WARNING level using the standard
+// logging object.
+func WarningE() *Event { return std.newEvent(LEVEL_WARNING) }
+
+// ErrorE starts an event at the LEVEL_ERROR level using the standard logging
+// object.
+func ErrorE() *Event { return std.newEvent(LEVEL_ERROR) }
+
+// CriticalE starts an event at the LEVEL_CRITICAL level using the standard
+// logging object.
+func CriticalE() *Event { return std.newEvent(LEVEL_CRITICAL) }
+
+// InfoE starts an event at the LEVEL_INFO level.
+func (l *Logger) InfoE() *Event { return l.newEvent(LEVEL_INFO) }
+
+// WarningE starts an event at the LEVEL_WARNING level.
+func (l *Logger) WarningE() *Event { return l.newEvent(LEVEL_WARNING) }
+
+// ErrorE starts an event at the LEVEL_ERROR level.
+func (l *Logger) ErrorE() *Event { return l.newEvent(LEVEL_ERROR) }
+
+// CriticalE starts an event at the LEVEL_CRITICAL level.
+func (l *Logger) CriticalE() *Event { return l.newEvent(LEVEL_CRITICAL) }
+
+// add appends a field to the event.
+func (e *Event) add(key string, value interface{}) *Event {
+	if e == nil {
+		return nil
+	}
+	e.fields = append(e.fields, Field{key, value})
+	return e
+}
+
+// Str adds a string field.
+func (e *Event) Str(key, value string) *Event { return e.add(key, value) }
+
+// Int adds an int field.
+func (e *Event) Int(key string, value int) *Event { return e.add(key, value) }
+
+// Int64 adds an int64 field.
+func (e *Event) Int64(key string, value int64) *Event { return e.add(key, value) }
+
+// Float64 adds a float64 field.
+func (e *Event) Float64(key string, value float64) *Event { return e.add(key, value) }
+
+// Bool adds a bool field.
+func (e *Event) Bool(key string, value bool) *Event { return e.add(key, value) }
+
+// Dur adds a time.Duration field.
+func (e *Event) Dur(key string, value time.Duration) *Event { return e.add(key, value) }
+
+// Time adds a time.Time field formatted as RFC3339.
+func (e *Event) Time(key string, value time.Time) *Event {
+	if e == nil {
+		return nil
+	}
+	return e.add(key, value.Format(time.RFC3339Nano))
+}
+
+// Interface adds a field with an arbitrary value formatted with fmt.Sprint.
+func (e *Event) Interface(key string, value interface{}) *Event { return e.add(key, value) }
+
+// Err adds err as the "error" field.
+func (e *Event) Err(err error) *Event { return e.add("error", err) }
+
+// Msg writes the event with msg followed by the fields of the event. With the
+// Lulid flag, the id of the entry is returned so it can be referenced, for
+// example in a ticket. Otherwise, or if the entry is not written, the id is
+// empty.
+func (e *Event) Msg(msg string) (id string) {
+	if e == nil {
+		return ""
+	}
+	e.logr.fprintCtx(e.ctx, &id, e.logr.Flags(), e.level, 2, 0, msg+"\n", e.fields, nil)
+	e.spanEvent(msg)
+	return id
+}
+
+// Msgf writes the event with a message formatted according to a format
+// specifier followed by the fields of the event. The id of the entry is
+// returned as by Msg.
+func (e *Event) Msgf(format string, v ...interface{}) (id string) {
+	if e == nil {
+		return ""
+	}
+	msg := fmt.Sprintf(format, v...)
+	e.logr.fprintCtx(e.ctx, &id, e.logr.Flags(), e.level, 2, 0, msg+"\n", e.fields, nil)
+	e.spanEvent(msg)
+	return id
+}
+
+// appendFields inserts fields in key=value form at the end of text, before
+// any trailing newlines. If keep is not nil, only the fields it returns true
+// for are inserted.
+func appendFields(text string, fields []Field, keep func(Field) bool) string {
+	if len(fields) == 0 {
+		return text
+	}
+	body := strings.TrimRight(text, "\n")
+	var buf strings.Builder
+	buf.WriteString(body)
+	for _, f := range fields {
+		if keep != nil && !keep(f) {
+			continue
+		}
+		buf.WriteString(" ")
+		buf.WriteString(f.String())
+	}
+	buf.WriteString(text[len(body):])
+	return buf.String()
+}
+
+// sortFields sorts fields by key.
+func sortFields(fields []Field) {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+}
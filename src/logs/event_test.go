@@ -0,0 +1,36 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(Llabel | LfunctionName)
+	logr.ErrorE().Str("user", "bob smith").Int("attempt", 3).
+		Dur("took", 1500*time.Millisecond).Err(errors.New("bad password")).
+		Msg("login failed")
+	logr.DebugE().Str("user", "bob").Msg("not shown")
+	expect := "[ERROR]    TestEvent: login failed user=\"bob smith\" attempt=3 " +
+		"took=1.5s error=\"bad password\"\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestEventDisabledAllocs(t *testing.T) {
+	logr := New(LEVEL_ERROR)
+	allocs := testing.AllocsPerRun(100, func() {
+		logr.DebugE().Str("user", "bob").Int("attempt", 3).Msg("not shown")
+	})
+	if allocs != 0 {
+		t.Errorf("Disabled event allocated %v times; want: 0", allocs)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// FailoverWriter is a stream that writes to a primary writer and switches to
+// a secondary writer when the primary fails. Once retryAfter has passed the
+// primary is tried again with the next write, and used again if it succeeds.
+type FailoverWriter struct {
+	mu         sync.Mutex
+	primary    io.Writer
+	secondary  io.Writer
+	retryAfter time.Duration
+	failedAt   time.Time // Zero while the primary is in use
+}
+
+// Failover returns a stream writing to primary, falling back to secondary
+// while primary returns errors. For example, a network sink can fall back to a
+// local file:
+//
+//	log.SetStreams(logs.Failover(conn, file, 30*time.Second))
+func Failover(primary, secondary io.Writer, retryAfter time.Duration) *FailoverWriter {
+	return &FailoverWriter{primary: primary, secondary: secondary,
+		retryAfter: retryAfter}
+}
+
+// Write writes p to the primary writer, or to the secondary writer if the
+// primary failed less than retryAfter ago or fails now.
+func (f *FailoverWriter) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failedAt.IsZero() || time.Since(f.failedAt) >= f.retryAfter {
+		n, err := f.primary.Write(p)
+		if err == nil {
+			f.failedAt = time.Time{}
+			return n, nil
+		}
+		f.failedAt = time.Now()
+		if n > 0 {
+			// Only send the part the primary did not get.
+			m, err := f.secondary.Write(p[n:])
+			return n + m, err
+		}
+	}
+	return f.secondary.Write(p)
+}
+
+// Failed reports whether the secondary writer is in use.
+func (f *FailoverWriter) Failed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.failedAt.IsZero()
+}
@@ -0,0 +1,52 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+type flakyWriter struct {
+	bytes.Buffer
+	down bool
+}
+
+func (f *flakyWriter) Write(p []byte) (int, error) {
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	return f.Buffer.Write(p)
+}
+
+func TestFailover(t *testing.T) {
+	primary := &flakyWriter{}
+	var secondary bytes.Buffer
+	f := Failover(primary, &secondary, 20*time.Millisecond)
+	l := New(LEVEL_DEBUG, f)
+	l.SetFlags(0)
+
+	l.Println("one")
+	primary.down = true
+	l.Println("two")
+	if !f.Failed() {
+		t.Error("expected the secondary writer to be in use")
+	}
+	primary.down = false
+	l.Println("three")
+	time.Sleep(30 * time.Millisecond)
+	l.Println("four")
+	if f.Failed() {
+		t.Error("expected the primary writer to be in use")
+	}
+
+	if expect := "one\nfour\n"; primary.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", primary.String(), expect)
+	}
+	if expect := "two\nthree\n"; secondary.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", secondary.String(), expect)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// fieldProvider is a function added with AddFieldProvider.
+type fieldProvider struct {
+	f func(ctx context.Context, e *Entry)
+}
+
+// AddFieldProvider adds a field provider to the standard logging object. See
+// (*Logger).AddFieldProvider.
+func AddFieldProvider(f func(ctx context.Context, e *Entry)) (remove func()) {
+	return std.AddFieldProvider(f)
+}
+
+// AddFieldProvider adds f to the field providers of l. Field providers are
+// called for every entry before it is formatted and add computed fields to
+// e.Fields, so that call sites do not have to pass them:
+//
+//	logr.AddFieldProvider(func(ctx context.Context, e *logs.Entry) {
+//		if u, ok := ctx.Value(userKey{}).(string); ok {
+//			e.Fields = append(e.Fields, logs.Field{Key: "user", Value: u})
+//		}
+//	})
+//
+// ctx is the context of the event, see (*Event).Ctx, or context.Background()
+// for entries without one. Providers are called in the order they were added
+// with the logger lock held, so they must be fast and must not log to l. Use
+// CachedField for values that are expensive to compute. The returned function
+// removes the provider.
+func (l *Logger) AddFieldProvider(f func(ctx context.Context, e *Entry)) (remove func()) {
+	p := &fieldProvider{f: f}
+	l.mu.Lock()
+	l.fieldProviders = append(l.fieldProviders[:len(l.fieldProviders):len(l.fieldProviders)], p)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, x := range l.fieldProviders {
+			if x == p {
+				l.fieldProviders = append(l.fieldProviders[:i:i], l.fieldProviders[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// provideFields calls the field providers for e and returns its fields. It
+// must be called with the lock held.
+func (l *Logger) provideFields(ctx context.Context, e *Entry) []Field {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// The fields may belong to the caller, providers append to a copy.
+	e.Fields = e.Fields[:len(e.Fields):len(e.Fields)]
+	for _, p := range l.fieldProviders {
+		p.f(ctx, e)
+	}
+	return e.Fields
+}
+
+// CachedField returns a field provider adding the field key with the value
+// returned by f, for example the deployment color read from a file. f is
+// called at most once per ttl. With a ttl of zero or less, f is only called
+// once.
+func CachedField(key string, ttl time.Duration, f func() interface{}) func(ctx context.Context, e *Entry) {
+	var mu sync.Mutex
+	var value interface{}
+	var updated time.Time
+	return func(ctx context.Context, e *Entry) {
+		mu.Lock()
+		if updated.IsZero() || (ttl > 0 && time.Since(updated) >= ttl) {
+			value = f()
+			updated = time.Now()
+		}
+		v := value
+		mu.Unlock()
+		e.Fields = append(e.Fields, Field{key, v})
+	}
+}
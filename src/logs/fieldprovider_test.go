@@ -0,0 +1,43 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+type testUserKey struct{}
+
+func TestFieldProvider(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+
+	calls := 0
+	logr.AddFieldProvider(CachedField("color", 0, func() interface{} {
+		calls++
+		return "blue"
+	}))
+	remove := logr.AddFieldProvider(func(ctx context.Context, e *Entry) {
+		if u, ok := ctx.Value(testUserKey{}).(string); ok {
+			e.Fields = append(e.Fields, Field{Key: "user", Value: u})
+		}
+	})
+
+	ctx := context.WithValue(context.Background(), testUserKey{}, "ann")
+	logr.InfoE().Ctx(ctx).Str("id", "7").Msg("login")
+	logr.Infoln("tick")
+	remove()
+	logr.InfoE().Ctx(ctx).Msg("logout")
+
+	expect := "login id=7 color=blue user=ann\ntick color=blue\nlogout color=blue\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if calls != 1 {
+		t.Errorf("\nGot:\t%d calls\nExpect:\t1 call\n", calls)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"io"
+)
+
+// fieldRoute is a rule added with RouteField.
+type fieldRoute struct {
+	key   string
+	value string
+	w     io.Writer
+}
+
+// RouteField writes entries of the standard logging object with a field to
+// w. See (*Logger).RouteField.
+func RouteField(key, value string, w io.Writer) (remove func()) {
+	return std.RouteField(key, value, w)
+}
+
+// RouteField writes entries with the field key=value to w in addition to the
+// streams of l, for example to keep an audit log per tenant:
+//
+//	f, _ := os.OpenFile("audit-acme.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+//	logr.RouteField("tenant", "acme", f)
+//	logr.WithField("tenant", "acme").Info("invoice paid")
+//
+// Field values are compared in their fmt.Sprint form. Encoders, color modes,
+// and level routes set for w apply as for other streams. Entries written with
+// an explicit stream, such as those of Fprint, are not routed. The returned
+// function removes the rule.
+func (l *Logger) RouteField(key, value string, w io.Writer) (remove func()) {
+	r := &fieldRoute{key: key, value: value, w: w}
+	l.mu.Lock()
+	l.fieldRoutes = append(l.fieldRoutes[:len(l.fieldRoutes):len(l.fieldRoutes)], r)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, x := range l.fieldRoutes {
+			if x == r {
+				l.fieldRoutes = append(l.fieldRoutes[:i:i], l.fieldRoutes[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// routeFields returns streams with the streams of the routes matching fields
+// appended. Streams are only added once.
+func routeFields(routes []*fieldRoute, streams []io.Writer, fields []Field) []io.Writer {
+	if len(fields) == 0 {
+		return streams
+	}
+	out := streams
+	for _, r := range routes {
+		if !hasField(fields, r.key, r.value) || containsStream(out, r.w) {
+			continue
+		}
+		// The streams of the logger are shared, append to a copy.
+		out = append(out[:len(out):len(out)], r.w)
+	}
+	return out
+}
+
+func hasField(fields []Field, key, value string) bool {
+	for _, f := range fields {
+		if f.Key == key && fmt.Sprint(f.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStream(streams []io.Writer, w io.Writer) bool {
+	for _, s := range streams {
+		if sameStream(s, w) {
+			return true
+		}
+	}
+	return false
+}
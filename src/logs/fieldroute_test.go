@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRouteField(t *testing.T) {
+	var all, acme, globex bytes.Buffer
+	logr := New(LEVEL_DEBUG, &all)
+	logr.SetFlags(0)
+	logr.RouteField("tenant", "acme", &acme)
+	remove := logr.RouteField("tenant", "globex", &globex)
+	logr.RouteField("tenant", "all", &all)
+
+	logr.WithField("tenant", "acme").Infoln("invoice paid")
+	logr.WithField("tenant", "globex").Infoln("user added")
+	logr.WithField("tenant", "all").Infoln("once")
+	logr.Infoln("no tenant")
+	remove()
+	logr.WithField("tenant", "globex").Infoln("removed")
+
+	tests := []struct {
+		name        string
+		got, expect string
+	}{
+		{"all", all.String(), "invoice paid tenant=acme\nuser added tenant=globex\n" +
+			"once tenant=all\nno tenant\nremoved tenant=globex\n"},
+		{"acme", acme.String(), "invoice paid tenant=acme\n"},
+		{"globex", globex.String(), "user added tenant=globex\n"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expect {
+			t.Errorf("%s\nGot:\t%q\nExpect:\t%q\n", tt.name, tt.got, tt.expect)
+		}
+	}
+}
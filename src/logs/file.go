@@ -0,0 +1,335 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// SyncPolicy controls when a file stream calls fsync. The zero value never
+// syncs and leaves flushing to the operating system.
+type SyncPolicy struct {
+	level    level
+	onLevel  bool
+	every    int
+	interval time.Duration
+}
+
+// SyncNever leaves flushing to the operating system. This is the default.
+var SyncNever = SyncPolicy{}
+
+// SyncOnLevel syncs after every entry at lvl or above, for example
+// LEVEL_ERROR.
+func SyncOnLevel(lvl level) SyncPolicy { return SyncPolicy{level: lvl, onLevel: true} }
+
+// SyncEvery syncs after every n writes.
+func SyncEvery(n int) SyncPolicy { return SyncPolicy{every: n} }
+
+// SyncInterval syncs every d if anything was written.
+func SyncInterval(d time.Duration) SyncPolicy { return SyncPolicy{interval: d} }
+
+// FileLocking selects how a ReopenableFile keeps the writes of several
+// processes logging to the same file from interleaving.
+type FileLocking int
+
+const (
+	// LockNone writes each entry with a single append. This is the default.
+	LockNone FileLocking = iota
+	// LockFlock holds an exclusive flock(2) lock on the file during each
+	// write. All processes writing the file must use it.
+	LockFlock
+	// LockAtomicAppend splits entries longer than PIPE_BUF bytes into
+	// appends of whole lines no longer than PIPE_BUF, so the lines of
+	// different processes never mix even without locks. A single line
+	// longer than PIPE_BUF is still written with one append.
+	LockAtomicAppend
+)
+
+var errNoFlock = errors.New("logs: file locking is not supported on this platform")
+
+// pipeBuf is the POSIX minimum of PIPE_BUF, the largest write guaranteed to
+// be atomic on pipes. Linux uses 4096.
+const pipeBuf = 4096
+
+// entrySyncer is implemented by streams that sync according to the entry
+// just written to them.
+type entrySyncer interface {
+	syncEntry(e *Entry) error
+}
+
+// ReopenableFile is a file stream that can be closed and opened again at the
+// same path. This lets external tools like logrotate(8) move the file away
+// and signal the program to start a new one, without the races of
+// copytruncate.
+//
+//	f, err := logs.NewReopenableFile("/var/log/app.log")
+//	f.ReopenOnSignal(syscall.SIGHUP)
+//	logr.SetStreams(f)
+type ReopenableFile struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	sigs  chan os.Signal
+	onErr func(error) // Called with errors of reopening on a signal
+
+	archiver  *Archiver  // Run after every reopen
+	retention *Retention // Run after every reopen, after the archiver
+
+	policy  SyncPolicy
+	locking FileLocking
+	pending int           // Writes since the last sync
+	stop    chan struct{} // Stops the interval sync
+}
+
+// NewReopenableFile opens path for appending, creating it if needed.
+func NewReopenableFile(path string) (*ReopenableFile, error) {
+	f := &ReopenableFile{path: path}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *ReopenableFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	f.file = file
+	return nil
+}
+
+// Path returns the path of the file.
+func (f *ReopenableFile) Path() string { return f.path }
+
+// Write appends p to the file.
+func (f *ReopenableFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	f.pending++
+	switch f.locking {
+	case LockFlock:
+		if err := flock(f.file); err != nil {
+			return 0, err
+		}
+		n, err := f.file.Write(p)
+		if uerr := funlock(f.file); err == nil {
+			err = uerr
+		}
+		return n, err
+	case LockAtomicAppend:
+		return writeLines(f.file, p, pipeBuf)
+	}
+	return f.file.Write(p)
+}
+
+// writeLines writes p to w in chunks of whole lines no longer than max, or a
+// single line if it is longer.
+func writeLines(w io.Writer, p []byte, max int) (int, error) {
+	var n int
+	for len(p) > 0 {
+		end := len(p)
+		if end > max {
+			end = bytes.LastIndexByte(p[:max], '\n') + 1
+			if end == 0 {
+				if end = bytes.IndexByte(p, '\n') + 1; end == 0 {
+					end = len(p)
+				}
+			}
+		}
+		m, err := w.Write(p[:end])
+		n += m
+		if err != nil {
+			return n, err
+		}
+		p = p[end:]
+	}
+	return n, nil
+}
+
+// SetLocking sets how writes are kept apart from those of other processes
+// writing the same file. An error is returned if the platform does not
+// support the method.
+func (f *ReopenableFile) SetLocking(l FileLocking) error {
+	if l == LockFlock && !flockSupported {
+		return errNoFlock
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locking = l
+	return nil
+}
+
+// SetSyncPolicy sets when the file is synced to disk.
+func (f *ReopenableFile) SetSyncPolicy(p SyncPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.policy = p
+	if f.stop != nil {
+		close(f.stop)
+		f.stop = nil
+	}
+	if p.interval > 0 {
+		f.stop = make(chan struct{})
+		go f.syncEvery(p.interval, f.stop)
+	}
+}
+
+func (f *ReopenableFile) syncEvery(d time.Duration, stop chan struct{}) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			f.mu.Lock()
+			if f.pending > 0 {
+				f.sync()
+			}
+			f.mu.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sync must be called with the lock held.
+func (f *ReopenableFile) sync() error {
+	f.pending = 0
+	if f.file == nil {
+		return os.ErrClosed
+	}
+	return f.file.Sync()
+}
+
+// Sync commits the file to disk.
+func (f *ReopenableFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sync()
+}
+
+// syncEntry applies the sync policy after e was written. e is nil for raw
+// writes, which are never synced by level.
+func (f *ReopenableFile) syncEntry(e *Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := f.policy
+	if (p.onLevel && e != nil && e.Level >= p.level && e.Level != LEVEL_PRINT) ||
+		(p.every > 0 && f.pending >= p.every) {
+		return f.sync()
+	}
+	return nil
+}
+
+// Reopen closes the file and opens the path again. Writes are blocked until
+// the new file is open. The archiver and the retention policy, if set, then
+// upload and prune the rotated files; their first error is returned, although
+// the file is open again.
+func (f *ReopenableFile) Reopen() error {
+	f.mu.Lock()
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+	err := f.open()
+	a, r := f.archiver, f.retention
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if a != nil {
+		_, err = a.ArchiveNow(context.Background())
+	}
+	if r != nil {
+		if _, rerr := r.PruneNow(); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// SetArchiver sets an Archiver run by Reopen every time the file is rotated,
+// or none if a is nil. Its pattern should match the rotated files only, not
+// the path of the file itself.
+func (f *ReopenableFile) SetArchiver(a *Archiver) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.archiver = a
+}
+
+// SetRetention sets a Retention policy run by Reopen every time the file is
+// rotated, or none if r is nil. Its pattern should match the rotated files
+// only, not the path of the file itself.
+func (f *ReopenableFile) SetRetention(r *Retention) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retention = r
+}
+
+// SetErrorHandler sets a function that is called with the errors of reopening
+// the file on a signal. Until the file is reopened, writes fail with
+// os.ErrClosed.
+func (f *ReopenableFile) SetErrorHandler(h func(error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.onErr = h
+}
+
+// ReopenOnSignal calls Reopen every time one of sig is received, usually
+// SIGHUP, replacing the signals of an earlier call. Errors are passed to the
+// error handler. It is stopped by Close.
+func (f *ReopenableFile) ReopenOnSignal(sig ...os.Signal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sigs != nil {
+		signal.Stop(f.sigs)
+		close(f.sigs)
+	}
+	ch := make(chan os.Signal, 1)
+	f.sigs = ch
+	signal.Notify(ch, sig...)
+	go func() {
+		for range ch {
+			if err := f.Reopen(); err != nil {
+				f.mu.Lock()
+				h := f.onErr
+				f.mu.Unlock()
+				if h != nil {
+					h(err)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops signal handling and interval syncing and closes the file.
+func (f *ReopenableFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sigs != nil {
+		signal.Stop(f.sigs)
+		close(f.sigs)
+		f.sigs = nil
+	}
+	if f.stop != nil {
+		close(f.stop)
+		f.stop = nil
+	}
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
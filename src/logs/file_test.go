@@ -0,0 +1,128 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReopenableFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewReopenableFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	logr := New(LEVEL_DEBUG, f)
+	logr.SetFlags(0)
+	logr.Println("before rotate")
+
+	// Simulate logrotate moving the file away.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	logr.Println("after rotate")
+
+	for p, expect := range map[string]string{
+		path + ".1": "before rotate\n",
+		path:        "after rotate\n",
+	} {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", b, expect)
+		}
+	}
+}
+
+func TestReopenableFileSyncPolicy(t *testing.T) {
+	var tests = []struct {
+		name   string
+		policy SyncPolicy
+		expect int // Writes pending after logging
+	}{
+		{"never", SyncNever, 3},
+		{"level", SyncOnLevel(LEVEL_ERROR), 1},
+		{"every", SyncEvery(2), 1},
+	}
+	for _, test := range tests {
+		f, err := NewReopenableFile(filepath.Join(t.TempDir(), "app.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.SetSyncPolicy(test.policy)
+		logr := New(LEVEL_DEBUG, f)
+		logr.Infoln("one")
+		logr.Errorln("two")
+		logr.Infoln("three")
+		if f.pending != test.expect {
+			t.Errorf("%s\nGot:\t%d\nExpect:\t%d\n", test.name, f.pending, test.expect)
+		}
+		f.Close()
+	}
+}
+
+func TestReopenableFileLocking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	line := strings.Repeat("x", 1000) + "\n"
+	for _, mode := range []FileLocking{LockFlock, LockAtomicAppend} {
+		os.Remove(path)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			// Separate files stand in for separate processes.
+			f, err := NewReopenableFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := f.SetLocking(mode); err != nil {
+				t.Skip(err)
+			}
+			wg.Add(1)
+			go func(f *ReopenableFile) {
+				defer wg.Done()
+				defer f.Close()
+				for j := 0; j < 50; j++ {
+					f.Write([]byte(strings.Repeat(line, 10)))
+				}
+			}(f)
+		}
+		wg.Wait()
+		b, _ := os.ReadFile(path)
+		lines := strings.SplitAfter(string(b), "\n")
+		if len(lines) != 2001 {
+			t.Errorf("mode %d\nGot:\t%d lines\nExpect:\t2000\n", mode, len(lines)-1)
+		}
+		for _, l := range lines[:len(lines)-1] {
+			if l != line {
+				t.Fatalf("mode %d: interleaved line %.40q", mode, l)
+			}
+		}
+	}
+}
+
+type chunkWriter []string
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	*c = append(*c, string(p))
+	return len(p), nil
+}
+
+func TestWriteLines(t *testing.T) {
+	var got chunkWriter
+	writeLines(&got, []byte("aa\nbb\ncc\ndddddd\nee\n"), 6)
+	expect := []string{"aa\nbb\n", "cc\n", "dddddd\n", "ee\n"}
+	if strings.Join(got, "|") != strings.Join(expect, "|") {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenOnSignalError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewReopenableFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	errs := make(chan error, 2)
+	f.SetErrorHandler(func(err error) { errs <- err })
+	f.ReopenOnSignal(syscall.SIGUSR2)
+	f.ReopenOnSignal(syscall.SIGUSR1)
+
+	// The directory is gone, so the file cannot be opened again.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	select {
+	case err := <-errs:
+		if !os.IsNotExist(err) {
+			t.Errorf("\nGot:\t%v\nExpect:\tno such file or directory\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Error handler was not called")
+	}
+	if _, err := f.Write([]byte("lost\n")); err != os.ErrClosed {
+		t.Errorf("\nGot:\t%v\nExpect:\t%v\n", err, os.ErrClosed)
+	}
+}
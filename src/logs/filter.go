@@ -0,0 +1,79 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "regexp"
+
+// Filter drops entries by their message. It is created with FilterOut or
+// FilterOnly and added with AddFilter.
+type Filter struct {
+	re   *regexp.Regexp
+	only bool
+}
+
+// FilterOut returns a filter dropping the entries with a message matching re.
+func FilterOut(re *regexp.Regexp) Filter { return Filter{re: re} }
+
+// FilterOnly returns a filter dropping the entries with a message not
+// matching re. If several FilterOnly filters are added, entries matching any
+// of them are kept.
+func FilterOnly(re *regexp.Regexp) Filter { return Filter{re: re, only: true} }
+
+// AddFilter adds f to the standard logging object. See (*Logger).AddFilter.
+func AddFilter(f Filter) (remove func()) { return std.AddFilter(f) }
+
+// ClearFilters removes the filters of the standard logging object.
+func ClearFilters() { std.ClearFilters() }
+
+// AddFilter adds f to the filters of l. Filters are applied to the message
+// before it is formatted or encoded, so dropped entries are not written to
+// any stream, for example to silence health check requests while they are
+// flooding the logs:
+//
+//	remove := logr.AddFilter(logs.FilterOut(regexp.MustCompile(`GET /healthz`)))
+//	defer remove()
+//
+// The returned function removes the filter.
+func (l *Logger) AddFilter(f Filter) (remove func()) {
+	p := &f
+	l.mu.Lock()
+	l.filters = append(l.filters[:len(l.filters):len(l.filters)], p)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, x := range l.filters {
+			if x == p {
+				l.filters = append(l.filters[:i:i], l.filters[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// ClearFilters removes the filters added with AddFilter.
+func (l *Logger) ClearFilters() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.filters = nil
+}
+
+// filtersPass reports whether text passes filters.
+func filtersPass(filters []*Filter, text string) bool {
+	only, matched := false, false
+	for _, f := range filters {
+		if f.re == nil {
+			continue
+		}
+		m := f.re.MatchString(text)
+		if !f.only && m {
+			return false
+		}
+		if f.only {
+			only = true
+			matched = matched || m
+		}
+	}
+	return !only || matched
+}
@@ -0,0 +1,35 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+
+	remove := logr.AddFilter(FilterOut(regexp.MustCompile(`GET /healthz`)))
+	logr.Infoln("GET /healthz 200")
+	logr.Infoln("GET /users 200")
+	remove()
+	logr.Infoln("GET /healthz 200")
+
+	logr.AddFilter(FilterOnly(regexp.MustCompile(`^db`)))
+	logr.AddFilter(FilterOnly(regexp.MustCompile(`^cache`)))
+	logr.Infoln("db connected")
+	logr.Infoln("cache miss")
+	logr.Infoln("http listening")
+	logr.ClearFilters()
+	logr.Infoln("http listening")
+
+	expect := "GET /users 200\nGET /healthz 200\ndb connected\ncache miss\nhttp listening\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package logs
+
+import "os"
+
+// flockSupported reports whether advisory file locks are available.
+const flockSupported = false
+
+func flock(f *os.File) error { return errNoFlock }
+
+func funlock(f *os.File) error { return errNoFlock }
@@ -0,0 +1,21 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package logs
+
+import (
+	"os"
+	"syscall"
+)
+
+// flockSupported reports whether advisory file locks are available.
+const flockSupported = true
+
+// flock takes an exclusive advisory lock on f, waiting for other processes.
+func flock(f *os.File) error { return syscall.Flock(int(f.Fd()), syscall.LOCK_EX) }
+
+// funlock releases the lock taken by flock.
+func funlock(f *os.File) error { return syscall.Flock(int(f.Fd()), syscall.LOCK_UN) }
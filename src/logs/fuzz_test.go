@@ -0,0 +1,110 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build go1.18
+// +build go1.18
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// fuzzTexts are seeds containing partial escapes, newlines, and invalid
+// UTF-8.
+var fuzzTexts = []string{
+	"",
+	"Hello, World!\n",
+	"\n\n\tleading newlines\n",
+	"line 1\nline 2\n",
+	"\x1b[38;5;196mred\x1b[0;00m",
+	"\x1b[38;5",
+	"\x1b",
+	"\x1b]8;;file:///a\x1b\\link\x1b]8;;\x1b\\",
+	"\x1b]8;;unterminated",
+	"\xff\xfe invalid \xc3",
+	"user={{.Text}} %s %!d(string=x)",
+}
+
+func FuzzSetTemplate(f *testing.F) {
+	f.Add(logFmt, "text\n")
+	f.Add("{{.Tes}}", "text")
+	f.Add("{{bold .Text}}{{.Level | fg 0 0 255}}", "\x1b[1")
+	f.Add("{{initial .Level}}{{.Date}} {{.FileName}}:{{.LineNumber}}] {{.Text}}", "\xff")
+	f.Add("{{template \"x\"}}", "")
+	f.Fuzz(func(t *testing.T, tmpl, text string) {
+		var buf bytes.Buffer
+		logr := New(LEVEL_DEBUG, &buf)
+		logr.SetFlags(LstdFlags | LfunctionName | Lalign)
+		if logr.SetTemplate(tmpl) != nil {
+			return
+		}
+		// Templates that fail to execute panic with the error, as
+		// documented by SetTemplate. Other panics are bugs.
+		defer func() {
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				var execErr template.ExecError
+				if !ok || !errors.As(err, &execErr) && !strings.HasPrefix(err.Error(), "template:") {
+					t.Fatalf("Unexpected panic: %v", r)
+				}
+			}
+		}()
+		logr.Infoln(text)
+	})
+}
+
+func FuzzFprint(f *testing.F) {
+	for _, s := range fuzzTexts {
+		f.Add(s, uint32(LstdFlags))
+		f.Add(s, uint32(Llabel|Lindent|LshowIndent|Lalign|LcolorLabelsOnly))
+	}
+	f.Fuzz(func(t *testing.T, text string, flags uint32) {
+		var plain, stripped bytes.Buffer
+		logr := New(LEVEL_DEBUG, &plain, StripANSI(&stripped))
+		// Flags that read the caller are kept, the others are taken from
+		// the input.
+		logr.SetFlags(int(flags) &^ (Lquiet | Lhyperlink))
+		logr.SetMaxEntrySize(32)
+		logr.SetIndent(1)
+		logr.SetColorMode(&plain, Color16)
+		logr.Warning(text)
+		logr.WithField("text", text).Errorln(text)
+		logr.Write([]byte(text))
+		if logr.Flags()&(Lcolor|LcolorLabelsOnly) == 0 && logr.Flags()&LnoFileAnsi != 0 {
+			if out := plain.String(); stripAnsi(out) != out {
+				t.Errorf("Escapes in output without color: %q", out)
+			}
+		}
+	})
+}
+
+func FuzzStripANSI(f *testing.F) {
+	for _, s := range fuzzTexts {
+		f.Add(s, uint8(3))
+	}
+	f.Fuzz(func(t *testing.T, text string, chunk uint8) {
+		if chunk == 0 {
+			chunk = 1
+		}
+		var buf bytes.Buffer
+		w := StripANSI(&buf)
+		for i := 0; i < len(text); i += int(chunk) {
+			end := i + int(chunk)
+			if end > len(text) {
+				end = len(text)
+			}
+			if n, err := w.Write([]byte(text[i:end])); n != end-i || err != nil {
+				t.Fatalf("Write: %d, %v", n, err)
+			}
+		}
+		// Escapes still incomplete at the end are held back.
+		if whole := stripAnsi(text); !strings.HasPrefix(whole, buf.String()) {
+			t.Errorf("\nGot:\t%q\nExpect prefix of:\t%q\n", buf.String(), whole)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// gcpSeverity maps levels to Cloud Logging severities.
+var gcpSeverity = [...]string{
+	LEVEL_DEBUG:    "DEBUG",
+	LEVEL_INFO:     "INFO",
+	LEVEL_WARNING:  "WARNING",
+	LEVEL_ERROR:    "ERROR",
+	LEVEL_CRITICAL: "CRITICAL",
+	LEVEL_PRINT:    "DEFAULT",
+}
+
+// Special keys of the structured logging format of Google Cloud Logging.
+const (
+	gcpTrace          = "logging.googleapis.com/trace"
+	gcpSpanID         = "logging.googleapis.com/spanId"
+	gcpLabels         = "logging.googleapis.com/labels"
+	gcpSourceLocation = "logging.googleapis.com/sourceLocation"
+)
+
+// GCPEncoder encodes entries in the structured JSON format read by the
+// logging agents of Google Cloud, for example on Cloud Run, GKE, and App
+// Engine. Writing the encoded entries to standard output is all it takes to
+// ship them:
+//
+//	logr.SetStreams(os.Stdout)
+//	logr.SetStreamEncoder(os.Stdout, &logs.GCPEncoder{ProjectID: "my-project"})
+//
+// Levels map to severities and fields become keys of the jsonPayload. The
+// trace_id and span_id fields are linked to Cloud Trace if ProjectID is set,
+// and the caller of the entry becomes the sourceLocation.
+type GCPEncoder struct {
+	ProjectID string            // Used to build trace resource names
+	Labels    map[string]string // Labels added to every entry
+}
+
+// Encode satisfies the Encoder interface.
+func (g *GCPEncoder) Encode(e *Entry) ([]byte, error) {
+	m := make(map[string]interface{}, len(e.Fields)+5)
+	for _, f := range e.Fields {
+		switch {
+		case f.Key == FieldTraceID && g.ProjectID != "":
+			m[gcpTrace] = fmt.Sprintf("projects/%s/traces/%v", g.ProjectID, f.Value)
+		case f.Key == FieldSpanID:
+			m[gcpSpanID] = fmt.Sprint(f.Value)
+		default:
+			m[f.Key] = jsonValue(f.Value)
+		}
+	}
+	m["severity"] = gcpSeverity[e.Level]
+	m["message"] = e.Message
+	m["time"] = e.Time.Format(time.RFC3339Nano)
+	if len(g.Labels) > 0 {
+		m[gcpLabels] = g.Labels
+	}
+	if e.Caller.File != "" {
+		m[gcpSourceLocation] = map[string]string{
+			"file":     e.Caller.File,
+			"line":     strconv.Itoa(e.Caller.Line),
+			"function": e.Caller.Function,
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
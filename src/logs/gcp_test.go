@@ -0,0 +1,47 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestGCPEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetStreamEncoder(&buf, &GCPEncoder{ProjectID: "p1",
+		Labels: map[string]string{"env": "test"}})
+	_, file, line, _ := runtime.Caller(0)
+	logr.WarningE().Str(FieldTraceID, "abc").Str(FieldSpanID, "def").
+		Int("n", 1).Msg("slow")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"severity":                      "WARNING",
+		"message":                       "slow",
+		"n":                             1.0,
+		"logging.googleapis.com/trace":  "projects/p1/traces/abc",
+		"logging.googleapis.com/spanId": "def",
+	}
+	for k, v := range expect {
+		if got[k] != v {
+			t.Errorf("%s\nGot:\t%v\nExpect:\t%v\n", k, got[k], v)
+		}
+	}
+	loc, _ := got["logging.googleapis.com/sourceLocation"].(map[string]interface{})
+	if loc["file"] != file || loc["line"] != strconv.Itoa(line+2) ||
+		loc["function"] != "logs.TestGCPEncoder" {
+		t.Errorf("Got sourceLocation %v", loc)
+	}
+	if labels, _ := got["logging.googleapis.com/labels"].(map[string]interface{}); labels["env"] != "test" {
+		t.Errorf("Got labels %v", labels)
+	}
+}
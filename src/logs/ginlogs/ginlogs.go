@@ -0,0 +1,49 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_gin
+// +build logs_gin
+
+// Package ginlogs adapts the HTTP logging of package logs to gin. It is only
+// built with the logs_gin build tag because it needs the gin module, which is
+// not a dependency of package logs:
+//
+//	r := gin.New()
+//	r.Use(ginlogs.Middleware(logr))
+package ginlogs
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"logs"
+)
+
+// Middleware returns a gin middleware that logs every request like
+// (*logs.Logger).Middleware, including the request id, the request entry, the
+// access log line, and the recovery of panics, which are answered with status
+// 500.
+func Middleware(l *logs.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := l.RequestContext(c.Writer, c.Request)
+		c.Request = c.Request.WithContext(ctx)
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				l.RecoverRequest(ctx, c.Request, v)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+			size := c.Writer.Size()
+			if size < 0 {
+				size = 0
+			}
+			l.LogRequest(ctx, c.Request, c.Writer.Status(), size, start)
+		}()
+		c.Next()
+	}
+}
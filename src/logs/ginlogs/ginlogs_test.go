@@ -0,0 +1,75 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_gin
+// +build logs_gin
+
+package ginlogs
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"logs"
+)
+
+func newRouter(logr *logs.Logger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(logr))
+	r.GET("/pot", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "short and stout")
+	})
+	r.GET("/boom", func(c *gin.Context) {
+		panic("nil map")
+	})
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	r := newRouter(logr)
+
+	req := httptest.NewRequest("GET", "/pot", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusTeapot)
+	}
+	if id := rec.Header().Get("X-Request-ID"); id != "abc123" {
+		t.Errorf("X-Request-ID = %q; want: %q", id, "abc123")
+	}
+	expect := regexp.MustCompile(`^abc123 request method=GET path=/pot status=418 size=15 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	r := newRouter(logr)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusInternalServerError)
+	}
+	expect := regexp.MustCompile(`(?s)^\S+ panic: nil map method=GET path=/boom stack=.*
+\S+ request method=GET path=/boom status=500 size=0 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
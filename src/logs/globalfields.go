@@ -0,0 +1,60 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+// GlobalFields returns the fields added to every entry of the standard logging
+// object.
+func GlobalFields() Fields { return std.GlobalFields() }
+
+// SetGlobalFields sets the fields added to every entry of the standard logging
+// object. See (*Logger).SetGlobalFields.
+func SetGlobalFields(fields Fields) { std.SetGlobalFields(fields) }
+
+// GlobalFields returns the fields added to every entry of l.
+func (l *Logger) GlobalFields() Fields {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	m := make(Fields, len(l.globalFields))
+	for _, f := range l.globalFields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// SetGlobalFields sets fields added to every entry of l, so entries from many
+// services can be told apart once they are aggregated:
+//
+//	logr.SetGlobalFields(logs.Fields{"service": "api", "version": version})
+//
+// The fields are appended to the text, sorted by key, and written by all
+// encoders. Fields of the entry with the same key take precedence. A nil or
+// empty fields removes the global fields.
+func (l *Logger) SetGlobalFields(fields Fields) {
+	var fs []Field
+	if len(fields) > 0 {
+		fs = mapFields(fields)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.globalFields = fs
+}
+
+// addGlobalFields returns fields followed by the global fields not replaced by
+// one of fields.
+func addGlobalFields(fields, global []Field) []Field {
+	out := fields[:len(fields):len(fields)]
+	for _, g := range global {
+		replaced := false
+		for _, f := range fields {
+			if f.Key == g.Key {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, g)
+		}
+	}
+	return out
+}
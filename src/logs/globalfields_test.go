@@ -0,0 +1,37 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGlobalFields(t *testing.T) {
+	var text, js bytes.Buffer
+	logr := New(LEVEL_DEBUG, &text, &js)
+	logr.SetFlags(0)
+	logr.SetStreamEncoder(&js, JSONEncoder{})
+	logr.SetGlobalFields(Fields{"version": "1.2", "service": "api"})
+
+	logr.Infoln("started")
+	logr.WithField("service", "worker").Infoln("job done")
+	logr.SetGlobalFields(nil)
+	logr.Infoln("stopped")
+
+	expect := "started service=api version=1.2\njob done service=worker version=1.2\nstopped\n"
+	if text.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", text.String(), expect)
+	}
+	lines := strings.Split(js.String(), "\n")
+	for _, want := range []string{`"service":"api"`, `"version":"1.2"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", lines[0], want)
+		}
+	}
+	if g := logr.GlobalFields(); len(g) != 0 {
+		t.Errorf("\nGot:\t%v\nExpect:\tno fields\n", g)
+	}
+}
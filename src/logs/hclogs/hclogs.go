@@ -0,0 +1,219 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_hclog
+// +build logs_hclog
+
+// Package hclogs implements the hclog.Logger interface of
+// github.com/hashicorp/go-hclog on top of a logs.Logger, so libraries from
+// the Terraform and Vault ecosystems write through its format and streams.
+// It is only built with the logs_hclog build tag because it needs the
+// go-hclog module, which is not a dependency of package logs:
+//
+//	client := plugin.NewClient(&plugin.ClientConfig{
+//		Logger: hclogs.New(logr, "plugin"),
+//		...
+//	})
+package hclogs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"logs"
+)
+
+// Logger is an hclog.Logger writing to a logs.Logger. The name is written as
+// the logs.FieldLogger field and the implied arguments as fields of every
+// entry.
+type Logger struct {
+	l    *logs.Logger
+	name string
+	args []interface{}
+}
+
+var _ hclog.Logger = (*Logger)(nil)
+
+// New returns an hclog.Logger named name writing to l.
+func New(l *logs.Logger, name string) *Logger { return &Logger{l: l, name: name} }
+
+// event starts an event at the level corresponding to lvl, or returns nil if
+// the entry is not written.
+func (h *Logger) event(lvl hclog.Level) *logs.Event {
+	switch lvl {
+	case hclog.Trace, hclog.Debug:
+		return h.l.DebugE()
+	case hclog.NoLevel, hclog.Info:
+		return h.l.InfoE()
+	case hclog.Warn:
+		return h.l.WarningE()
+	case hclog.Error:
+		return h.l.ErrorE()
+	}
+	return nil
+}
+
+// Log writes msg at lvl with args as key value pairs. A trailing key without
+// a value is written with the key "EXTRA_VALUE_AT_END", as hclog does.
+func (h *Logger) Log(lvl hclog.Level, msg string, args ...interface{}) {
+	e := h.event(lvl)
+	if e == nil {
+		return
+	}
+	if h.name != "" {
+		e.Str(logs.FieldLogger, h.name)
+	}
+	kv := append(append([]interface{}(nil), h.args...), args...)
+	if len(kv)%2 == 1 {
+		kv = append(kv[:len(kv)-1], "EXTRA_VALUE_AT_END", kv[len(kv)-1])
+	}
+	for i := 0; i < len(kv); i += 2 {
+		e.Interface(fmt.Sprint(kv[i]), kv[i+1])
+	}
+	e.Msg(msg)
+}
+
+// Trace writes msg at the LEVEL_DEBUG level.
+func (h *Logger) Trace(msg string, args ...interface{}) { h.Log(hclog.Trace, msg, args...) }
+
+// Debug writes msg at the LEVEL_DEBUG level.
+func (h *Logger) Debug(msg string, args ...interface{}) { h.Log(hclog.Debug, msg, args...) }
+
+// Info writes msg at the LEVEL_INFO level.
+func (h *Logger) Info(msg string, args ...interface{}) { h.Log(hclog.Info, msg, args...) }
+
+// Warn writes msg at the LEVEL_WARNING level.
+func (h *Logger) Warn(msg string, args ...interface{}) { h.Log(hclog.Warn, msg, args...) }
+
+// Error writes msg at the LEVEL_ERROR level.
+func (h *Logger) Error(msg string, args ...interface{}) { h.Log(hclog.Error, msg, args...) }
+
+// IsTrace reports whether LEVEL_DEBUG entries are written.
+func (h *Logger) IsTrace() bool { return h.event(hclog.Trace) != nil }
+
+// IsDebug reports whether LEVEL_DEBUG entries are written.
+func (h *Logger) IsDebug() bool { return h.event(hclog.Debug) != nil }
+
+// IsInfo reports whether LEVEL_INFO entries are written.
+func (h *Logger) IsInfo() bool { return h.event(hclog.Info) != nil }
+
+// IsWarn reports whether LEVEL_WARNING entries are written.
+func (h *Logger) IsWarn() bool { return h.event(hclog.Warn) != nil }
+
+// IsError reports whether LEVEL_ERROR entries are written.
+func (h *Logger) IsError() bool { return h.event(hclog.Error) != nil }
+
+// ImpliedArgs returns the arguments added with With.
+func (h *Logger) ImpliedArgs() []interface{} { return h.args }
+
+// With returns a logger adding args to every entry.
+func (h *Logger) With(args ...interface{}) hclog.Logger {
+	return &Logger{l: h.l, name: h.name,
+		args: append(append([]interface{}(nil), h.args...), args...)}
+}
+
+// Name returns the name of the logger.
+func (h *Logger) Name() string { return h.name }
+
+// Named returns a logger with name appended to the current name, separated
+// by a dot.
+func (h *Logger) Named(name string) hclog.Logger {
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	return h.ResetNamed(name)
+}
+
+// ResetNamed returns a logger named name.
+func (h *Logger) ResetNamed(name string) hclog.Logger {
+	return &Logger{l: h.l, name: name, args: h.args}
+}
+
+// SetLevel sets the level of the underlying logs.Logger, which is shared by
+// all loggers created from h.
+func (h *Logger) SetLevel(lvl hclog.Level) {
+	switch lvl {
+	case hclog.Trace, hclog.Debug:
+		h.l.SetLevel(logs.LEVEL_DEBUG)
+	case hclog.Info:
+		h.l.SetLevel(logs.LEVEL_INFO)
+	case hclog.Warn:
+		h.l.SetLevel(logs.LEVEL_WARNING)
+	case hclog.Error:
+		h.l.SetLevel(logs.LEVEL_ERROR)
+	case hclog.Off:
+		h.l.SetLevel(logs.LEVEL_CRITICAL)
+	}
+}
+
+// GetLevel returns the hclog level matching the level of the underlying
+// logs.Logger.
+func (h *Logger) GetLevel() hclog.Level {
+	switch h.l.Level() {
+	case logs.LEVEL_DEBUG:
+		return hclog.Debug
+	case logs.LEVEL_INFO, logs.LEVEL_PRINT:
+		return hclog.Info
+	case logs.LEVEL_WARNING:
+		return hclog.Warn
+	}
+	return hclog.Error
+}
+
+// StandardLogger returns a standard library logger writing one entry per
+// line, at opts.ForceLevel or LEVEL_INFO.
+func (h *Logger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(h.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns a writer writing one entry per line, at
+// opts.ForceLevel or LEVEL_INFO. With opts.InferLevels, lines starting with
+// "[DEBUG]", "[WARN]", and the like are written at that level.
+func (h *Logger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	lvl := hclog.Info
+	infer := false
+	if opts != nil {
+		infer = opts.InferLevels
+		if opts.ForceLevel != hclog.NoLevel {
+			lvl = opts.ForceLevel
+		}
+	}
+	return &stdWriter{h: h, level: lvl, infer: infer}
+}
+
+// stdWriter writes each line written to it as an entry.
+type stdWriter struct {
+	h     *Logger
+	level hclog.Level
+	infer bool
+}
+
+// inferred maps the prefixes recognized with InferLevels to levels.
+var inferred = []struct {
+	prefix string
+	level  hclog.Level
+}{
+	{"[TRACE]", hclog.Trace}, {"[DEBUG]", hclog.Debug}, {"[INFO]", hclog.Info},
+	{"[WARN]", hclog.Warn}, {"[ERROR]", hclog.Error}, {"[ERR]", hclog.Error},
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		lvl := w.level
+		if w.infer {
+			for _, i := range inferred {
+				if strings.HasPrefix(line, i.prefix) {
+					lvl = i.level
+					line = strings.TrimSpace(line[len(i.prefix):])
+					break
+				}
+			}
+		}
+		w.h.Log(lvl, line)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_hclog
+// +build logs_hclog
+
+package hclogs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+
+	"logs"
+)
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(logs.Llabel)
+	h := New(logr, "")
+	h.Trace("trace")
+	h.Debug("debug")
+	h.Info("info")
+	h.Warn("warn")
+	h.Error("error")
+	expect := "[DEBUG]    trace\n[DEBUG]    debug\n[INFO]     info\n" +
+		"[WARNING]  warn\n[ERROR]    error\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+
+	h.SetLevel(hclog.Warn)
+	if logr.Level() != logs.LEVEL_WARNING || h.GetLevel() != hclog.Warn {
+		t.Errorf("\nGot:\t%s %s\nExpect:\t%s %s\n", logr.Level(), h.GetLevel(),
+			logs.LEVEL_WARNING, hclog.Warn)
+	}
+	if h.IsDebug() || h.IsInfo() || !h.IsWarn() || !h.IsError() {
+		t.Errorf("IsDebug, IsInfo, IsWarn, IsError = %t %t %t %t; want: false false true true",
+			h.IsDebug(), h.IsInfo(), h.IsWarn(), h.IsError())
+	}
+	buf.Reset()
+	h.Info("filtered")
+	if buf.Len() != 0 {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), "")
+	}
+}
+
+func TestWithNamed(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	h := New(logr, "plugin").With("pid", 42)
+	n := h.Named("grpc")
+	n.Info("connected", "addr", "localhost:1234", "dangling")
+	h.Info("exited")
+	if n.Name() != "plugin.grpc" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", n.Name(), "plugin.grpc")
+	}
+	expect := "connected logger=plugin.grpc pid=42 addr=localhost:1234 EXTRA_VALUE_AT_END=dangling\n" +
+		"exited logger=plugin pid=42\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestStandardLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(logs.Llabel)
+	h := New(logr, "")
+	std := h.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true})
+	std.Print("[WARN] disk slow")
+	std.Print("plain\nsecond line")
+	expect := "[WARNING]  disk slow\n[INFO]     plain\n[INFO]     second line\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+
+	buf.Reset()
+	std = h.StandardLogger(&hclog.StandardLoggerOptions{ForceLevel: hclog.Error})
+	std.Print("[DEBUG] forced")
+	if expect := "[ERROR]    [DEBUG] forced\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// healthBuckets is the number of buckets the health window is divided into.
+const healthBuckets = 60
+
+// defaultHealthWindow is the window over which entries are counted by Health.
+const defaultHealthWindow = time.Minute
+
+// healthBucket counts the entries written in a slice of the health window.
+type healthBucket struct {
+	num    int64 // Number of the slice since the Unix epoch
+	counts [LEVEL_PRINT + 1]int
+}
+
+// health tracks the entries written by a logger for Health.
+type health struct {
+	window      time.Duration
+	buckets     [healthBuckets]healthBucket
+	lastError   time.Time
+	lastLevel   level
+	lastMessage string
+}
+
+// width returns the duration covered by one bucket.
+func (h *health) width() int64 {
+	w := h.window
+	if w <= 0 {
+		w = defaultHealthWindow
+	}
+	if w < healthBuckets {
+		w = healthBuckets
+	}
+	return int64(w / healthBuckets)
+}
+
+// observe counts e. It must be called with the logger lock held.
+func (h *health) observe(e *Entry) {
+	n := e.Time.UnixNano() / h.width()
+	b := &h.buckets[n%healthBuckets]
+	if b.num != n {
+		*b = healthBucket{num: n}
+	}
+	b.counts[e.Level]++
+	if e.Level == LEVEL_ERROR || e.Level == LEVEL_CRITICAL {
+		h.lastError = e.Time
+		h.lastLevel = e.Level
+		h.lastMessage = e.Message
+	}
+}
+
+// HealthStatus describes the recent activity of a logger.
+type HealthStatus struct {
+	// LastError is the time of the last ERROR or CRITICAL entry, zero if
+	// there was none.
+	LastError        time.Time `json:"last_error"`
+	LastErrorLevel   string    `json:"last_error_level,omitempty"`
+	LastErrorMessage string    `json:"last_error_message,omitempty"`
+
+	// Counts is the number of entries per level name written during the
+	// last Window.
+	Counts map[string]int `json:"counts"`
+	Window time.Duration  `json:"window_ns"`
+}
+
+// Healthy reports whether no CRITICAL entries were written during the window.
+func (s HealthStatus) Healthy() bool { return s.Counts[levelName(LEVEL_CRITICAL)] == 0 }
+
+// status returns the health status at now. It must be called with the logger
+// lock held.
+func (h *health) status(now time.Time) HealthStatus {
+	s := HealthStatus{
+		LastError: h.lastError,
+		Counts:    make(map[string]int),
+		Window:    time.Duration(h.width() * healthBuckets),
+	}
+	if !h.lastError.IsZero() {
+		s.LastErrorLevel = levelName(h.lastLevel)
+		s.LastErrorMessage = h.lastMessage
+	}
+	n := now.UnixNano() / h.width()
+	for lvl := LEVEL_DEBUG; lvl <= LEVEL_PRINT; lvl++ {
+		s.Counts[levelName(lvl)] = 0
+	}
+	for _, b := range h.buckets {
+		if b.num > n-healthBuckets && b.num <= n {
+			for lvl, c := range b.counts {
+				s.Counts[levelName(level(lvl))] += c
+			}
+		}
+	}
+	return s
+}
+
+// Health returns the health status of the standard logging object.
+func Health() HealthStatus { return std.Health() }
+
+// Health returns the time and message of the last ERROR or CRITICAL entry
+// written by l and the number of entries per level over the health window,
+// one minute by default.
+func (l *Logger) Health() HealthStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.health.status(time.Now())
+}
+
+// SetHealthWindow sets the health window of the standard logging object.
+func SetHealthWindow(d time.Duration) { std.SetHealthWindow(d) }
+
+// SetHealthWindow sets the window over which Health counts entries. The
+// counts are reset.
+func (l *Logger) SetHealthWindow(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.health = health{window: d, lastError: l.health.lastError,
+		lastLevel: l.health.lastLevel, lastMessage: l.health.lastMessage}
+}
+
+// HealthHandler returns a health handler for the standard logging object.
+// See (*Logger).HealthHandler.
+func HealthHandler() http.Handler { return std.HealthHandler() }
+
+// HealthHandler returns a handler that responds with the Health of l as JSON.
+// The status code is 503 if CRITICAL entries were written during the health
+// window, so it can serve as a health check:
+//
+//	http.Handle("/debug/logs/health", logr.HealthHandler())
+func (l *Logger) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := l.Health()
+		w.Header().Set("Content-Type", "application/json")
+		if !s.Healthy() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(s)
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	logr := New(LEVEL_INFO)
+	logr.Infoln("started")
+	logr.Infoln("ready")
+	logr.Errorln("connection lost")
+	s := logr.Health()
+	if s.Counts["info"] != 2 || s.Counts["error"] != 1 || s.Counts["critical"] != 0 {
+		t.Errorf("Counts: %v", s.Counts)
+	}
+	if s.LastErrorMessage != "connection lost" || s.LastErrorLevel != "error" ||
+		time.Since(s.LastError) > time.Minute {
+		t.Errorf("Last error: %v %q %q", s.LastError, s.LastErrorLevel, s.LastErrorMessage)
+	}
+	if s.Window != time.Minute || !s.Healthy() {
+		t.Errorf("Window: %v, Healthy: %v", s.Window, s.Healthy())
+	}
+}
+
+func TestHealthWindow(t *testing.T) {
+	logr := New(LEVEL_INFO)
+	logr.SetHealthWindow(60 * time.Millisecond)
+	logr.Criticalln("out of memory")
+	if s := logr.Health(); s.Counts["critical"] != 1 {
+		t.Errorf("Counts: %v", s.Counts)
+	}
+	time.Sleep(80 * time.Millisecond)
+	s := logr.Health()
+	if s.Counts["critical"] != 0 || s.LastErrorMessage != "out of memory" {
+		t.Errorf("Counts: %v, last error: %q", s.Counts, s.LastErrorMessage)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	logr := New(LEVEL_INFO)
+	logr.Criticalln("disk failed")
+	rec := httptest.NewRecorder()
+	logr.HealthHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Status: %d", rec.Code)
+	}
+	var s HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.LastErrorMessage != "disk failed" || s.Counts["critical"] != 1 {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", rec.Body.String(), "disk failed")
+	}
+}
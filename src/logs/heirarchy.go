@@ -0,0 +1,76 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// stackDepth returns the call depth of the function skip frames above the
+// caller of stackDepth, counted from the function that started the goroutine.
+// The frames are expanded, so inlined calls count the same as other calls.
+// Frames of the runtime, such as those running deferred calls or a panic, and
+// wrappers generated by the compiler are not counted, so the depth does not
+// depend on the Go version.
+func stackDepth(skip int) int {
+	pc := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pc)
+	for n == len(pc) {
+		pc = make([]uintptr, 2*len(pc))
+		n = runtime.Callers(skip+2, pc)
+	}
+	depth := -1
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if !generatedFrame(f) {
+			depth++
+		}
+		if !more {
+			break
+		}
+	}
+	if depth < 0 {
+		return 0
+	}
+	return depth
+}
+
+// generatedFrame reports whether f belongs to the runtime or to a wrapper
+// generated by the compiler, for example for a method value.
+func generatedFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, "runtime.") ||
+		strings.HasSuffix(f.Function, "-fm") || f.File == "<autogenerated>"
+}
+
+// Push returns a copy of ctx whose events are indented one level deeper by
+// the standard logging object. See (*Logger).Push.
+func Push(ctx context.Context) context.Context { return std.Push(ctx) }
+
+// Pop reverses a call to Push on the standard logging object.
+func Pop(ctx context.Context) context.Context { return std.Pop(ctx) }
+
+// Push returns a copy of ctx whose events are indented one level deeper than
+// those of ctx, until Pop is called. The indent carried by a context is used
+// in place of the call depth of Lheirarchical, for example for callbacks run
+// by another package or work handed to a goroutine:
+//
+//	ctx = logr.Push(ctx)
+//	logr.InfoE().Ctx(ctx).Msg("retrying")
+//	ctx = logr.Pop(ctx)
+func (l *Logger) Push(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeDepthKey{}, scopeDepth(ctx)+1)
+}
+
+// Pop returns a copy of ctx whose events are indented one level less than
+// those of ctx. Pop without a matching Push returns ctx.
+func (l *Logger) Pop(ctx context.Context) context.Context {
+	d := scopeDepth(ctx)
+	if d == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeDepthKey{}, d-1)
+}
@@ -0,0 +1,86 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func heirarchyCallee(logr *Logger) {
+	logr.Println("callee")
+	defer func() {
+		logr.Println("deferred")
+	}()
+}
+
+//go:noinline
+func heirarchyNoInline(logr *Logger) {
+	logr.Println("noinline")
+}
+
+// indents returns the number of leading spaces of each line of s by text.
+func indents(s string) map[string]int {
+	m := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		text := strings.TrimLeft(line, " ")
+		m[text] = len(line) - len(text)
+	}
+	return m
+}
+
+func TestHeirarchical(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lheirarchical)
+
+	logr.Println("caller")
+	heirarchyCallee(logr)
+	heirarchyNoInline(logr)
+
+	got := indents(buf.String())
+	base := got["caller"]
+	expect := map[string]int{
+		"caller":   base,
+		"callee":   base + 4,
+		"deferred": base + 8,
+		"noinline": base + 4,
+	}
+	for text, n := range expect {
+		if got[text] != n {
+			t.Errorf("\nGot:\t%q: %d\nExpect:\t%q: %d\n", text, got[text], text, n)
+		}
+	}
+}
+
+func TestPushPop(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lheirarchical)
+
+	ctx := logr.Push(logr.Push(context.Background()))
+	logr.InfoE().Ctx(ctx).Msg("pushed twice")
+	ctx = logr.Pop(ctx)
+	logr.InfoE().Ctx(ctx).Msg("popped")
+	ctx = logr.Pop(logr.Pop(ctx))
+	logr.InfoE().Ctx(ctx).Msg("background")
+
+	got := indents(buf.String())
+	base := got["background"]
+	expect := map[string]int{
+		"pushed twice": 8,
+		"popped":       4,
+		"background":   base,
+	}
+	for text, n := range expect {
+		if got[text] != n {
+			t.Errorf("\nGot:\t%q: %d\nExpect:\t%q: %d\n", text, got[text], text, n)
+		}
+	}
+	if d := scopeDepth(ctx); d != 0 {
+		t.Errorf("\nGot:\t%d\nExpect:\t0\n", d)
+	}
+}
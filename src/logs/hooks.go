@@ -0,0 +1,60 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultFatalHookTimeout is the longest time the Fatal functions wait for
+// fatal hooks before exiting.
+var defaultFatalHookTimeout = 5 * time.Second
+
+// runFatalHooks calls all fatal hooks with e concurrently and waits for them
+// to return, or for the fatal hook timeout to expire.
+func (l *Logger) runFatalHooks(e Entry) {
+	l.mu.Lock()
+	hooks := l.fatalHooks
+	timeout := l.fatalHookTimeout
+	l.mu.Unlock()
+	if len(hooks) == 0 {
+		return
+	}
+	var wg sync.WaitGroup
+	for _, f := range hooks {
+		wg.Add(1)
+		go func(f func(Entry)) {
+			defer wg.Done()
+			f(e)
+		}(f)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// fatal writes text at LEVEL_CRITICAL, the summary, and the crash report,
+// runs the fatal hooks and exits. It must be called directly by the exported Fatal
+// functions so that the caller depth is correct.
+func (l *Logger) fatal(text string) {
+	e := Entry{Time: time.Now(), Level: LEVEL_CRITICAL,
+		Message: strings.Trim(text, "\r\n")}
+	l.Fprint(l.Flags(), LEVEL_CRITICAL, 3, text, nil)
+	if table := l.summaryTable(); table != "" {
+		l.Fprint(l.Flags(), LEVEL_PRINT, 2, table, nil)
+	}
+	l.writeCrashReport()
+	l.ResumeOutput()
+	l.Flush()
+	l.runFatalHooks(e)
+	exit(1)
+}
@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestOnFatal(t *testing.T) {
+	var buf bytes.Buffer
+	var code int
+	defer func(f func(int)) { exit = f }(exit)
+	exit = func(c int) { code = c }
+
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LfunctionName)
+	logr.SetFatalHookTimeout(50 * time.Millisecond)
+	entries := make(chan Entry, 1)
+	logr.OnFatal(func(e Entry) { entries <- e })
+	logr.OnFatal(func(Entry) { select {} })
+
+	start := time.Now()
+	logr.Fatalf("Fatal %s", "error")
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Fatalf() took %s; stuck hook was not timed out", d)
+	}
+	if code != 1 {
+		t.Errorf("exit code = %d; want: 1", code)
+	}
+	got := <-entries
+	if got.Level != LEVEL_CRITICAL || got.Message != "Fatal error" {
+		t.Errorf("Entry = %+v; want: LEVEL_CRITICAL \"Fatal error\"", got)
+	}
+	if expect := "TestOnFatal: Fatal error"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultRequestIDHeader is the header Middleware reads and writes request
+// ids with.
+var defaultRequestIDHeader = "X-Request-ID"
+
+// responseRecorder records the status and size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.size += n
+	return n, err
+}
+
+// Flush sends buffered data to the client if the underlying writer supports
+// it, so streaming handlers such as TailHandler work behind Middleware.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if r.status == 0 {
+			r.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, for example for
+// WebSocket upgrades.
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("logs: %T does not support hijacking", r.ResponseWriter)
+	}
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// Middleware returns a handler that logs every request handled by next using
+// the standard logging object. See (*Logger).Middleware.
+func Middleware(next http.Handler) http.Handler { return std.Middleware(next) }
+
+// SetRequestIDHeader sets the header used for request ids by the Middleware
+// of the standard logging object.
+func SetRequestIDHeader(header string) { std.SetRequestIDHeader(header) }
+
+// Middleware returns a handler that logs every request handled by next at the
+// LEVEL_INFO level with the method, path, status, size, and duration of the
+// request as fields.
+//
+// The request id is taken from the request id header, or generated if the
+// header is missing. It is set on the response header and stored in the
+// request context, so handlers can stamp their own events with it using
+// Ctx(r.Context()).
+//
+// A panic in next is logged with RecoverRequest and answered with status 500.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx := l.RequestContext(w, r)
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			if v := recover(); v != nil {
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				l.RecoverRequest(ctx, r, v)
+				if rec.status == 0 {
+					rec.WriteHeader(http.StatusInternalServerError)
+				}
+			}
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			l.LogRequest(ctx, r, rec.status, rec.size, start)
+		}()
+		next.ServeHTTP(rec, r.WithContext(ctx))
+	})
+}
+
+// RequestContext returns the context of r with the request id from the
+// request id header, or a new one if the header is missing, and sets the
+// header on the response. It is the first step of Middleware, for adapters to
+// other web frameworks.
+func (l *Logger) RequestContext(w http.ResponseWriter, r *http.Request) context.Context {
+	header := l.RequestIDHeader()
+	ctx := r.Context()
+	if id := r.Header.Get(header); id != "" {
+		ctx = ContextWithRequestID(ctx, id)
+	} else {
+		ctx = WithRequestID(ctx)
+	}
+	w.Header().Set(header, RequestID(ctx))
+	return ctx
+}
+
+// LogRequest writes the request entry and the access log line for r, which
+// was answered with status and size bytes and started at start. ctx is the
+// context returned by RequestContext.
+func (l *Logger) LogRequest(ctx context.Context, r *http.Request, status, size int, start time.Time) {
+	l.InfoE().Ctx(ctx).Str("method", r.Method).Str("path", r.URL.Path).
+		Int("status", status).Int("size", size).
+		Dur("duration", time.Since(start)).Msg("request")
+	l.mu.Lock()
+	a := l.accessLog
+	l.mu.Unlock()
+	if a != nil {
+		a.write(r, status, size, start)
+	}
+}
+
+// RecoverRequest logs v, the value of a panic recovered while handling r, at
+// the LEVEL_ERROR level with the stack of the panic as the stack field. It
+// must be called from the deferred function that recovered.
+func (l *Logger) RecoverRequest(ctx context.Context, r *http.Request, v interface{}) {
+	l.ErrorE().Ctx(ctx).Str("method", r.Method).Str("path", r.URL.Path).
+		Str("stack", string(debug.Stack())).Msgf("panic: %v", v)
+}
+
+// RequestIDHeader returns the header used for request ids by Middleware.
+func (l *Logger) RequestIDHeader() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.requestIDHeader
+}
+
+// SetRequestIDHeader sets the header used for request ids by Middleware. The
+// default is "X-Request-ID".
+func (l *Logger) SetRequestIDHeader(header string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requestIDHeader = header
+}
+
+// AccessLogFormat selects the line format of the access log.
+type AccessLogFormat int
+
+const (
+	// CommonLogFormat is the Apache Common Log Format:
+	//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326
+	CommonLogFormat AccessLogFormat = iota
+	// CombinedLogFormat adds the quoted referer and user agent.
+	CombinedLogFormat
+)
+
+// accessLog writes one line per request to a stream.
+type accessLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format AccessLogFormat
+}
+
+// clfField returns s, or "-" if s is empty.
+func clfField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// line returns the access log line of a request.
+func (a *accessLog) line(r *http.Request, status, size int, t time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	user, _, _ := r.BasicAuth()
+	if user == "" && r.URL.User != nil {
+		user = r.URL.User.Username()
+	}
+	uri := r.RequestURI
+	if uri == "" {
+		uri = r.URL.RequestURI()
+	}
+	bytes := "-"
+	if size > 0 {
+		bytes = strconv.Itoa(size)
+	}
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s", clfField(host), clfField(user),
+		t.Format("02/Jan/2006:15:04:05 -0700"), r.Method, uri, r.Proto, status, bytes)
+	if a.format == CombinedLogFormat {
+		line += fmt.Sprintf(" %q %q", clfField(r.Referer()), clfField(r.UserAgent()))
+	}
+	return line + "\n"
+}
+
+func (a *accessLog) write(r *http.Request, status, size int, t time.Time) {
+	line := a.line(r, status, size, t)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	io.WriteString(a.w, line)
+}
+
+// SetAccessLog sets the access log written by the Middleware of the standard
+// logging object. See (*Logger).SetAccessLog.
+func SetAccessLog(w io.Writer, format AccessLogFormat) { std.SetAccessLog(w, format) }
+
+// SetAccessLog makes Middleware write a line in format to w for every
+// request, in addition to the request entry, for analyzers such as GoAccess
+// and AWStats that expect the Apache formats. A nil w stops the access log.
+//
+//	f, err := logs.NewReopenableFile("/var/log/app/access.log")
+//	logr.SetAccessLog(f, logs.CombinedLogFormat)
+func (l *Logger) SetAccessLog(w io.Writer, format AccessLogFormat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w == nil {
+		l.accessLog = nil
+		return
+	}
+	l.accessLog = &accessLog{w: w, format: format}
+}
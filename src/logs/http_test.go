@@ -0,0 +1,140 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.SetRequestIDHeader("X-Trace")
+	h := logr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logr.DebugE().Ctx(r.Context()).Msg("handling")
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	}))
+
+	req := httptest.NewRequest("GET", "/pot", nil)
+	req.Header.Set("X-Trace", "abc123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if id := rec.Header().Get("X-Trace"); id != "abc123" {
+		t.Errorf("X-Trace = %q; want: %q", id, "abc123")
+	}
+	expect := regexp.MustCompile(`^abc123 handling
+abc123 request method=GET path=/pot status=418 size=15 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	ctx := WithRequestID(context.Background())
+	id := RequestID(ctx)
+	if len(id) != 16 {
+		t.Errorf("RequestID() = %q; want: 16 hex characters", id)
+	}
+	if RequestID(WithRequestID(ctx)) != id {
+		t.Errorf("WithRequestID() replaced the existing request id")
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf, access bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	h := logr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	tests := []struct {
+		format AccessLogFormat
+		expect string
+	}{
+		{CommonLogFormat, `^192\.0\.2\.1 - frank \[\d\d/\w\w\w/\d{4}:\d\d:\d\d:\d\d [-+]\d{4}\] "GET /a\?b=c HTTP/1\.1" 200 5
+$`},
+		{CombinedLogFormat, `^192\.0\.2\.1 - frank \[[^]]+\] "GET /a\?b=c HTTP/1\.1" 200 5 "http://example\.com/" "curl/8\.0"
+$`},
+	}
+	for _, test := range tests {
+		access.Reset()
+		logr.SetAccessLog(&access, test.format)
+		req := httptest.NewRequest("GET", "/a?b=c", nil)
+		req.SetBasicAuth("frank", "secret")
+		req.Header.Set("Referer", "http://example.com/")
+		req.Header.Set("User-Agent", "curl/8.0")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if !regexp.MustCompile(test.expect).MatchString(access.String()) {
+			t.Errorf("\nGot:\t%q\nExpect:\t%s\n", access.String(), test.expect)
+		}
+	}
+	if buf.Len() == 0 {
+		t.Error("request entries missing")
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	h := logr.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/boom", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", rec.Code, http.StatusInternalServerError)
+	}
+	expect := regexp.MustCompile(`(?s)^\S+ panic: nil map method=GET path=/boom stack=.*TestMiddlewarePanic.*
+\S+ request method=GET path=/boom status=500 size=0 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestMiddlewarePassthrough(t *testing.T) {
+	var buf syncBuffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/flush", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush() = %v", err)
+		}
+	})
+	mux.HandleFunc("/hijack", func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nok")
+		rw.Flush()
+	})
+	srv := httptest.NewServer(logr.Middleware(mux))
+	defer srv.Close()
+
+	for _, path := range []string{"/flush", "/hijack"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: status %d; want: 200", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+// defaultMaxIds is the number of ids kept by a logging object before the
+// oldest are evicted.
+const defaultMaxIds = 10000
+
+// evictIds removes the oldest ids while there are more than maxIds. Ids used
+// by MuteId, SoloId, or SetIdLevel are kept. It must be called with the lock
+// held.
+func (l *Logger) evictIds() {
+	if l.maxIds <= 0 {
+		return
+	}
+	over := len(l.ids) - l.maxIds
+	if over <= 0 {
+		return
+	}
+	kept := l.idOrder[:0]
+	for _, key := range l.idOrder {
+		num := l.ids[key]
+		if over > 0 && !l.mutedIds[num] && !l.soloIds[num] && !l.hasIdLevel(num) {
+			delete(l.ids, key)
+			over--
+			continue
+		}
+		kept = append(kept, key)
+	}
+	l.idOrder = kept
+}
+
+func (l *Logger) hasIdLevel(num int) bool {
+	_, ok := l.idLevels[num]
+	return ok
+}
+
+// ResetIds clears the ids of the standard logging object. See
+// (*Logger).ResetIds.
+func ResetIds() { std.ResetIds() }
+
+// SetMaxIds sets the number of ids kept by the standard logging object. See
+// (*Logger).SetMaxIds.
+func SetMaxIds(n int) { std.SetMaxIds(n) }
+
+// ResetIds forgets the ids assigned to logging calls, so that numbering starts
+// at one again. The rules set with MuteId, SoloId, and SetIdLevel are removed
+// since their ids would refer to other calls.
+func (l *Logger) ResetIds() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = make(map[string]int)
+	l.idOrder = nil
+	l.lastId = 0
+	l.mutedIds = nil
+	l.soloIds = nil
+	l.idLevels = nil
+}
+
+// SetMaxIds sets the number of ids kept by the logging object. When a new
+// logging call is seen and the limit is reached, the id assigned first is
+// forgotten, and the call gets a new id if it is seen again. Ids used by
+// MuteId, SoloId, or SetIdLevel are never forgotten. A limit of zero or less
+// keeps all ids. The default is 10000.
+func (l *Logger) SetMaxIds(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxIds = n
+	l.evictIds()
+}
@@ -0,0 +1,104 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build !logs_nodebug
+// +build !logs_nodebug
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func idFuncA(logr *Logger) { logr.Debugln("A") }
+
+func idFuncB(logr *Logger) { logr.Debugln("B") }
+
+func TestLid(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lid)
+	idFuncA(logr)
+	idFuncB(logr)
+	idFuncA(logr)
+	if expect := "1 A\n2 B\n1 A\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestMuteId(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lid)
+	idFuncA(logr)
+	idFuncB(logr)
+	buf.Reset()
+
+	logr.MuteId(1)
+	idFuncA(logr)
+	idFuncB(logr)
+	logr.UnmuteId(1)
+	logr.SoloId(1)
+	idFuncA(logr)
+	idFuncB(logr)
+	logr.SoloId()
+	logr.SetLevel(LEVEL_ERROR)
+	logr.SetIdLevel(2, LEVEL_DEBUG)
+	idFuncA(logr)
+	idFuncB(logr)
+
+	if expect := "2 B\n1 A\n2 B\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func idFuncC(logr *Logger) { logr.Debugln("C") }
+
+func TestResetIds(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lid)
+	idFuncA(logr)
+	idFuncB(logr)
+	logr.MuteId(1)
+	logr.ResetIds()
+	idFuncB(logr)
+	idFuncA(logr)
+	if expect := "1 A\n2 B\n1 B\n2 A\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestMaxIds(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lid)
+	logr.SetMaxIds(2)
+	idFuncA(logr)
+	idFuncB(logr)
+	logr.SetIdLevel(1, LEVEL_DEBUG)
+	idFuncC(logr)
+	idFuncA(logr)
+	idFuncB(logr)
+	if expect := "1 A\n2 B\n3 C\n1 A\n4 B\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if len(logr.ids) != 2 {
+		t.Errorf("\nGot:\t%d ids\nExpect:\t2 ids\n", len(logr.ids))
+	}
+}
+
+func TestIdByCallSite(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lid)
+	for i := 0; i < 2; i++ {
+		func() { logr.Debugln("closure") }()
+	}
+	logr.Debugln("first")
+	logr.Debugln("second")
+	if expect := "1 closure\n1 closure\n2 first\n3 second\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+// Name returns the name of the standard logging object.
+func Name() string { return std.Name() }
+
+// SetName sets the name of the standard logging object.
+func SetName(name string) { std.SetName(name) }
+
+// LastEntry returns the last entry written by the standard logging object.
+func LastEntry() Entry { return std.LastEntry() }
+
+// Name returns the name of the logging object.
+func (l *Logger) Name() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.name
+}
+
+// SetName sets the name of the logging object, for example the component it
+// is used by. The name is the LoggerName of the entries and is included by
+// JSONEncoder and the SQL sinks.
+func (l *Logger) SetName(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.name = name
+}
+
+// LastEntry returns the last entry written by l, for debugging and for
+// assertions in tests. Output written with Write does not create entries. The
+// zero Entry is returned if no entry was written.
+func (l *Logger) LastEntry() Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.lastEntry == nil {
+		return Entry{}
+	}
+	return *l.lastEntry
+}
@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLastEntry(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	if e := logr.LastEntry(); !e.Time.IsZero() {
+		t.Errorf("Expected zero entry, got %+v", e)
+	}
+	logr.SetName("db")
+	logr.WithField("table", "users").Warningln("slow query")
+	logr.Debugln("not written")
+	e := logr.LastEntry()
+	if e.Level != LEVEL_WARNING || e.Message != "slow query" || e.LoggerName != "db" ||
+		len(e.Fields) != 1 || e.Fields[0].Key != "table" {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+}
+
+func TestLoggerNameJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetStreamEncoder(&buf, JSONEncoder{})
+	logr.SetName("db")
+	logr.Infoln("connected")
+	logr.InfoE().Str(FieldLogger, "pool").Msg("resized")
+	out := buf.String()
+	if !strings.Contains(out, `"logger":"db","msg":"connected"`) ||
+		!strings.Contains(out, `"logger":"pool","msg":"resized"`) {
+		t.Errorf("\nGot:\t%q\n", out)
+	}
+}
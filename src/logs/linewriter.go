@@ -0,0 +1,95 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"sync"
+	"time"
+)
+
+// defaultLineFlushTimeout is how long a LineWriter holds an incomplete line
+// by default.
+var defaultLineFlushTimeout = time.Second
+
+// LineWriter is a writer that turns output written in arbitrary chunks into
+// one entry per line. Incomplete lines are held until the rest is written, or
+// until FlushTimeout passes without a newline. A LineWriter can bridge the
+// standard library log package:
+//
+//	log.SetOutput(logr.NewLineWriter(logs.LEVEL_INFO, "stdlib: "))
+//	log.SetFlags(0)
+type LineWriter struct {
+	// FlushTimeout is how long an incomplete line is held before it is
+	// written as it is. Zero holds it until Flush or Close.
+	FlushTimeout time.Duration
+
+	mu     sync.Mutex
+	l      *Logger
+	level  level
+	prefix string
+	buf    []byte      // Incomplete line
+	timer  *time.Timer // Flushes the incomplete line
+}
+
+// NewLineWriter returns a LineWriter writing to the standard logging object.
+// See (*Logger).NewLineWriter.
+func NewLineWriter(lvl level, prefix string) *LineWriter { return std.NewLineWriter(lvl, prefix) }
+
+// NewLineWriter returns a LineWriter writing each line as an entry at lvl,
+// with prefix added in front of the line.
+func (l *Logger) NewLineWriter(lvl level, prefix string) *LineWriter {
+	return &LineWriter{FlushTimeout: defaultLineFlushTimeout, l: l, level: lvl,
+		prefix: prefix}
+}
+
+// Write writes an entry for every line completed by p. Line endings may be
+// "\n" or "\r\n".
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	data := w.buf
+	for {
+		advance, line, _ := bufio.ScanLines(data, false)
+		if advance == 0 {
+			break
+		}
+		w.entry(line)
+		data = data[advance:]
+	}
+	w.buf = append(w.buf[:0], data...)
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.buf) > 0 && w.FlushTimeout > 0 {
+		w.timer = time.AfterFunc(w.FlushTimeout, func() { w.Flush() })
+	}
+	return len(p), nil
+}
+
+// entry writes line. It must be called with the lock held.
+func (w *LineWriter) entry(line []byte) {
+	w.l.fprint(w.l.Flags(), w.level, 3, 0, w.prefix+string(line)+"\n", nil, nil)
+}
+
+// Flush writes the incomplete line, if any, as an entry.
+func (w *LineWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.buf) > 0 {
+		_, line, _ := bufio.ScanLines(w.buf, true)
+		w.entry(line)
+		w.buf = w.buf[:0]
+	}
+	return nil
+}
+
+// Close flushes the incomplete line.
+func (w *LineWriter) Close() error { return w.Flush() }
@@ -0,0 +1,56 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"log"
+	"testing"
+	"time"
+)
+
+func TestLineWriter(t *testing.T) {
+	var buf syncBuffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(Llabel)
+	w := l.NewLineWriter(LEVEL_WARNING, "> ")
+	w.FlushTimeout = 0
+	for _, chunk := range []string{"fir", "st\nsec", "ond\r\n", "\nthi", "rd"} {
+		w.Write([]byte(chunk))
+	}
+	expect := "[WARNING]  > first\n[WARNING]  > second\n[WARNING]  > \n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	w.Close()
+	expect += "[WARNING]  > third\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestLineWriterFlushTimeout(t *testing.T) {
+	var buf syncBuffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(0)
+	w := l.NewLineWriter(LEVEL_INFO, "")
+	w.FlushTimeout = 10 * time.Millisecond
+	w.Write([]byte("Password: "))
+	for i := 0; i < 100 && buf.String() == ""; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if expect := "Password: \n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestLineWriterStdlib(t *testing.T) {
+	var buf syncBuffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(Llabel)
+	std := log.New(l.NewLineWriter(LEVEL_ERROR, "stdlib: "), "", 0)
+	std.Printf("listen %s: %s", ":80", "permission denied")
+	if expect := "[ERROR]    stdlib: listen :80: permission denied\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -5,29 +5,43 @@
 // that provides a number of enhancements. Including colored output, logging
 // levels, custom log formatting, and multiple simultaneous output streams like
 // os.Stdout or a File.
+//
+// The functions ending in f take a format and arguments like fmt.Printf and
+// pass them to fmt.Sprintf unchanged, so the printf check of go vet finds
+// mistakes in calls to them without configuration. Versions of vet that do
+// not detect wrappers can be given the list explicitly:
+//
+//	go vet -printfuncs=Debugf,Infof,Warningf,Errorf,Criticalf,Progress,Msgf ./...
+//
+// Formats built at run time can be checked with SetFormatCheck.
 package logs
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
-	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aybabtme/rgbterm"
 )
 
-// Label contains the name of a label as well as the short name and RGB color
-// values.
+// Label contains the name of a label as well as the short name and the style
+// used to color it.
 type Label struct {
-	level    level
-	name     string
-	colorRGB [3]uint8
+	level level
+	name  string
+	style Style
+	short string // Four letter abbreviation used with LshortLabel
+	icon  string // Unicode icon used with Licons
+	ascii string // Icon used when the locale is not UTF-8
 }
 
 // String satisfies the Stringer interface.
@@ -35,34 +49,40 @@ func (l Label) String() string { return l.name }
 
 // Colorized returns the colorized label for console output using ANSI escape
 // sequences.
-func (l Label) Colorized() string {
-	if l.level == LEVEL_PRINT {
-		return l.name
-	}
-	return rgbterm.FgString(l.name, l.colorRGB[0], l.colorRGB[1], l.colorRGB[2])
-}
+func (l Label) Colorized() string { return l.colorize(l.name) }
+
+// Short returns the abbreviated name of the label, for example "[WARN]".
+func (l Label) Short() string { return l.short }
+
+// colorize returns text rendered with the label style.
+func (l Label) colorize(text string) string { return l.style.Render(text) }
 
 // Labels are prefixed to the beginning of a string on output. Labels can be
 // colored.
 var Labels = [6]Label{
 	Label{LEVEL_DEBUG, "[DEBUG]   ",
-		[3]uint8{255, 255, 255}, // White
+		DefaultTheme[LEVEL_DEBUG],
+		"[DEBG]", "✓", "+",
 	},
 
 	Label{LEVEL_INFO, "[INFO]    ",
-		[3]uint8{0, 215, 95}, // Green
+		DefaultTheme[LEVEL_INFO],
+		"[INFO]", "ℹ", "i",
 	},
 
 	Label{LEVEL_WARNING, "[WARNING] ",
-		[3]uint8{255, 255, 135}, // Yellow
+		DefaultTheme[LEVEL_WARNING],
+		"[WARN]", "⚠", "!",
 	},
 
 	Label{LEVEL_ERROR, "[ERROR]   ",
-		[3]uint8{255, 99, 0}, // Orange
+		DefaultTheme[LEVEL_ERROR],
+		"[ERRO]", "✗", "x",
 	},
 
 	Label{LEVEL_CRITICAL, "[CRITICAL]",
-		[3]uint8{255, 0, 0}, // Red
+		DefaultTheme[LEVEL_CRITICAL],
+		"[CRIT]", "☠", "X",
 	},
 
 	Label{level: LEVEL_PRINT}, // LEVEL_PRINT requires no label
@@ -138,8 +158,26 @@ const (
 	LEVEL_PRINT
 )
 
+// Date format presets that can be passed to SetDateFormat.
+const (
+	// DateRFC3339 is the default date format, 2006-01-02T15:04:05Z07:00.
+	DateRFC3339 = time.RFC3339
+
+	// DateRFC3339Nano is DateRFC3339 with nanosecond precision.
+	DateRFC3339Nano = time.RFC3339Nano
+
+	// DateKitchen shows only the time of day, 3:04PM.
+	DateKitchen = time.Kitchen
+
+	// DateUnixMillis outputs the number of milliseconds since the Unix
+	// epoch. time.Format is not used, so this is the fastest option.
+	DateUnixMillis = "unixmillis"
+)
+
 var (
-	defaultDate           = time.RFC3339
+	defaultDate           = DateRFC3339
+	defaultHyperlink      = "file://%[1]s"
+	defaultHexDumpMax     = 4096
 	defaultSeperator      = "::"
 	defaultSeperatorColor = rgbterm.FgString("::", 0, 255, 135) // Green
 	defaultIndentColor    = []uint8{0, 135, 175}                // Grayish blue
@@ -181,6 +219,58 @@ const (
 	// Show the label for output
 	Llabel
 
+	// File name relative to the module root: internal/db/conn.go:23.
+	// Overrides LlongFileName and LshortFileName.
+	LmoduleFileName
+
+	// Wrap the file name in an OSC 8 terminal hyperlink
+	Lhyperlink
+
+	// Align continuation lines of multi-line text under the first line
+	Lalign
+
+	// Use the four letter label abbreviations: [DEBG], [WARN], [CRIT]
+	LshortLabel
+
+	// Show the level icon in front of the label, or in place of the label
+	// if Llabel is not set
+	Licons
+
+	// Show a numeric id for the logging call, assigned by its file and
+	// line. The id can be used with MuteId, SoloId, and SetIdLevel.
+	Lid
+
+	// Audit mode. Number every entry with a sequence number that increases
+	// by one for each entry written, so lost entries can be detected.
+	Laudit
+
+	// Quiet mode. Entries below LEVEL_ERROR are not written regardless of
+	// the logging level. Output of the Print functions is not affected.
+	Lquiet
+
+	// Color only the label and the seperator. The text, including escape
+	// sequences it contains, is written unchanged and indentation is not
+	// colored. Lcolor is not needed with this flag.
+	LcolorLabelsOnly
+
+	// Indent entries by the call depth of the calling function, so that
+	// entries written by callees are nested under those of their callers.
+	// Push and Pop set the depth explicitly where the call stack does not
+	// match the logical nesting.
+	Lheirarchical
+
+	// Show the user name, process id, and parent process id: ann[4242:1].
+	// Instances sharing a log file or syslog destination can be told apart.
+	Lprocess
+
+	// Append the time zone abbreviation to the date, or the offset if the
+	// zone has no abbreviation: 2009-11-10T23:00:00+01:00 CET.
+	Lzone
+
+	// Give every entry a unique id, a ULID that sorts by time. The id is
+	// shown after the date and returned by (*Event).Msg.
+	Lulid
+
 	// initial values for the standard logger
 	LstdFlags = Lseperator | Ldate | Lcolor | LnoFileAnsi | Llabel
 
@@ -191,15 +281,16 @@ const (
 // A Logger represents an active logging object that generates lines of output
 // to an io.Writer. Each logging operation makes a single call to the Writer's
 // Write method. A Logger can be used simultaneously from multiple goroutines;
-// it guarantees to serialize access to the Writer.
+// it guarantees to serialize access to the Writer. Entries are formatted
+// without holding the logger lock, and each stream is written in the order
+// the entries were logged, independently of the other streams.
 type Logger struct {
 	mu               sync.Mutex         // Ensures atomic writes
-	buf              []byte             // For marshaling output to write
 	dateFormat       string             // time.RubyDate is the default format
 	flags            int                // Properties of the output
 	level            level              // The default level is warning
 	lastId           int                // The last id level encountered
-	ids              map[string]int     // A map of encountered function names with corresponding ID
+	ids              map[string]int     // A map of encountered call sites with corresponding ID
 	template         *template.Template // The format order of the output
 	seperator        string             // Inserted into every logging output
 	streams          []io.Writer        // Destination for output
@@ -209,7 +300,64 @@ type Logger struct {
 	tabStop          int   // Number of spaces considered to be a tab stop
 	excludeIDs       []int // Exclude by whatever things
 	excludeFuncNames []string
+	includeCallers   []string // Caller patterns of IncludeCallers
+	excludeCallers   []string // Caller patterns of ExcludeCallers
 	excludeStrings   []string
+	filePrefixes     []string // Trimmed from file names with LmoduleFileName
+	hyperlinkFormat  string   // URL format used with Lhyperlink
+	labelWidth       int      // Labels are padded to this width
+	hexDumpMax       int      // Maximum number of bytes shown by DebugHex
+	maxEntrySize     int      // Longer messages are truncated
+	summary          *summary // Counts of warnings and errors, if enabled
+	alerts           []*Alert // Watch the rate of entries
+	statsd           *StatsD  // Counts entries if set
+	crashDir         string   // Crash reports are written here
+	recent           *ring    // Recent entries included in crash reports
+	fatalHooks       []func(Entry)
+	fatalHookTimeout time.Duration
+	watchdogs        []*watchdog
+	levelHandlers    []*levelHandler
+	filters          []*Filter
+	fieldRoutes      []*fieldRoute
+	fieldProviders   []*fieldProvider
+	globalFields     []Field // Never modified, replaced on change
+	location         *time.Location
+	lastEntry        *Entry
+	health           health
+	runtimeStats     *runtimeStats
+	verbosity        int
+	formatCheck      bool
+	tails            map[*tailClient]bool
+	subscribers      map[*subscriber]bool
+	deadLetter       *deadLetter
+	async            *asyncQueue
+	held             []asyncItem // Output held by SuspendOutput
+	closeTimeout     time.Duration
+	writeTimeout     time.Duration
+	hung             hungWrites
+	turns            map[io.Writer]*turnstile
+	dispatch         turnstile
+	accessLog        *accessLog
+	vmodules         []vmodule
+	mutedIds         map[int]bool  // Ids that produce no output
+	soloIds          map[int]bool  // If set, only these ids produce output
+	idLevels         map[int]level // Logging level overrides per id
+	idOrder          []string      // Keys of ids in the order they were assigned
+	maxIds           int           // Number of ids kept before eviction
+	progress         string        // The active progress line
+	progressLast     time.Time     // Last time progress was written as an entry
+	progressInterval time.Duration // Time between progress entries
+	bannerWidth      int           // Width of banners, zero for the default
+	errorHandler     func(*StreamError)
+	encoders         map[io.Writer]Encoder // Encoders for streams
+	colorModes       map[io.Writer]ColorMode
+	muted            map[io.Writer]bool // Streams not written to
+	routes           map[io.Writer]uint // Levels written to streams, as bits
+	traceExtractor   func(ctx context.Context) (traceID, spanID string)
+	spanEventHook    func(ctx context.Context, e Entry)
+	requestIDHeader  string // Header used by Middleware for request ids
+	name             string // Name of the logger in entries
+	seq              uint64 // Sequence number of the last entry in audit mode
 }
 
 var (
@@ -230,44 +378,54 @@ func New(level level, streams ...io.Writer) (obj *Logger) {
 		seperator:   defaultSeperatorColor,
 		tabStop:     4,
 		indentLevel: -1,
+
+		hyperlinkFormat: defaultHyperlink,
+		hexDumpMax:      defaultHexDumpMax,
+
+		fatalHookTimeout: defaultFatalHookTimeout,
+		closeTimeout:     defaultCloseTimeout,
+		progressInterval: defaultProgressInterval,
+		requestIDHeader:  defaultRequestIDHeader,
+		maxIds:           defaultMaxIds,
 	}
 	return
 }
 
-// Returns the template of the standard logging object.
-func Template() *template.Template { return std.template }
+// Returns a copy of the template of the standard logging object.
+func Template() *template.Template { return std.Template() }
 
 // SetTemplate allocates and parses a new output template for the standard
 // logging object. error is returned if the template fails to parse. If the
 // template cannot be set, then the default template is used. If data field
 // name are misnamed in the template, a panic is produced.
-func SetTemplate(temp string) error {
-	tmpl, err := template.New("default").Funcs(funcMap).Parse(temp)
-	if err != nil {
-		return err
-	}
-	std.template = tmpl
-	return nil
-}
+func SetTemplate(temp string) error { return std.SetTemplate(temp) }
+
+// SetParsedTemplate sets a copy of tmpl as the output template of the
+// standard logging object.
+func SetParsedTemplate(tmpl *template.Template) error { return std.SetParsedTemplate(tmpl) }
 
 // Returns the date format used by the standard logging object as a string.
 func DateFormat() string { return std.dateFormat }
 
 // Set the date format of the standard logging object. See the date package
-// documentation for details on using the date format string.
+// documentation for details on using the date format string. The Date*
+// constants can also be used.
 func SetDateFormat(format string) { std.dateFormat = format }
 
 // Returns the usages flags of the standard logging object.
-func Flags() int { return std.flags }
+func Flags() int { return std.Flags() }
 
 // Set the usage flags for the standard logging object.
-func SetFlags(flags int) { std.flags = flags }
+func SetFlags(flags int) { std.SetFlags(flags) }
+
+// SetQuiet sets or clears the Lquiet flag of the standard logging object.
+func SetQuiet(quiet bool) { std.SetQuiet(quiet) }
 
 // Get the logging level of the standard logging object.
-func Level() level { return std.level }
+func Level() level { return std.Level() }
 
 // Set the logging level of the standard logging object.
-func SetLevel(level level) { std.level = level }
+func SetLevel(level level) { std.SetLevel(level) }
 
 // Get the logging seperator used by the standard logging object. By default it is
 // "::".
@@ -277,10 +435,10 @@ func Seperator() string { return std.seperator }
 func SetSeperator(seperator string) { std.seperator = seperator }
 
 // Streams get the output streams of the standard logger
-func Streams() []io.Writer { return std.streams }
+func Streams() []io.Writer { return std.Streams() }
 
 // SetStreams set the output streams of the standard logger
-func SetStreams(streams ...io.Writer) { std.streams = streams }
+func SetStreams(streams ...io.Writer) { std.SetStreams(streams...) }
 
 // Indent gets the indent level for all output.
 func Indent() int { return std.indent }
@@ -316,9 +474,100 @@ func ExcludeByFuncName(names ...string) {
 	std.excludeFuncNames = names
 }
 
+// FilePrefixes returns the path prefixes trimmed from file names by the
+// standard logging object when the LmoduleFileName flag is used.
+func FilePrefixes() []string { return std.filePrefixes }
+
+// SetFilePrefixes sets the path prefixes trimmed from file names when the
+// LmoduleFileName flag is used. The first matching prefix wins. If no prefix
+// matches, the main module path found in the build info is used.
+func SetFilePrefixes(prefixes ...string) { std.filePrefixes = prefixes }
+
+// SetIcon sets the icon shown for lvl when the Licons flag is used. ascii is
+// shown instead when the locale does not use UTF-8. Icons are shared by all
+// logging objects.
+func SetIcon(lvl level, icon, ascii string) {
+	Labels[lvl].icon = icon
+	Labels[lvl].ascii = ascii
+}
+
+// MuteId suppresses all output from the logging call with the given id. Ids
+// are shown in the output when the Lid flag is used.
+func MuteId(id int) { std.MuteId(id) }
+
+// UnmuteId reverses MuteId for the standard logging object.
+func UnmuteId(id int) { std.UnmuteId(id) }
+
+// SoloId only allows output from the logging calls with the given ids.
+// Calling SoloId without arguments allows output from all calls again.
+func SoloId(ids ...int) { std.SoloId(ids...) }
+
+// SetIdLevel sets the logging level for output from the logging call with the
+// given id, overriding the level of the standard logging object.
+func SetIdLevel(id int, lvl level) { std.SetIdLevel(id, lvl) }
+
+// SetStreamEncoder sets the encoder used for stream by the standard logging
+// object. See (*Logger).SetStreamEncoder for details.
+func SetStreamEncoder(stream io.Writer, enc Encoder) { std.SetStreamEncoder(stream, enc) }
+
+// SetErrorHandler sets a function that is called with every failed stream
+// write of the standard logging object.
+func SetErrorHandler(f func(*StreamError)) { std.SetErrorHandler(f) }
+
+// OnFatal registers f to be called by the Fatal functions of the standard
+// logging object. See (*Logger).OnFatal for details.
+func OnFatal(f func(Entry)) { std.OnFatal(f) }
+
+// SetFatalHookTimeout sets how long the Fatal functions of the standard
+// logging object wait for fatal hooks to return.
+func SetFatalHookTimeout(d time.Duration) { std.SetFatalHookTimeout(d) }
+
+// CrashDir returns the directory crash reports are written to by the standard
+// logging object.
+func CrashDir() string { return std.crashDir }
+
+// SetCrashDir enables crash reports for the standard logging object. See
+// (*Logger).SetCrashDir for details.
+func SetCrashDir(dir string, entries int) { std.SetCrashDir(dir, entries) }
+
+// HexDumpMax returns the maximum number of bytes dumped by DebugHex for the
+// standard logging object.
+func HexDumpMax() int { return std.hexDumpMax }
+
+// SetHexDumpMax sets the maximum number of bytes dumped by DebugHex. The
+// default is 4096. A value of zero or less disables truncation.
+func SetHexDumpMax(max int) { std.hexDumpMax = max }
+
+// MaxEntrySize returns the maximum message size of the standard logging
+// object.
+func MaxEntrySize() int { return std.maxEntrySize }
+
+// SetMaxEntrySize sets the maximum message size of the standard logging
+// object. See (*Logger).SetMaxEntrySize for details.
+func SetMaxEntrySize(n int) { std.SetMaxEntrySize(n) }
+
+// LabelWidth returns the width labels are padded to by the standard logging
+// object.
+func LabelWidth() int { return std.labelWidth }
+
+// SetLabelWidth pads the labels of the standard logging object with spaces to
+// width characters so that output text always starts in the same column.
+// Labels longer than width are not truncated.
+func SetLabelWidth(width int) { std.labelWidth = width }
+
+// HyperlinkFormat returns the URL format used by the standard logging object
+// for the Lhyperlink flag.
+func HyperlinkFormat() string { return std.hyperlinkFormat }
+
+// SetHyperlinkFormat sets the URL format used for the Lhyperlink flag. In the
+// format, %[1]s is the absolute file name and %[2]d is the line number. The
+// default is "file://%[1]s"; editors may use something like
+// "vscode://file%[1]s:%[2]d".
+func SetHyperlinkFormat(format string) { std.hyperlinkFormat = format }
+
 // WithFlags uses flags to write output using the print function passed as f.
 func WithFlags(flags int, f func(...interface{}), args ...interface{}) {
-	cFlags := std.flags
+	cFlags := std.Flags()
 	std.SetFlags(flags)
 	f(args...)
 	std.SetFlags(cFlags)
@@ -328,7 +577,7 @@ func WithFlags(flags int, f func(...interface{}), args ...interface{}) {
 // with the format and arguments specified.
 func WithFlagsf(flags int, f func(string, ...interface{}),
 	format string, args ...interface{}) {
-	cFlags := std.flags
+	cFlags := std.Flags()
 	std.SetFlags(flags)
 	f(format, args...)
 	std.SetFlags(cFlags)
@@ -337,132 +586,143 @@ func WithFlagsf(flags int, f func(string, ...interface{}),
 // Printf formats according to a format specifier and writes to standard
 // logger output stream(s).
 func Printf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_PRINT, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_PRINT, 2, 0, nil, format, v...)
 }
 
 // Print sends output to the standard logger object output stream(s) regardless
 // of logging level. The output is formatted using the output template and
 // flags. Spaces are added between operands when neither is a string.
 func Print(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_PRINT, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_PRINT, 2, fmt.Sprint(v...), nil)
 }
 
 // Println formats using the default formats for its operands and writes to the
 // standard logger output stream(s). Spaces are always added between operands and
 // a newline is appended.
 func Println(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_PRINT, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_PRINT, 2, fmt.Sprintln(v...), nil)
 }
 
 // Panicf is equivalent to Printf(), but panic() is called once output is
 // complete.
 func Panicf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_CRITICAL, 2, 0, nil, format, v...)
+	std.writeCrashReport()
 	panic(v)
 }
 
 // Panic is equivalent to Print(), but panic() is called once output is
 // complete.
 func Panic(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	std.writeCrashReport()
 	panic(v)
 }
 
 // Panicln is equivalent to Println(), but panic() is called once output is
 // complete.
 func Panicln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	std.writeCrashReport()
 	panic(v)
 }
 
-// Debugf is similar to Printf(), except the colorized LEVEL_DEBUG label is
-// prefixed to the output.
-func Debugf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_DEBUG, 2, fmt.Sprintf(format, v...), nil)
+// Fatalf is equivalent to Printf() at the LEVEL_CRITICAL level, but
+// os.Exit(1) is called once output is complete. Hooks registered with OnFatal
+// are run before exiting.
+func Fatalf(format string, v ...interface{}) {
+	std.fatal(fmt.Sprintf(format, v...))
 }
 
-// Debug is similar to Print(), except the colorized LEVEL_DEBUG label is
-// prefixed to the output.
-func Debug(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_DEBUG, 2, fmt.Sprint(v...), nil)
+// Fatal is equivalent to Print() at the LEVEL_CRITICAL level, but os.Exit(1)
+// is called once output is complete.
+func Fatal(v ...interface{}) {
+	std.fatal(fmt.Sprint(v...))
 }
 
-// Debugln is similar to Println(), except the colorized LEVEL_DEBUG label is
-// prefixed to the output.
-func Debugln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_DEBUG, 2, fmt.Sprintln(v...), nil)
+// Fatalln is equivalent to Println() at the LEVEL_CRITICAL level, but
+// os.Exit(1) is called once output is complete.
+func Fatalln(v ...interface{}) {
+	std.fatal(fmt.Sprintln(v...))
 }
 
 // Infof is similar to Printf(), except the colorized LEVEL_INFO label is
 // prefixed to the output.
 func Infof(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_INFO, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_INFO, 2, 0, nil, format, v...)
 }
 
 // Info is similar to Print(), except the colorized LEVEL_INFO label is prefixed
 // to the output.
 func Info(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_INFO, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_INFO, 2, fmt.Sprint(v...), nil)
 }
 
 // Infoln is similar to Println(), except the colorized LEVEL_INFO label is
 // prefixed to the output.
 func Infoln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_INFO, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_INFO, 2, fmt.Sprintln(v...), nil)
 }
 
 // Warningf is similar to Printf(), except the colorized LEVEL_WARNING label is
 // prefixed to the output.
 func Warningf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_WARNING, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_WARNING, 2, 0, nil, format, v...)
 }
 
 // Warning is similar to Print(), except the colorized LEVEL_WARNING label is
 // prefixed to the output.
 func Warning(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_WARNING, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_WARNING, 2, fmt.Sprint(v...), nil)
 }
 
 // Warningln is similar to Println(), except the colorized LEVEL_WARNING label
 // is prefixed to the output.
 func Warningln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_WARNING, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_WARNING, 2, fmt.Sprintln(v...), nil)
 }
 
 // Errorf is similar to Printf(), except the colorized LEVEL_ERROR label is
 // prefixed to the output.
 func Errorf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_ERROR, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_ERROR, 2, 0, nil, format, v...)
 }
 
 // Error is similar to Print(), except the colorized LEVEL_ERROR label is
 // prefixed to the output.
 func Error(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_ERROR, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_ERROR, 2, fmt.Sprint(v...), nil)
 }
 
 // Errorln is similar to Println(), except the colorized LEVEL_ERROR label is
 // prefixed to the output.
 func Errorln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_ERROR, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_ERROR, 2, fmt.Sprintln(v...), nil)
+}
+
+// ErrorErr writes msg and err at the LEVEL_ERROR level. Every error in the
+// chain returned by errors.Unwrap is written on its own indented line, along
+// with the stack trace of errors that provide one.
+func ErrorErr(err error, msg string) {
+	std.Fprint(std.Flags(), LEVEL_ERROR, 2, formatError(msg, err, std.tabStop), nil)
 }
 
 // Criticalf is similar to Printf(), except the colorized LEVEL_CRITICAL label is
 // prefixed to the output.
 func Criticalf(format string, v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprintf(format, v...), nil)
+	std.fprintf(std.Flags(), LEVEL_CRITICAL, 2, 0, nil, format, v...)
 }
 
 // Critical is similar to Prin()t, except the colorized LEVEL_CRITICAL label is
 // prefixed to the output.
 func Critical(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
 }
 
 // Criticalln is similar to Println(), except the colorized LEVEL_CRITICAL label
 // is prefixed to the output.
 func Criticalln(v ...interface{}) {
-	std.Fprint(std.flags, LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	std.Fprint(std.Flags(), LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
 }
 
 // Fprint is used by all of the logging functions to send output to the output
@@ -486,9 +746,56 @@ func Criticalln(v ...interface{}) {
 // Fprint returns the number of bytes written to the stream or an error.
 func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 	text string, stream io.Writer) (n int, err error) {
+	return l.fprint(flags, logLevel, calldepth+1, 0, text, nil, stream)
+}
+
+// fprint implements Fprint. indentCount is the number of indents added to the
+// indent level of the logging object. fields are appended to the text in
+// key=value form and passed to the encoders of the streams.
+func (l *Logger) fprint(flags int, logLevel level, calldepth, indentCount int,
+	text string, fields []Field, stream io.Writer) (n int, err error) {
+	return l.fprintCtx(nil, nil, flags, logLevel, calldepth+1, indentCount, text, fields, stream)
+}
+
+// fprintCtx is fprint for entries with a context, which is passed to the field
+// providers. ctx may be nil. If entryID is not nil, it is set to the id of the
+// entry once the entry passed the filters.
+func (l *Logger) fprintCtx(ctx context.Context, entryID *string, flags int, logLevel level,
+	calldepth, indentCount int, text string, fields []Field, stream io.Writer) (n int, err error) {
+
+	if flags&Lquiet != 0 && logLevel < LEVEL_ERROR {
+		return
+	}
+
+	var pgmC uintptr
+	var file, absFile, fName string
+	var line, absLine int
+	var id string
+
+	// The lock is held while the entry is prepared, including the caller
+	// lookup, since the id map and the configuration are read throughout.
+	// It is released once the entry is prepared. The output is formatted
+	// and written without it, in the order of the tickets taken.
+	l.mu.Lock()
+	locked := true
+	var pending []ticket
+	defer func() {
+		if locked {
+			l.mu.Unlock()
+		}
+		for _, k := range pending {
+			k.pass()
+		}
+	}()
 
+	now := time.Now()
+	if l.location != nil {
+		now = now.In(l.location)
+	}
+
+	idRules := len(l.mutedIds) > 0 || len(l.soloIds) > 0 || len(l.idLevels) > 0
 	if (logLevel != LEVEL_PRINT && l.level != LEVEL_PRINT) &&
-		logLevel < l.level {
+		logLevel < l.level && len(l.idLevels) == 0 {
 		return
 	}
 
@@ -500,26 +807,36 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 			}
 		}
 	}
+	if len(l.filters) > 0 && !filtersPass(l.filters, text) {
+		return
+	}
 
-	now := time.Now()
-	var pgmC uintptr
-	var file, fName string
-	var line int
-	var id string
-	var indentCount int
-
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	if flags&(LlongFileName|LshortFileName|LfunctionName) != 0 ||
-		len(l.excludeFuncNames) > 0 {
+	// Entries written outside of a scope use the depth carried by their
+	// context, if any.
+	if indentCount == 0 {
+		indentCount = scopeDepth(ctx)
+	}
+	if indentCount == 0 && flags&Lheirarchical != 0 {
+		indentCount = stackDepth(calldepth)
+	}
 
-		// release lock while getting caller info - it's expensive.
-		// l.mu.Unlock()
+	summarize := l.summary != nil && logLevel >= LEVEL_WARNING && logLevel != LEVEL_PRINT
+	callerRules := len(l.includeCallers) > 0 || len(l.excludeCallers) > 0
+	// Encoders may include the caller in their output.
+	if flags&(LlongFileName|LshortFileName|LmoduleFileName|LfunctionName|Lid) != 0 ||
+		len(l.excludeFuncNames) > 0 || idRules || summarize || callerRules ||
+		len(l.encoders) > 0 {
 
 		pgmC, file, line, _ = runtime.Caller(calldepth)
+		absFile, absLine = file, line
+
+		if callerRules && !l.callerEnabled(absFile, runtime.FuncForPC(pgmC).Name()) {
+			return
+		}
 
-		if flags&LshortFileName != 0 {
+		if flags&LmoduleFileName != 0 {
+			file = trimFilePath(file, l.filePrefixes)
+		} else if flags&LshortFileName != 0 {
 			short := file
 			for i := len(file) - 1; i > 0; i-- {
 				if file[i] == '/' {
@@ -530,6 +847,16 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 			file = short
 		}
 
+		if flags&Lid != 0 || idRules {
+			num := l.funcId(absFile + ":" + strconv.Itoa(absLine))
+			if !l.idEnabled(num, logLevel) {
+				return
+			}
+			if flags&Lid != 0 {
+				id = strconv.Itoa(num)
+			}
+		}
+
 		if flags&LfunctionName != 0 || len(l.excludeFuncNames) > 0 {
 			fAtPC := runtime.FuncForPC(pgmC)
 			fName = fAtPC.Name()
@@ -540,8 +867,11 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 				}
 			}
 		}
+	}
 
-		// l.mu.Lock()
+	if (logLevel != LEVEL_PRINT && l.level != LEVEL_PRINT) &&
+		logLevel < l.level && !idRules {
+		return
 	}
 
 	// Check func name excludes and return if matches are found
@@ -553,21 +883,80 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 		}
 	}
 
-	l.buf = l.buf[:0] // Reset!
+	// Fields substituted by named placeholders are not appended to the text.
+	fields, inText := unwrapFields(fields)
+	if len(l.globalFields) > 0 {
+		fields = addGlobalFields(fields, l.globalFields)
+	}
+	entry := &Entry{
+		Time:       now,
+		Level:      logLevel,
+		Message:    strings.Trim(text, "\r\n"),
+		Fields:     fields,
+		LoggerName: l.name,
+	}
+	if absFile != "" {
+		entry.Caller = runtime.Frame{PC: pgmC, File: absFile, Line: absLine}
+		if fn := runtime.FuncForPC(pgmC); fn != nil {
+			entry.Caller.Function = fn.Name()
+		}
+	}
+	if len(l.fieldProviders) > 0 {
+		fields = l.provideFields(ctx, entry)
+	}
+	if summarize {
+		l.summary.add(logLevel, absFile, absLine, entry.Message)
+	}
+	for _, a := range l.alerts {
+		a.observe(entry)
+	}
+	if l.statsd != nil {
+		l.statsd.count(logLevel)
+	}
+	for _, w := range l.watchdogs {
+		w.observe(entry)
+	}
+	l.health.observe(entry)
+	if flags&Laudit != 0 {
+		l.seq++
+		entry.Seq = l.seq
+	}
+	if flags&Lprocess != 0 {
+		entry.Pid, entry.PPid, entry.User = os.Getpid(), os.Getppid(), userName()
+	}
+	if flags&Lulid != 0 {
+		entry.ID = newULID(now)
+		if entryID != nil {
+			*entryID = entry.ID
+		}
+	}
+	var requestID string
+	text = appendFields(text, fields, func(f Field) bool {
+		if inText[f.Key] {
+			return false
+		}
+		if f.Key == FieldRequestID {
+			requestID = fmt.Sprint(f.Value)
+			return false
+		}
+		return true
+	})
+	if l.maxEntrySize > 0 {
+		text = truncateText(text, l.maxEntrySize)
+		entry.Message = truncateText(entry.Message, l.maxEntrySize)
+	}
 
 	trimText := strings.TrimLeft(text, "\t\v\r\n")
 	trimedCount := len(text) - len(trimText)
-	if trimedCount > 0 {
-		l.buf = append(l.buf, trimText...)
-	} else {
-		l.buf = append(l.buf, text...)
-	}
 
 	var date string
 	var seperator string
 
 	if flags&Ldate != 0 {
-		date = now.Format(l.dateFormat)
+		date = formatDate(now, l.dateFormat)
+		if flags&Lzone != 0 && l.dateFormat != DateUnixMillis {
+			date += " " + zoneName(now)
+		}
 	}
 
 	if flags&Lseperator != 0 {
@@ -578,8 +967,10 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 		line = 0
 	}
 
-	if flags&(LshortFileName|LlongFileName) == 0 {
+	if flags&(LshortFileName|LlongFileName|LmoduleFileName) == 0 {
 		file = ""
+	} else if flags&Lhyperlink != 0 {
+		file = hyperlink(fmt.Sprintf(l.hyperlinkFormat, absFile, absLine), file)
 	}
 
 	if flags&LfunctionName == 0 {
@@ -599,66 +990,268 @@ func (l *Logger) Fprint(flags int, logLevel level, calldepth int,
 				}
 			}
 		}
-		if len(indent) > 0 && string(indent[0]) != " " {
+		if len(indent) > 0 && string(indent[0]) != " " && flags&LcolorLabelsOnly == 0 {
 			indent = rgbterm.FgString(indent, defaultIndentColor[0],
 				defaultIndentColor[1], defaultIndentColor[2])
 		}
 	}
 
 	var label string
-	if flags&Llabel != 0 {
-		if flags&Lcolor != 0 {
-			label = logLevel.AnsiLabel()
-		} else {
-			label = logLevel.Label()
-		}
+	if flags&(Llabel|Licons) != 0 {
+		label = l.label(flags, logLevel)
 	}
 
 	f := &format{
 		Seperator:    seperator,
 		LogLabel:     label,
+		Level:        strings.ToUpper(levelName(logLevel)),
+		LevelNum:     int(logLevel),
 		Date:         date,
+		Unix:         now.Unix(),
+		UnixMilli:    now.UnixNano() / int64(time.Millisecond),
+		UnixNano:     now.UnixNano(),
 		FileName:     file,
 		FunctionName: fName,
 		LineNumber:   line,
 		Indent:       indent,
 		Id:           id,
-		Text:         string(l.buf),
+		RequestID:    requestID,
+		Seq:          entry.Seq,
+		EntryID:      entry.ID,
+		Pid:          entry.Pid,
+		PPid:         entry.PPid,
+		User:         entry.User,
+		Text:         trimText,
+	}
+
+	tmpl := l.template
+	cfg := l.outputConfig()
+	handlers := l.levelHandlers
+	streams := l.streams
+	if stream != nil {
+		streams = []io.Writer{stream}
+	} else if len(l.fieldRoutes) > 0 {
+		streams = routeFields(l.fieldRoutes, streams, fields)
+	}
+	if stream == nil && progressEntry(ctx) {
+		streams = withoutTerminals(streams)
+	}
+	dispatch := l.dispatch.take()
+	pending = []ticket{dispatch}
+	var tickets []ticket
+	if l.async == nil || stream != nil {
+		tickets = l.takeTurns(streams)
+		pending = append(pending, tickets...)
 	}
+	locked = false
+	l.mu.Unlock()
 
 	var out bytes.Buffer
 	var strippedText, finalText string
 
-	err = l.template.Execute(&out, f)
+	if flags&Lalign != 0 {
+		if err = alignText(tmpl, f); err != nil {
+			panic(err)
+		}
+	}
+
+	err = tmpl.Execute(&out, f)
 	if err != nil {
 		panic(err)
 	}
 
-	if flags&Lcolor == 0 {
+	color := flags&(Lcolor|LcolorLabelsOnly) != 0
+	if !color {
 		strippedText = stripAnsi(out.String())
 	}
 
-	if trimedCount > 0 && flags&Lcolor == 0 {
+	if trimedCount > 0 && !color {
 		finalText = text[:trimedCount] + strippedText
-	} else if trimedCount > 0 && flags&Lcolor != 0 {
+	} else if trimedCount > 0 && color {
 		finalText = text[:trimedCount] + out.String()
-	} else if flags&Lcolor == 0 {
+	} else if !color {
 		finalText = strippedText
 	} else {
 		finalText = out.String()
 	}
 
-	if stream == nil {
-		n, err = l.Write([]byte(finalText))
-	} else {
-		n, err = stream.Write([]byte(finalText))
+	// Entries are kept and queued in the order they were logged.
+	dispatch.wait()
+	pending = pending[1:]
+	l.mu.Lock()
+	l.lastEntry = entry
+	if l.recent != nil {
+		l.recent.add(finalText)
+	}
+	for sub := range l.subscribers {
+		sub.send(entry)
+	}
+	if len(l.tails) > 0 {
+		plain := stripAnsi(finalText)
+		for c := range l.tails {
+			c.send(logLevel, plain)
+		}
+	}
+	q := l.async
+	held := l.held != nil && stream == nil
+	if held {
+		l.held = append(l.held, asyncItem{cfg, streams, []byte(finalText), entry})
+	}
+	l.mu.Unlock()
+	// The entry is queued without the logger lock, so a full queue does not
+	// block the other methods. The dispatch turn keeps the queue in order.
+	queued := false
+	if !held && q != nil && tickets == nil {
+		queued = q.push(asyncItem{cfg, streams, []byte(finalText), entry})
+	}
+	dispatch.done()
+	if len(handlers) > 0 {
+		defer notify(handlers, entry)
+	}
+	if held {
+		return len(finalText), nil
+	}
+	if tickets == nil {
+		if !queued {
+			// Async output was disabled after the entry was prepared.
+			return cfg.output(streams, nil, []byte(finalText), entry)
+		}
+		return len(finalText), nil
 	}
 
-	return
+	pending = nil
+	if stream != nil {
+		// The explicit stream receives the text as formatted, only converted
+		// to the color mode set for it. The flags and encoders of the
+		// logging object apply to its own streams.
+		mode := Color256
+		if len(cfg.colorModes) > 0 && comparable(stream) {
+			if m, ok := cfg.colorModes[stream]; ok {
+				mode = m
+			}
+		}
+		tickets[0].wait()
+		n, err = stream.Write(recolor([]byte(finalText), mode))
+		tickets[0].done()
+		// The turnstile of a stream that is not one of the streams of the
+		// logging object is dropped, so passing a new buffer to every call
+		// does not keep them all.
+		l.mu.Lock()
+		if !l.hasStream(stream) {
+			l.dropTurns(stream)
+		}
+		l.mu.Unlock()
+		return n, err
+	}
+	return cfg.output(streams, tickets, []byte(finalText), entry)
+}
+
+// funcId returns the id of the call site at file:line key, assigning the next
+// id if the call site has not been seen before. Ids start at one.
+func (l *Logger) funcId(key string) int {
+	num, ok := l.ids[key]
+	if !ok {
+		if l.ids == nil {
+			l.ids = make(map[string]int)
+		}
+		l.lastId++
+		num = l.lastId
+		l.ids[key] = num
+		l.idOrder = append(l.idOrder, key)
+		l.evictIds()
+	}
+	return num
+}
+
+// mayWrite reports whether output at lvl can be written at the current level,
+// so the output can be skipped before it is built. Output may still be
+// suppressed by other rules.
+func (l *Logger) mayWrite(lvl level) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return lvl == LEVEL_PRINT || l.level == LEVEL_PRINT || lvl >= l.level ||
+		len(l.idLevels) > 0
+}
+
+// idEnabled reports whether output at logLevel from the function with id num
+// should be written according to the mute, solo, and level rules.
+func (l *Logger) idEnabled(num int, logLevel level) bool {
+	if l.mutedIds[num] || (len(l.soloIds) > 0 && !l.soloIds[num]) {
+		return false
+	}
+	lvl, ok := l.idLevels[num]
+	if !ok {
+		lvl = l.level
+	}
+	return logLevel == LEVEL_PRINT || lvl == LEVEL_PRINT || logLevel >= lvl
+}
+
+// label returns the label text for logLevel using the label flags and label
+// width of the logging object.
+func (l *Logger) label(flags int, logLevel level) string {
+	lbl := Labels[logLevel]
+	var text string
+	if flags&Llabel != 0 && flags&LshortLabel != 0 {
+		text = lbl.short
+	} else if flags&Llabel != 0 {
+		text = lbl.name
+	}
+	if flags&Licons != 0 {
+		icon := lbl.icon
+		if !utf8Locale {
+			icon = lbl.ascii
+		}
+		if icon != "" && text != "" {
+			text = icon + " " + text
+		} else if icon != "" {
+			text = icon
+		}
+	}
+	if text == "" {
+		return ""
+	}
+	if n := l.labelWidth - utf8.RuneCountInString(text); n > 0 {
+		text += strings.Repeat(" ", n)
+	}
+	if flags&(Lcolor|LcolorLabelsOnly) != 0 {
+		return lbl.colorize(text)
+	}
+	return text
+}
+
+// alignText indents the continuation lines of f.Text so they start in the same
+// column as the first line. The column is found by rendering the template
+// without any text.
+func alignText(tmpl *template.Template, f *format) error {
+	body := strings.TrimRight(f.Text, "\n")
+	if !strings.Contains(body, "\n") {
+		return nil
+	}
+	text := f.Text
+	f.Text = ""
+	var prefix bytes.Buffer
+	if err := tmpl.Execute(&prefix, f); err != nil {
+		return err
+	}
+	pad := strings.Repeat(" ", utf8.RuneCountInString(stripAnsi(prefix.String())))
+	f.Text = strings.Replace(body, "\n", "\n"+pad, -1) + text[len(body):]
+	return nil
 }
 
-// Returns the template of the standard logging object.
-func (l *Logger) Template() *template.Template { return l.template }
+// Template returns a copy of the template of the logging object. The active
+// template is never modified once set, so entries can be written with it
+// while the copy is changed. Use SetParsedTemplate to make the changes
+// active.
+func (l *Logger) Template() *template.Template {
+	l.mu.Lock()
+	tmpl := l.template
+	l.mu.Unlock()
+	c, err := tmpl.Clone()
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
 
 // SetTemplate allocates and parses a new output template for the logging
 // object. error is returned if the template fails to parse. If the template
@@ -669,7 +1262,23 @@ func (l *Logger) SetTemplate(temp string) error {
 	if err != nil {
 		return err
 	}
+	l.mu.Lock()
 	l.template = tmpl
+	l.mu.Unlock()
+	return nil
+}
+
+// SetParsedTemplate sets a copy of tmpl as the output template, for example
+// one returned by Template with more templates added. Later changes to tmpl
+// do not affect the logging object.
+func (l *Logger) SetParsedTemplate(tmpl *template.Template) error {
+	c, err := tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.template = c
+	l.mu.Unlock()
 	return nil
 }
 
@@ -677,20 +1286,50 @@ func (l *Logger) SetTemplate(temp string) error {
 func (l *Logger) DateFormat() string { return l.dateFormat }
 
 // Set the date format of the logging object. See the date package
-// documentation for details on using the date format string.
+// documentation for details on using the date format string. The Date*
+// constants can also be used.
 func (l *Logger) SetDateFormat(format string) { l.dateFormat = format }
 
 // Returns the usages flags of the logging object.
-func (l *Logger) Flags() int { return l.flags }
+func (l *Logger) Flags() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flags
+}
 
 // Set the usage flags for the logging object.
-func (l *Logger) SetFlags(flags int) { l.flags = flags }
+func (l *Logger) SetFlags(flags int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flags = flags
+}
+
+// SetQuiet sets or clears the Lquiet flag, for example for a --quiet command
+// line flag. Since the level is left alone, quiet mode can be turned off
+// again without knowing the previous level.
+func (l *Logger) SetQuiet(quiet bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if quiet {
+		l.flags |= Lquiet
+	} else {
+		l.flags &^= Lquiet
+	}
+}
 
 // Get the logging level of the logging object.
-func (l *Logger) Level() level { return l.level }
+func (l *Logger) Level() level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
 
 // Set the logging level of the logging object.
-func (l *Logger) SetLevel(level level) { l.level = level }
+func (l *Logger) SetLevel(level level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = level
+}
 
 // Get the logging seperator used by the logging object. By default it is "::".
 func (l *Logger) Seperator() string { return l.seperator }
@@ -699,10 +1338,18 @@ func (l *Logger) Seperator() string { return l.seperator }
 func (l *Logger) SetSeperator(seperator string) { l.seperator = seperator }
 
 // Get the output streams of the logger
-func (l *Logger) Streams() []io.Writer { return l.streams }
+func (l *Logger) Streams() []io.Writer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.streams
+}
 
 // Set the output streams of the logger
-func (l *Logger) SetStreams(streams ...io.Writer) { l.streams = streams }
+func (l *Logger) SetStreams(streams ...io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.streams = streams
+}
 
 // Indent gets the indent level for all output of the logging object.
 func (l *Logger) Indent() int { return l.indent }
@@ -737,9 +1384,177 @@ func (l *Logger) ExcludeByFuncName(names ...string) {
 	l.excludeFuncNames = names
 }
 
+// FilePrefixes returns the path prefixes trimmed from file names when the
+// LmoduleFileName flag is used.
+func (l *Logger) FilePrefixes() []string { return l.filePrefixes }
+
+// SetFilePrefixes sets the path prefixes trimmed from file names when the
+// LmoduleFileName flag is used. The first matching prefix wins. If no prefix
+// matches, the main module path found in the build info is used.
+func (l *Logger) SetFilePrefixes(prefixes ...string) { l.filePrefixes = prefixes }
+
+// MuteId suppresses all output from the logging call with the given id. Ids
+// are shown in the output when the Lid flag is used.
+func (l *Logger) MuteId(id int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mutedIds == nil {
+		l.mutedIds = make(map[int]bool)
+	}
+	l.mutedIds[id] = true
+}
+
+// UnmuteId reverses MuteId.
+func (l *Logger) UnmuteId(id int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.mutedIds, id)
+}
+
+// SoloId only allows output from the logging calls with the given ids.
+// Calling SoloId without arguments allows output from all calls again.
+func (l *Logger) SoloId(ids ...int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.soloIds = nil
+	for _, id := range ids {
+		if l.soloIds == nil {
+			l.soloIds = make(map[int]bool)
+		}
+		l.soloIds[id] = true
+	}
+}
+
+// SetIdLevel sets the logging level for output from the logging call with the
+// given id, overriding the level of the logging object.
+func (l *Logger) SetIdLevel(id int, lvl level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.idLevels == nil {
+		l.idLevels = make(map[int]level)
+	}
+	l.idLevels[id] = lvl
+}
+
+// SetStreamEncoder sets the encoder used for stream, for example
+// MsgpackEncoder{} or JSONEncoder{}. Entries written to the stream are encoded
+// instead of being formatted with the template. A nil encoder restores the
+// template output. Streams that cannot be used as map keys keep the template
+// output.
+func (l *Logger) SetStreamEncoder(stream io.Writer, enc Encoder) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setStreamEncoder(stream, enc)
+}
+
+// setStreamEncoder implements SetStreamEncoder. It must be called with the
+// logger lock held.
+func (l *Logger) setStreamEncoder(stream io.Writer, enc Encoder) {
+	if !comparable(stream) {
+		return
+	}
+	// The map is replaced rather than modified since output in progress
+	// may still use the old one.
+	encoders := make(map[io.Writer]Encoder, len(l.encoders)+1)
+	for k, v := range l.encoders {
+		encoders[k] = v
+	}
+	if enc == nil {
+		delete(encoders, stream)
+	} else {
+		encoders[stream] = enc
+	}
+	l.encoders = encoders
+}
+
+// SetErrorHandler sets a function that is called with every failed stream
+// write. The handler may be called from several goroutines at once, and is
+// called while the entry still holds its place in line for the remaining
+// streams, so it must not write to the logging object.
+func (l *Logger) SetErrorHandler(f func(*StreamError)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errorHandler = f
+}
+
+// OnFatal registers f to be called by the Fatal functions after the
+// LEVEL_CRITICAL entry is written, but before os.Exit is called. Hooks can be
+// used to flush remote streams or release resources. All hooks run
+// concurrently and the process exits once they return or the fatal hook
+// timeout expires, whichever is first.
+func (l *Logger) OnFatal(f func(Entry)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fatalHooks = append(l.fatalHooks, f)
+}
+
+// SetFatalHookTimeout sets how long the Fatal functions wait for fatal hooks
+// to return. The default is five seconds.
+func (l *Logger) SetFatalHookTimeout(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fatalHookTimeout = d
+}
+
+// CrashDir returns the directory crash reports are written to.
+func (l *Logger) CrashDir() string { return l.crashDir }
+
+// SetCrashDir enables crash reports. When one of the Fatal or Panic functions
+// is called, a timestamped report containing the last entries, the stacks of
+// all goroutines, and the build info is written to dir before exiting.
+// entries is the number of recent entries kept for the report. An empty dir
+// disables crash reports.
+func (l *Logger) SetCrashDir(dir string, entries int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.crashDir = dir
+	l.recent = nil
+	if dir != "" {
+		l.recent = newRing(entries)
+	}
+}
+
+// HexDumpMax returns the maximum number of bytes dumped by DebugHex.
+func (l *Logger) HexDumpMax() int { return l.hexDumpMax }
+
+// SetHexDumpMax sets the maximum number of bytes dumped by DebugHex. The
+// default is 4096. A value of zero or less disables truncation.
+func (l *Logger) SetHexDumpMax(max int) { l.hexDumpMax = max }
+
+// MaxEntrySize returns the maximum message size in bytes.
+func (l *Logger) MaxEntrySize() int { return l.maxEntrySize }
+
+// SetMaxEntrySize limits the size of the message of an entry, including its
+// fields, to n bytes. Longer messages are cut and end with a marker like
+// "...[truncated 12431 bytes]". The date, label, and other template output
+// are kept. A value of zero or less disables truncation, which is the
+// default.
+func (l *Logger) SetMaxEntrySize(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxEntrySize = n
+}
+
+// LabelWidth returns the width labels are padded to.
+func (l *Logger) LabelWidth() int { return l.labelWidth }
+
+// SetLabelWidth pads labels with spaces to width characters so that output
+// text always starts in the same column. Labels longer than width are not
+// truncated.
+func (l *Logger) SetLabelWidth(width int) { l.labelWidth = width }
+
+// HyperlinkFormat returns the URL format used for the Lhyperlink flag.
+func (l *Logger) HyperlinkFormat() string { return l.hyperlinkFormat }
+
+// SetHyperlinkFormat sets the URL format used for the Lhyperlink flag. In the
+// format, %[1]s is the absolute file name and %[2]d is the line number. The
+// default is "file://%[1]s"; editors may use something like
+// "vscode://file%[1]s:%[2]d".
+func (l *Logger) SetHyperlinkFormat(format string) { l.hyperlinkFormat = format }
+
 // WithFlags uses flags to write output using the print function passed as f.
 func (l *Logger) WithFlags(flags int, f func(...interface{}), args ...interface{}) {
-	cFlags := l.flags
+	cFlags := l.Flags()
 	l.SetFlags(flags)
 	f(args...)
 	l.SetFlags(cFlags)
@@ -749,147 +1564,334 @@ func (l *Logger) WithFlags(flags int, f func(...interface{}), args ...interface{
 // with the format and arguments specified.
 func (l *Logger) WithFlagsf(flags int, f func(string, ...interface{}),
 	format string, args ...interface{}) {
-	cFlags := l.flags
+	cFlags := l.Flags()
 	l.SetFlags(flags)
 	f(format, args...)
 	l.SetFlags(cFlags)
 }
 
 // Write writes the array of bytes (p) to all of the logger.Streams. If the
-// Lcolor flag is set, ansi escape codes are used to add coloring to the output.
-func (l *Logger) Write(p []byte) (wLen int, err error) {
-	var write = func(w io.Writer, isStdFile bool) {
-		x := p
-		if !isStdFile && l.flags&LnoFileAnsi != 0 {
-			// TODO: If Lcolor is used, then no coloring should
-			// have to be stripped. Inefficient.
-			x = stripAnsiByte(x)
-		}
-		wLen, err = w.Write(x)
-		if wLen != len(p) {
-			err = io.ErrShortWrite
-		}
-	}
-	for _, w := range l.streams {
-		wIface := reflect.ValueOf(w).Interface()
-		switch wType := wIface.(type) {
-		case *os.File:
-			if wType == os.Stdout || wType == os.Stderr {
-				write(w, true)
-				continue
+// LnoFileAnsi flag is set, ansi escape codes are stripped from the output
+// written to streams other than os.Stdout and os.Stderr, see SetColorMode.
+// Each variant of the output is created once and shared by all streams using
+// it, and p itself is never modified.
+//
+// Every stream receives the complete output, even if writing to an earlier
+// stream failed. Failed writes are passed to the error handler and returned
+// together as a WriteError, in which case n is zero. Otherwise n is len(p).
+//
+// Streams with an encoder receive p as the message of an entry at the
+// LEVEL_PRINT level. With async output, p is queued and write errors are only
+// passed to the error handler.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	return l.writeStreams(nil, p, nil)
+}
+
+// writeStreams implements Write for the streams keep returns true for, or all
+// streams if keep is nil. e is passed to cfg.output.
+func (l *Logger) writeStreams(keep func(io.Writer) bool, p []byte, e *Entry) (n int, err error) {
+	l.mu.Lock()
+	cfg := l.outputConfig()
+	streams := l.streams
+	if keep != nil {
+		streams = nil
+		for _, w := range l.streams {
+			if keep(w) {
+				streams = append(streams, w)
 			}
-			write(w, false)
-		default:
-			write(w, false)
 		}
 	}
-	return
+	if l.async == nil && l.held == nil {
+		tickets := l.takeTurns(streams)
+		l.mu.Unlock()
+		return cfg.output(streams, tickets, p, e)
+	}
+	dispatch := l.dispatch.take()
+	l.mu.Unlock()
+	dispatch.wait()
+	defer dispatch.done()
+	l.mu.Lock()
+	if l.held != nil {
+		l.held = append(l.held, asyncItem{cfg, streams, append([]byte(nil), p...), e})
+		l.mu.Unlock()
+		return len(p), nil
+	}
+	q := l.async
+	l.mu.Unlock()
+	if q != nil && q.push(asyncItem{cfg, streams, append([]byte(nil), p...), e}) {
+		return len(p), nil
+	}
+	return cfg.output(streams, nil, p, e)
+}
+
+// outputConfig holds the settings used to write to the streams, copied while
+// the logger lock is held so the streams can be written without it.
+type outputConfig struct {
+	flags        int
+	encoders     map[io.Writer]Encoder // Never modified, replaced on change
+	colorModes   map[io.Writer]ColorMode
+	muted        map[io.Writer]bool
+	routes       map[io.Writer]uint
+	errorHandler func(*StreamError)
+	progress     string
+	deadLetter   *deadLetter
+	writeTimeout time.Duration
+	hung         *hungWrites
+}
+
+// outputConfig returns the current output settings. It must be called with
+// the logger lock held.
+func (l *Logger) outputConfig() outputConfig {
+	return outputConfig{
+		flags:        l.flags,
+		encoders:     l.encoders,
+		colorModes:   l.colorModes,
+		muted:        l.muted,
+		routes:       l.routes,
+		errorHandler: l.errorHandler,
+		progress:     l.progress,
+		deadLetter:   l.deadLetter,
+		writeTimeout: l.writeTimeout,
+		hung:         &l.hung,
+	}
+}
+
+// output writes p to streams. Streams with an encoder receive e encoded
+// instead. If e is nil, an entry is created from p when it is needed. Each
+// encoding is only created once. If tickets is not nil, each stream is written
+// when it is the turn of the ticket with the same index. Muted streams and
+// streams not routed the level of the entry are skipped.
+func (c outputConfig) output(streams []io.Writer, tickets []ticket, p []byte, e *Entry) (n int, err error) {
+	var recolored [ColorTrue][]byte
+	lvl := LEVEL_PRINT
+	if e != nil {
+		lvl = e.Level
+	}
+	var encoded map[Encoder][]byte
+	var errs WriteError
+	for i, w := range streams {
+		var se *StreamError
+		func() {
+			if tickets != nil {
+				tickets[i].wait()
+				defer tickets[i].done()
+			}
+			if !c.enabled(w, lvl) {
+				return
+			}
+			se = c.write(w, p, &e, &recolored, &encoded)
+		}()
+		if se != nil {
+			errs = append(errs, se)
+			if c.errorHandler != nil {
+				c.errorHandler(se)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return 0, errs
+	}
+	return len(p), nil
+}
+
+// enabled reports whether output at lvl is written to w.
+func (c outputConfig) enabled(w io.Writer, lvl level) bool {
+	if (len(c.muted) == 0 && len(c.routes) == 0) || !comparable(w) {
+		return true
+	}
+	if c.muted[w] {
+		return false
+	}
+	mask, ok := c.routes[w]
+	return !ok || mask&(1<<uint(lvl)) != 0
+}
+
+// write writes p, or the entry encoded, to w. The entry, the output converted
+// to the color mode of w, and the encodings are created when needed and kept
+// for the other streams.
+func (c outputConfig) write(w io.Writer, p []byte, e **Entry, recolored *[ColorTrue][]byte,
+	encoded *map[Encoder][]byte) *StreamError {
+	x := p
+	var enc Encoder
+	if len(c.encoders) > 0 && comparable(w) {
+		enc = c.encoders[w]
+	}
+	if enc != nil {
+		if *e == nil {
+			*e = &Entry{Time: time.Now(), Level: LEVEL_PRINT,
+				Message: strings.Trim(string(p), "\r\n")}
+		}
+		if *encoded == nil {
+			*encoded = make(map[Encoder][]byte)
+		}
+		b, ok := (*encoded)[enc]
+		if !ok {
+			var eerr error
+			if b, eerr = enc.Encode(*e); eerr != nil {
+				return &StreamError{Stream: w, Err: eerr}
+			}
+			(*encoded)[enc] = b
+		}
+		x = b
+	} else if mode := c.colorMode(w); mode != ColorTrue {
+		if (*recolored)[mode] == nil {
+			(*recolored)[mode] = recolor(p, mode)
+		}
+		x = (*recolored)[mode]
+	}
+	// Keep an active progress line below the regular output
+	redraw := c.progress != "" && isTerminal(w)
+	if redraw {
+		io.WriteString(w, eraseLine)
+	}
+	var wn int
+	var werr error
+	if c.writeTimeout > 0 {
+		wn, werr = c.hung.timedWrite(w, x, c.writeTimeout)
+	} else {
+		wn, werr = w.Write(x)
+	}
+	if werr == nil && wn != len(x) {
+		werr = io.ErrShortWrite
+	}
+	if s, ok := w.(entrySyncer); ok && werr == nil {
+		werr = s.syncEntry(*e)
+	}
+	if werr != nil && c.deadLetter != nil && IsPermanent(werr) {
+		if *e == nil {
+			*e = &Entry{Time: time.Now(), Level: LEVEL_PRINT,
+				Message: strings.Trim(string(p), "\r\n")}
+		}
+		var text []byte
+		if enc == nil {
+			text = x
+		}
+		if derr := c.deadLetter.add(*e, w, text, werr); derr != nil {
+			werr = fmt.Errorf("%v (dead-letter file: %v)", werr, derr)
+		}
+	}
+	if redraw && bytes.HasSuffix(x, []byte("\n")) {
+		io.WriteString(w, c.progress)
+	}
+	if werr != nil {
+		return &StreamError{Stream: w, Written: wn, Err: werr}
+	}
+	return nil
 }
 
 // Printf is equivalent to log.Printf().
 func (l *Logger) Printf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_PRINT, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_PRINT, 2, 0, nil, format, v...)
 }
 
 // Print is equivalent to log.Print().
 func (l *Logger) Print(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_PRINT, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_PRINT, 2, fmt.Sprint(v...), nil)
 }
 
 // Println is equivalent to log.Println().
 func (l *Logger) Println(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_PRINT, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_PRINT, 2, fmt.Sprintln(v...), nil)
 }
 
 // Panicf is equivalent to log.Panicf().
 func (l *Logger) Panicf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_CRITICAL, 2, 0, nil, format, v...)
+	l.writeCrashReport()
 	panic(v)
 }
 
 // Panic is equivalent to log.Panic().
 func (l *Logger) Panic(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	l.writeCrashReport()
 	panic(v)
 }
 
 // Panicln is equivalent to log.Panicln().
 func (l *Logger) Panicln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	l.writeCrashReport()
 	panic(v)
 }
 
-// Debugf is equivalent to log.Debugf().
-func (l *Logger) Debugf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_DEBUG, 2, fmt.Sprintf(format, v...), nil)
+// Fatalf is equivalent to log.Fatalf().
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.fatal(fmt.Sprintf(format, v...))
 }
 
-// Debug is equivalent to log.Debug().
-func (l *Logger) Debug(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_DEBUG, 2, fmt.Sprint(v...), nil)
+// Fatal is equivalent to log.Fatal().
+func (l *Logger) Fatal(v ...interface{}) {
+	l.fatal(fmt.Sprint(v...))
 }
 
-// Debugln is equivalent to log.Debugln().
-func (l *Logger) Debugln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_DEBUG, 2, fmt.Sprintln(v...), nil)
+// Fatalln is equivalent to log.Fatalln().
+func (l *Logger) Fatalln(v ...interface{}) {
+	l.fatal(fmt.Sprintln(v...))
 }
 
 // Infof is equivalent to log.Infof().
 func (l *Logger) Infof(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_INFO, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_INFO, 2, 0, nil, format, v...)
 }
 
 // Info is equivalent to log.Info().
 func (l *Logger) Info(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_INFO, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_INFO, 2, fmt.Sprint(v...), nil)
 }
 
 // Infoln is equivalent to log.Infoln().
 func (l *Logger) Infoln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_INFO, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_INFO, 2, fmt.Sprintln(v...), nil)
 }
 
 // Warningf is equivalent to log.Warningf().
 func (l *Logger) Warningf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_WARNING, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_WARNING, 2, 0, nil, format, v...)
 }
 
 // Warning is equivalent to log.Warning().
 func (l *Logger) Warning(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_WARNING, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_WARNING, 2, fmt.Sprint(v...), nil)
 }
 
 // Warningln is equivalent to log.Warningln().
 func (l *Logger) Warningln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_WARNING, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_WARNING, 2, fmt.Sprintln(v...), nil)
 }
 
 // Errorf is equivalent to log.Errorf().
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_ERROR, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_ERROR, 2, 0, nil, format, v...)
 }
 
 // Error is equivalent to log.Error().
 func (l *Logger) Error(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_ERROR, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_ERROR, 2, fmt.Sprint(v...), nil)
 }
 
 // Errorln is equivalent to log.Errorln().
 func (l *Logger) Errorln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_ERROR, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_ERROR, 2, fmt.Sprintln(v...), nil)
+}
+
+// ErrorErr writes msg and the unwrap chain of err at the LEVEL_ERROR level.
+// See ErrorErr() for details.
+func (l *Logger) ErrorErr(err error, msg string) {
+	l.Fprint(l.Flags(), LEVEL_ERROR, 2, formatError(msg, err, l.tabStop), nil)
 }
 
 // Criticalf is equivalent to log.Criticalf().
 func (l *Logger) Criticalf(format string, v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprintf(format, v...), nil)
+	l.fprintf(l.Flags(), LEVEL_CRITICAL, 2, 0, nil, format, v...)
 }
 
 // Critical is equivalent to log.Critical().
 func (l *Logger) Critical(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_CRITICAL, 2, fmt.Sprint(v...), nil)
 }
 
 // Criticalln is equivalent to log.Criticalln().
 func (l *Logger) Criticalln(v ...interface{}) {
-	l.Fprint(l.flags, LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
+	l.Fprint(l.Flags(), LEVEL_CRITICAL, 2, fmt.Sprintln(v...), nil)
 }
@@ -42,6 +42,7 @@ func TestStdTemplate(t *testing.T) {
 }
 
 func TestStdSetTemplate(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_DEBUG, &buf)
@@ -78,6 +79,7 @@ func TestStdSetTemplateBad(t *testing.T) {
 }
 
 func TestStdSetTemplateBadDataObjectPanic(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_DEBUG, &buf)
@@ -119,6 +121,7 @@ func TestStdDateFormat(t *testing.T) {
 }
 
 func TestStdSetDateFormat(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_PRINT, &buf)
@@ -190,6 +193,7 @@ func TestStdStreams(t *testing.T) {
 }
 
 func TestStdCarriageReturn(t *testing.T) {
+	skipNoDebug(t)
 	// See https://github.com/demizer/go-logs/issues/11
 	var buf bytes.Buffer
 
@@ -208,6 +212,7 @@ func TestStdCarriageReturn(t *testing.T) {
 }
 
 func TestStdIndent(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_DEBUG, &buf)
@@ -233,6 +238,7 @@ func TestStdIndent(t *testing.T) {
 }
 
 func TestStdTabStop(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_DEBUG, &buf)
@@ -266,6 +272,7 @@ func TestStdTabStop(t *testing.T) {
 // TestStdLnoFileAnsi verifies output sent to os.Stdout contains color codes
 // and output sent to a file does not.
 func TestStdLnoFileAnsi(t *testing.T) {
+	skipNoDebug(t)
 	std = New(LEVEL_DEBUG)
 	SetFlags(Lseperator | Llabel | Lcolor | LnoFileAnsi)
 
@@ -318,6 +325,7 @@ var stdOutputTests = []struct {
 }
 
 func TestStdOutput(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	std = New(LEVEL_DEBUG, &buf)
@@ -459,6 +467,7 @@ func TestStdPanicf(t *testing.T) {
 }
 
 func TestStdExcludeByString(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	for _, test := range excludeByStringTests {
@@ -494,6 +503,7 @@ func TestStdExcludeByString(t *testing.T) {
 }
 
 func TestStdExcludeByFuncName(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	for _, test := range excludeByFuncNameTests {
@@ -516,6 +526,7 @@ func TestStdExcludeByFuncName(t *testing.T) {
 }
 
 func TestStdWithFlags(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	std = New(LEVEL_DEBUG, &buf)
 	SetFlags(Llabel | Lseperator)
@@ -533,6 +544,7 @@ func TestStdWithFlags(t *testing.T) {
 }
 
 func TestStdWithFlagsf(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	std = New(LEVEL_DEBUG, &buf)
 	SetFlags(Llabel | Lseperator)
@@ -13,12 +13,22 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aybabtme/rgbterm"
 )
 
+// skipNoDebug skips a test of debug output in logs_nodebug builds, where the
+// debug functions do nothing.
+func skipNoDebug(t *testing.T) {
+	if !DebugEnabled {
+		t.Skip("debug output is compiled out by logs_nodebug")
+	}
+}
+
 func TestStream(t *testing.T) {
 	var buf bytes.Buffer
 	logr := New(LEVEL_CRITICAL, os.Stdout, &buf)
@@ -28,6 +38,7 @@ func TestStream(t *testing.T) {
 }
 
 func TestMultiStreams(t *testing.T) {
+	skipNoDebug(t)
 	rand.Seed(time.Now().UnixNano())
 	fPath := filepath.Join(os.TempDir(), fmt.Sprint("go_test_",
 		rand.Int()))
@@ -53,6 +64,7 @@ func TestMultiStreams(t *testing.T) {
 }
 
 func TestLongFileFlag(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_DEBUG, &buf)
 	logr.SetFlags(LlongFileName | Llabel)
@@ -65,6 +77,7 @@ func TestLongFileFlag(t *testing.T) {
 }
 
 func TestShortFileFlag(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_DEBUG, &buf)
 	logr.SetFlags(LshortFileName | Llabel)
@@ -213,6 +226,7 @@ func TestFprintOutput(t *testing.T) {
 }
 
 func TestLevel(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_CRITICAL, &buf)
 	logr.Debug("This level should produce no output")
@@ -275,6 +289,196 @@ func TestFlagsLdate(t *testing.T) {
 	}
 }
 
+func TestFlagsLmoduleFileName(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LmoduleFileName)
+	_, file, _, _ := runtime.Caller(0)
+	logr.SetFilePrefixes("/does/not/match", filepath.Dir(file))
+	logr.Print("Test module file flag")
+	expect := "logger_test.go: Test module file flag"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestTrimFilePath(t *testing.T) {
+	var tests = []struct {
+		file, prefix, expect string
+	}{
+		{"/src/app/main.go", "/src/app", "main.go"},
+		{"/src/app/main.go", "/src/app/", "main.go"},
+		{"/src/application/main.go", "/src/app", "/src/application/main.go"},
+		{"/src/app", "/src/app", "/src/app"},
+	}
+	for _, test := range tests {
+		if got := trimFilePath(test.file, []string{test.prefix}); got != test.expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, test.expect)
+		}
+	}
+}
+
+func TestTrimFilePathModuleRoot(t *testing.T) {
+	if modulePath == "" {
+		t.Skip("built without module support")
+	}
+	_, file, _, _ := runtime.Caller(0)
+	if got := trimFilePath(file, nil); got != "logger_test.go" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, "logger_test.go")
+	}
+}
+
+func TestFlagsLhyperlink(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LshortFileName | Lhyperlink | Lcolor)
+	logr.SetHyperlinkFormat("editor://%[1]s:%[2]d")
+	_, file, line, _ := runtime.Caller(0)
+	logr.Print("Test hyperlink flag")
+	expect := fmt.Sprintf("\x1b]8;;editor://%s:%d\x1b\\logger_test.go"+
+		"\x1b]8;;\x1b\\: Test hyperlink flag", file, line+1)
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	buf.Reset()
+	logr.SetFlags(LshortFileName | Lhyperlink)
+	logr.Print("Test hyperlink flag")
+	expect = "logger_test.go: Test hyperlink flag"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestFlagsLalign(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lalign | Llabel | Lcolor)
+	logr.Debugln("Line one\nLine two\nLine three")
+	expect := "\x1b[38;5;231m[DEBUG]   \x1b[0;00m Line one\n" +
+		"           Line two\n" +
+		"           Line three\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestFlagsLshortLabel(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LshortLabel | Llabel)
+	logr.Warning("Short label")
+	logr.SetLabelWidth(8)
+	logr.Critical("Padded label")
+	logr.Print("No label")
+	expect := "[WARN] Short label[CRIT]   Padded labelNo label"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestFlagsLicons(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Licons)
+	defer func(u bool) { utf8Locale = u }(utf8Locale)
+	utf8Locale = true
+	logr.Error("Icon")
+	utf8Locale = false
+	logr.Error("ASCII icon")
+	logr.SetFlags(Licons | LshortLabel | Llabel)
+	logr.Warning("Icon and label")
+	expect := "✗ Iconx ASCII icon! [WARN] Icon and label"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestFlagsLaudit(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(Laudit)
+	logr.Infoln("first")
+	logr.Debugln("filtered")
+	logr.Infoln("second")
+	if expect := "#1 first\n#2 second\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestTemplateLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LstdFlags)
+	if err := logr.SetTemplate("{{.Level}} {{.LevelNum}} {{.Text}}"); err != nil {
+		t.Fatal(err)
+	}
+	logr.Warningln("careful")
+	logr.Println("plain")
+	if expect := "WARNING 2 careful\nPRINT 5 plain\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestSetFormatCheck(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	logr.SetFormatCheck(true)
+	format := "%d items %s\n"
+	logr.Infof(format, "three")
+	logr.Infof("100%% fine\n")
+	expect := "[INFO]     %!d(string=three) items %!s(MISSING)\n" +
+		"[WARNING]  bad format in previous entry: %!d(string=three) %!s(MISSING)\n" +
+		"[INFO]     100% fine\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+
+	// Dropped entries and the text of Print are not checked.
+	buf.Reset()
+	logr.SetLevel(LEVEL_WARNING)
+	logr.Infof(format, "three")
+	logr.Println("%!d(string=three)")
+	if expect := "%!d(string=three)\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestFlagsLquiet(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	logr.SetQuiet(true)
+	logr.Debugln("debug")
+	logr.Warningln("warning")
+	logr.Errorln("error")
+	logr.Println("print")
+	logr.SetQuiet(false)
+	logr.Debugln("debug")
+	expect := "[ERROR]    error\nprint\n[DEBUG]    debug\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestSetMaxEntrySize(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	logr.SetMaxEntrySize(10)
+	logr.Infoln("short")
+	logr.Infoln(strings.Repeat("x", 25))
+	logr.Infoln("ééééééé") // 14 bytes, the cut falls inside a rune
+	expect := "[INFO]     short\n" +
+		"[INFO]     xxxxxxxxxx...[truncated 15 bytes]\n" +
+		"[INFO]     ééééé...[truncated 4 bytes]\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
 func TestFlagsLfunctionName(t *testing.T) {
 	var buf bytes.Buffer
 	logr := New(LEVEL_DEBUG, &buf)
@@ -299,6 +503,7 @@ func TestFlagsLfunctionNameWithFileName(t *testing.T) {
 }
 
 func TestFlagsNoLcolorWithNewlinePadding(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_PRINT, &buf)
 	logr.SetFlags(Llabel)
@@ -310,6 +515,7 @@ func TestFlagsNoLcolorWithNewlinePadding(t *testing.T) {
 }
 
 func TestFlagsLcolorWithNewlinePaddingDebug(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	SetStreams(&buf)
 	logr := New(LEVEL_PRINT, &buf)
@@ -323,6 +529,7 @@ func TestFlagsLcolorWithNewlinePaddingDebug(t *testing.T) {
 }
 
 func TestFlagsLcolorWithNewlinePaddingDebugf(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_PRINT, &buf)
 	logr.SetFlags(Lcolor | Llabel)
@@ -342,6 +549,7 @@ func TestFlagsLcolorWithNewlinePaddingDebugf(t *testing.T) {
 }
 
 func TestFlagsLcolorWithNewlinePaddingDebugln(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_PRINT, &buf)
 	logr.SetFlags(Lcolor | Llabel)
@@ -366,6 +574,7 @@ func TestFlagsLcolorWithNewlinePaddingDebugln(t *testing.T) {
 }
 
 func TestSetIndentDebugln(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -388,6 +597,7 @@ func TestSetIndentDebugln(t *testing.T) {
 }
 
 func TestLindentWithLshowIndent(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -436,6 +646,7 @@ func TestTemplate(t *testing.T) {
 }
 
 func TestSetTemplate(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -472,6 +683,7 @@ func TestSetTemplateBad(t *testing.T) {
 }
 
 func TestSetTemplateBadDataObjectPanic(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -512,6 +724,7 @@ func TestDateFormat(t *testing.T) {
 }
 
 func TestSetDateFormat(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_PRINT, &buf)
@@ -534,6 +747,22 @@ func TestSetDateFormat(t *testing.T) {
 	SetTemplate(logFmt)
 }
 
+func TestSetDateFormatUnixMillis(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_PRINT, &buf)
+	logr.SetFlags(Ldate)
+	logr.SetDateFormat(DateUnixMillis)
+	logr.SetTemplate("{{.Date}}")
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	logr.Print("Hello")
+	after := time.Now().UnixNano() / int64(time.Millisecond)
+	got, err := strconv.ParseInt(buf.String(), 10, 64)
+	if err != nil || got < before || got > after {
+		t.Errorf("\nGot:\t%q\nExpect:\tbetween %d and %d\n", buf.String(),
+			before, after)
+	}
+}
+
 func TestFlags(t *testing.T) {
 	logr := New(LEVEL_INFO)
 
@@ -577,6 +806,7 @@ func TestStreams(t *testing.T) {
 }
 
 func TestIndent(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG)
@@ -604,6 +834,7 @@ func TestIndent(t *testing.T) {
 }
 
 func TestTabStop(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -637,6 +868,7 @@ func TestTabStop(t *testing.T) {
 // TestLnoFileAnsi verifies output sent to os.Stdout contains color codes
 // and output sent to a file does not.
 func TestLnoFileAnsi(t *testing.T) {
+	skipNoDebug(t)
 	logr := New(LEVEL_DEBUG)
 
 	logr.SetFlags(Lseperator | Llabel | Lcolor | LnoFileAnsi)
@@ -680,6 +912,28 @@ func TestLnoFileAnsi(t *testing.T) {
 	}
 }
 
+// TestLnoFileAnsiPerStream verifies that stripping the output for a file does
+// not change the output of the streams after it.
+func TestLnoFileAnsiPerStream(t *testing.T) {
+	var file1, term, file2 bytes.Buffer
+	logr := New(LEVEL_DEBUG, &file1, &term, &file2)
+	logr.SetFlags(Llabel | Lcolor | LnoFileAnsi)
+	logr.SetColorMode(&term, Color256)
+
+	logr.Warningln("Test 1")
+	logr.Write([]byte(rgbterm.FgString("Test 2", 255, 0, 0) + "\n"))
+
+	expe := "[WARNING]  Test 1\nTest 2\n"
+	expeTerm := rgbterm.FgString("[WARNING] ", 255, 255, 135) + " Test 1\n" +
+		rgbterm.FgString("Test 2", 255, 0, 0) + "\n"
+	if file1.String() != expe || file2.String() != expe {
+		t.Errorf("\nGot:\t%q\n\t%q\nExpect:\t%q\n", file1.String(), file2.String(), expe)
+	}
+	if term.String() != expeTerm {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", term.String(), expeTerm)
+	}
+}
+
 var printFunctionTests = []struct {
 	name   string
 	format string
@@ -690,6 +944,7 @@ var printFunctionTests = []struct {
 }
 
 func TestPrintFunctions(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	logr := New(LEVEL_DEBUG, &buf)
@@ -881,6 +1136,7 @@ var excludeByStringTests = []struct {
 }
 
 func TestExcludeByString(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	for _, test := range excludeByStringTests {
@@ -965,6 +1221,7 @@ var excludeByFuncNameTests = []struct {
 }
 
 func TestExcludeByFuncName(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 
 	for _, test := range excludeByFuncNameTests {
@@ -987,6 +1244,7 @@ func TestExcludeByFuncName(t *testing.T) {
 }
 
 func TestWithFlags(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_DEBUG, &buf)
 	logr.SetFlags(Llabel | Lseperator)
@@ -1004,6 +1262,7 @@ func TestWithFlags(t *testing.T) {
 }
 
 func TestWithFlagsf(t *testing.T) {
+	skipNoDebug(t)
 	var buf bytes.Buffer
 	logr := New(LEVEL_DEBUG, &buf)
 	logr.SetFlags(Llabel | Lseperator)
@@ -0,0 +1,43 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build go1.18
+// +build go1.18
+
+package logparse
+
+import (
+	"bytes"
+	"testing"
+
+	"logs"
+)
+
+func FuzzParseLine(f *testing.F) {
+	f.Add("2009-11-10T23:00:00Z [WARNING]  :: main.go:12: disk almost full free=3")
+	f.Add(`{"level":"error","msg":"failed","time":"2009-11-10T23:00:00Z","seq":1}`)
+	f.Add("#12 [CRIT] \x1b[38;5;196m:: x=\"unterminated")
+	f.Add("[\xff] a= =b \"")
+	f.Fuzz(func(t *testing.T, line string) {
+		new(Parser).ParseLine(line)
+	})
+}
+
+func FuzzParseRoundTrip(f *testing.F) {
+	f.Add("disk almost full")
+	f.Add("[ERROR] looks like a label")
+	f.Add("a=b \x1b[1 \xff\n")
+	f.Fuzz(func(t *testing.T, msg string) {
+		var buf bytes.Buffer
+		logr := logs.New(logs.LEVEL_DEBUG, &buf)
+		logr.SetFlags(logs.LstdFlags | logs.LshortFileName | logs.Laudit)
+		logr.Warningln(msg)
+		entries, err := Parse(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 || entries[0].Level != logs.LEVEL_WARNING || entries[0].Seq != 1 {
+			t.Errorf("Unexpected entries for %q: %+v", buf.String(), entries)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+// Package logparse reads the output of go-logs back into entries. Lines
+// written with the default template and lines written by the JSON encoder are
+// supported, so captured logs can be checked in tests, replayed, or shipped
+// elsewhere.
+package logparse
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"logs"
+)
+
+// Entry is a parsed line.
+type Entry struct {
+	logs.Entry
+	Caller string // The file name and line number, if present
+}
+
+var (
+	ansiRegexp   = regexp.MustCompile("\x1b\\[[\\d;]+m|\x1b\\]8;[^\x1b\a]*(\x1b\\\\|\a)")
+	seqRegexp    = regexp.MustCompile(`^#(\d+) `)
+	procRegexp   = regexp.MustCompile(`^(\S*)\[(\d+):(\d+)\] `)
+	ulidRegexp   = regexp.MustCompile(`^([0-9A-HJKMNP-TV-Z]{26}) `)
+	fieldRegexp  = regexp.MustCompile(`\s([\w.-]+)=("(?:[^"\\]|\\.)*"|[^\s"]*)$`)
+	callerRegexp = regexp.MustCompile(`^(\S+\.go)(?::(\d+))?: (?:\S+: )?(?:Line (\d+): )?`)
+)
+
+// Parser parses lines written by a logging object using the default template.
+type Parser struct {
+	DateFormat string // The date format of the logger, logs.DateRFC3339 if empty
+	Seperator  string // The seperator of the logger, "::" if empty
+}
+
+// Parse reads all lines from r with the default settings.
+func Parse(r io.Reader) ([]*Entry, error) { return new(Parser).Parse(r) }
+
+// Parse reads all lines from r. Empty lines are skipped.
+func (p *Parser) Parse(r io.Reader) ([]*Entry, error) {
+	var entries []*Entry
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for s.Scan() {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		e, err := p.ParseLine(s.Text())
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, s.Err()
+}
+
+// ParseLine parses a single line. Lines starting with "{" are parsed as JSON.
+// Parts of the default template that are missing are left empty, so any line
+// can be parsed; the whole line becomes the message in the worst case.
+func (p *Parser) ParseLine(line string) (*Entry, error) {
+	// Leading whitespace of the text is written in front of the entry.
+	line = strings.TrimLeft(strings.TrimRight(line, "\r\n"), "\t\v\r")
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return parseJSON(line)
+	}
+	e := &Entry{}
+	e.Level = logs.LEVEL_PRINT
+	rest := ansiRegexp.ReplaceAllString(line, "")
+
+	if m := seqRegexp.FindStringSubmatch(rest); m != nil {
+		e.Seq, _ = strconv.ParseUint(m[1], 10, 64)
+		rest = rest[len(m[0]):]
+	}
+	rest = p.parseDate(e, rest)
+	if m := procRegexp.FindStringSubmatch(rest); m != nil {
+		e.User = m[1]
+		e.Pid, _ = strconv.Atoi(m[2])
+		e.PPid, _ = strconv.Atoi(m[3])
+		rest = rest[len(m[0]):]
+	}
+	if m := ulidRegexp.FindStringSubmatch(rest); m != nil {
+		e.ID = m[1]
+		rest = rest[len(m[0]):]
+	}
+	rest = parseLabel(e, rest)
+	sep := p.Seperator
+	if sep == "" {
+		sep = "::"
+	}
+	if strings.HasPrefix(rest, sep+" ") {
+		rest = rest[len(sep)+1:]
+	}
+	if m := callerRegexp.FindStringSubmatch(rest); m != nil {
+		e.Caller = m[1]
+		if n := m[2] + m[3]; n != "" {
+			e.Caller += ":" + n
+		}
+		rest = rest[len(m[0]):]
+	}
+	// Fields are appended at the end in key=value form.
+	for {
+		m := fieldRegexp.FindStringSubmatchIndex(rest)
+		if m == nil {
+			break
+		}
+		value := rest[m[4]:m[5]]
+		if strings.HasPrefix(value, `"`) {
+			if v, err := strconv.Unquote(value); err == nil {
+				value = v
+			}
+		}
+		e.Fields = append(e.Fields, logs.Field{Key: rest[m[2]:m[3]], Value: value})
+		rest = rest[:m[0]]
+	}
+	sortFields(e.Fields)
+	e.Message = strings.TrimSpace(rest)
+	return e, nil
+}
+
+// parseDate parses the date at the start of s and returns the rest of s.
+func (p *Parser) parseDate(e *Entry, s string) string {
+	layout := p.DateFormat
+	if layout == "" {
+		layout = logs.DateRFC3339
+	}
+	n := strings.Count(layout, " ") + 1
+	parts := strings.SplitN(s, " ", n+1)
+	if len(parts) < n+1 {
+		return s
+	}
+	var t time.Time
+	var err error
+	date := strings.Join(parts[:n], " ")
+	if layout == logs.DateUnixMillis {
+		var ms int64
+		if ms, err = strconv.ParseInt(date, 10, 64); err == nil {
+			t = time.UnixMilli(ms)
+		}
+	} else {
+		t, err = time.Parse(layout, date)
+	}
+	if err != nil {
+		return s
+	}
+	e.Time = t
+	return parts[n]
+}
+
+// parseLabel parses a level label at the start of s and returns the rest of s.
+func parseLabel(e *Entry, s string) string {
+	if !strings.HasPrefix(s, "[") {
+		return s
+	}
+	i := strings.IndexByte(s, ']')
+	if i < 0 {
+		return s
+	}
+	name := s[:i+1]
+	for _, lbl := range logs.Labels {
+		full := strings.TrimSpace(lbl.String())
+		if full != "" && (name == full || name == lbl.Short()) {
+			e.Level = logs.LevelFromString(strings.Trim(full, "[]"))
+			return strings.TrimLeft(s[i+1:], " ")
+		}
+	}
+	return s
+}
+
+// parseJSON parses a line written by logs.JSONEncoder.
+func parseJSON(line string) (*Entry, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		return nil, err
+	}
+	e := &Entry{}
+	e.Level = logs.LEVEL_PRINT
+	for k, v := range m {
+		switch k {
+		case "time":
+			s, _ := v.(string)
+			e.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case "level":
+			s, _ := v.(string)
+			e.Level = logs.LevelFromString(s)
+		case "msg":
+			e.Message, _ = v.(string)
+		case "seq":
+			n, _ := v.(float64)
+			e.Seq = uint64(n)
+		case "pid":
+			n, _ := v.(float64)
+			e.Pid = int(n)
+		case "ppid":
+			n, _ := v.(float64)
+			e.PPid = int(n)
+		case "username":
+			e.User, _ = v.(string)
+		case "entry_id":
+			e.ID, _ = v.(string)
+		case "caller":
+			e.Caller, _ = v.(string)
+		default:
+			e.Fields = append(e.Fields, logs.Field{Key: k, Value: v})
+		}
+	}
+	sortFields(e.Fields)
+	return e, nil
+}
+
+func sortFields(fields []logs.Field) {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+}
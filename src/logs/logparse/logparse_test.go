@@ -0,0 +1,113 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logparse
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"logs"
+)
+
+func TestParseText(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(logs.LstdFlags | logs.LshortFileName | logs.LlineNumber | logs.Laudit)
+	logr.Warningln("disk almost full")
+	logr.ErrorE().Str("path", "/var/lib").Int("free", 3).Msg("write failed: no space")
+	logr.Println("plain")
+
+	entries, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		level   string
+		message string
+		fields  []logs.Field
+	}{
+		{"warning", "disk almost full", nil},
+		{"error", "write failed: no space",
+			[]logs.Field{{Key: "free", Value: "3"}, {Key: "path", Value: "/var/lib"}}},
+		{"print", "plain", nil},
+	}
+	if len(entries) != len(tests) {
+		t.Fatalf("Got %d entries; want: %d", len(entries), len(tests))
+	}
+	for i, test := range tests {
+		e := entries[i]
+		if e.Level != logs.LevelFromString(test.level) || e.Message != test.message ||
+			!reflect.DeepEqual(e.Fields, test.fields) || e.Seq != uint64(i+1) {
+			t.Errorf("\nGot:\t%s %q %v #%d\nExpect:\t%s %q %v #%d\n", e.Level, e.Message,
+				e.Fields, e.Seq, test.level, test.message, test.fields, i+1)
+		}
+		if e.Time.IsZero() {
+			t.Errorf("Entry %d has no time", i)
+		}
+		if len(e.Caller) < len("logparse_test.go:1") {
+			t.Errorf("Entry %d caller: %q", i, e.Caller)
+		}
+	}
+}
+
+func TestParseProcess(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(logs.LstdFlags | logs.Lprocess)
+	logr.Infoln("started")
+
+	entries, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Got %d entries; want: 1", len(entries))
+	}
+	e := entries[0]
+	if e.Pid != os.Getpid() || e.PPid != os.Getppid() || e.Message != "started" ||
+		e.Level != logs.LEVEL_INFO {
+		t.Errorf("\nGot:\t%d %d %s %q\nExpect:\t%d %d %s %q\n", e.Pid, e.PPid, e.Level,
+			e.Message, os.Getpid(), os.Getppid(), logs.LEVEL_INFO, "started")
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetFlags(logs.LstdFlags | logs.Lulid)
+	id := logr.InfoE().Msg("started")
+
+	entries, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Got %d entries; want: 1", len(entries))
+	}
+	e := entries[0]
+	if e.ID != id || e.Message != "started" || e.Level != logs.LEVEL_INFO {
+		t.Errorf("\nGot:\t%s %s %q\nExpect:\t%s %s %q\n", e.ID, e.Level, e.Message,
+			id, logs.LEVEL_INFO, "started")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	logr.SetStreamEncoder(&buf, logs.JSONEncoder{})
+	logr.InfoE().Str("user", "ann").Msg("login")
+
+	entries, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := entries[0]
+	expect := []logs.Field{{Key: "user", Value: "ann"}}
+	if e.Level != logs.LEVEL_INFO || e.Message != "login" || !reflect.DeepEqual(e.Fields, expect) {
+		t.Errorf("\nGot:\t%s %q %v\nExpect:\t%s %q %v\n", e.Level, e.Message, e.Fields,
+			logs.LEVEL_INFO, "login", expect)
+	}
+}
@@ -0,0 +1,140 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: logstream.proto
+
+//go:build logs_grpc
+// +build logs_grpc
+
+package logstream
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	LogStream_Subscribe_FullMethodName = "/logstream.LogStream/Subscribe"
+)
+
+// LogStreamClient is the client API for LogStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LogStreamClient interface {
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (LogStream_SubscribeClient, error)
+}
+
+type logStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLogStreamClient(cc grpc.ClientConnInterface) LogStreamClient {
+	return &logStreamClient{cc}
+}
+
+func (c *logStreamClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (LogStream_SubscribeClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogStream_ServiceDesc.Streams[0], LogStream_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logStreamSubscribeClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogStream_SubscribeClient interface {
+	Recv() (*Entry, error)
+	grpc.ClientStream
+}
+
+type logStreamSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *logStreamSubscribeClient) Recv() (*Entry, error) {
+	m := new(Entry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogStreamServer is the server API for LogStream service.
+// All implementations must embed UnimplementedLogStreamServer
+// for forward compatibility
+type LogStreamServer interface {
+	Subscribe(*SubscribeRequest, LogStream_SubscribeServer) error
+	mustEmbedUnimplementedLogStreamServer()
+}
+
+// UnimplementedLogStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedLogStreamServer struct {
+}
+
+func (UnimplementedLogStreamServer) Subscribe(*SubscribeRequest, LogStream_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedLogStreamServer) mustEmbedUnimplementedLogStreamServer() {}
+
+// UnsafeLogStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LogStreamServer will
+// result in compilation errors.
+type UnsafeLogStreamServer interface {
+	mustEmbedUnimplementedLogStreamServer()
+}
+
+func RegisterLogStreamServer(s grpc.ServiceRegistrar, srv LogStreamServer) {
+	s.RegisterService(&LogStream_ServiceDesc, srv)
+}
+
+func _LogStream_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogStreamServer).Subscribe(m, &logStreamSubscribeServer{ServerStream: stream})
+}
+
+type LogStream_SubscribeServer interface {
+	Send(*Entry) error
+	grpc.ServerStream
+}
+
+type logStreamSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *logStreamSubscribeServer) Send(m *Entry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// LogStream_ServiceDesc is the grpc.ServiceDesc for LogStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LogStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "logstream.LogStream",
+	HandlerType: (*LogStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _LogStream_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "logstream.proto",
+}
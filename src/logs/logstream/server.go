@@ -0,0 +1,69 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_grpc
+// +build logs_grpc
+
+// Package logstream implements the gRPC service in logstream.proto. It is
+// only built with the logs_grpc build tag because it needs the gRPC and
+// protobuf modules. The generated code is part of the package; after changing
+// logstream.proto, regenerate it with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//		--go-grpc_out=. --go-grpc_opt=paths=source_relative logstream.proto
+//
+// Register the server with a gRPC server:
+//
+//	s := grpc.NewServer()
+//	logstream.RegisterLogStreamServer(s, logstream.NewServer(logr))
+package logstream
+
+import (
+	"fmt"
+	"strings"
+
+	"logs"
+)
+
+// buffer is the number of entries queued per subscriber.
+const buffer = 1024
+
+// Server streams the entries of a logging object to subscribers.
+type Server struct {
+	UnimplementedLogStreamServer
+	logr *logs.Logger
+}
+
+// NewServer returns a server for the entries of logr.
+func NewServer(logr *logs.Logger) *Server { return &Server{logr: logr} }
+
+// Subscribe sends entries at or above the requested level until the client
+// goes away.
+func (s *Server) Subscribe(req *SubscribeRequest, stream LogStream_SubscribeServer) error {
+	lvl := logs.LEVEL_DEBUG
+	if req.GetLevel() != "" {
+		lvl = logs.LevelFromString(req.GetLevel())
+	}
+	ch, cancel := s.logr.Subscribe(lvl, buffer)
+	defer cancel()
+	for {
+		select {
+		case e := <-ch:
+			msg := &Entry{
+				TimeUnixNano: e.Time.UnixNano(),
+				Level:        strings.ToLower(strings.TrimPrefix(e.Level.String(), "LEVEL_")),
+				Message:      e.Message,
+				Seq:          e.Seq,
+				Fields:       make(map[string]string, len(e.Fields)),
+			}
+			for _, f := range e.Fields {
+				msg.Fields[f.Key] = fmt.Sprint(f.Value)
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
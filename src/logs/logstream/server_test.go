@@ -0,0 +1,64 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+//go:build logs_grpc
+// +build logs_grpc
+
+package logstream
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"logs"
+)
+
+func TestSubscribe(t *testing.T) {
+	var buf bytes.Buffer
+	logr := logs.New(logs.LEVEL_DEBUG, &buf)
+	lis := bufconn.Listen(1 << 16)
+	s := grpc.NewServer()
+	RegisterLogStreamServer(s, NewServer(logr))
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := NewLogStreamClient(conn).Subscribe(ctx, &SubscribeRequest{Level: "warning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Log until the subscription is registered by the server.
+	go func() {
+		for ctx.Err() == nil {
+			logr.Infoln("skipped")
+			logr.WarningE().Str("disk", "sda").Msg("almost full")
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.GetLevel() != "warning" || e.GetMessage() != "almost full" ||
+		e.GetFields()["disk"] != "sda" || e.GetTimeUnixNano() == 0 {
+		t.Errorf("\nGot:\t%s %q %v\nExpect:\t%s %q %v\n", e.GetLevel(), e.GetMessage(),
+			e.GetFields(), "warning", "almost full", map[string]string{"disk": "sda"})
+	}
+}
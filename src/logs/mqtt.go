@@ -0,0 +1,94 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "text/template"
+
+// MQTTPublisher publishes an MQTT message. MQTT clients return tokens or
+// take extra options, so it is usually a small adapter, for example for
+// github.com/eclipse/paho.mqtt.golang:
+//
+//	pub := logs.MQTTPublisherFunc(func(topic string, qos byte, retained bool, payload []byte) error {
+//		t := client.Publish(topic, qos, retained, payload)
+//		t.Wait()
+//		return t.Error()
+//	})
+type MQTTPublisher interface {
+	Publish(topic string, qos byte, retained bool, payload []byte) error
+}
+
+// MQTTPublisherFunc adapts a function to the MQTTPublisher interface.
+type MQTTPublisherFunc func(topic string, qos byte, retained bool, payload []byte) error
+
+// Publish calls f.
+func (f MQTTPublisherFunc) Publish(topic string, qos byte, retained bool, payload []byte) error {
+	return f(topic, qos, retained, payload)
+}
+
+// Payloads published to the status topic of an MQTTSink.
+const (
+	MQTTOnline  = "online"
+	MQTTOffline = "offline"
+)
+
+// MQTTSink is a stream publishing entries as JSON messages over MQTT, for
+// small gateways that report over MQTT anyway. Like OTLPExporter it is both
+// the stream and its encoder, so it is added with AddSink:
+//
+//	sink, err := logs.NewMQTTSink(pub, "gw1/logs/{{.Level}}")
+//	logr.AddSink(sink)
+//
+// The topic is a template with the fields Level and Logger, see NATSSink.
+// If StatusTopic is set, Online publishes a retained "online" message to it.
+// Configure the matching last will and testament on the client with Will, so
+// the broker publishes "offline" when the gateway disappears.
+type MQTTSink struct {
+	JSONEncoder
+	QoS         byte   // Quality of service of the entries, 0 by default
+	Retained    bool   // Publish entries as retained messages
+	StatusTopic string // Topic for the online and offline status
+
+	pub   MQTTPublisher
+	topic *template.Template
+}
+
+// NewMQTTSink returns a sink publishing with pub to the topics created by the
+// topic template.
+func NewMQTTSink(pub MQTTPublisher, topic string) (*MQTTSink, error) {
+	t, err := topicTemplate(topic)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTSink{pub: pub, topic: t}, nil
+}
+
+// Write publishes an entry encoded by Encode.
+func (s *MQTTSink) Write(p []byte) (int, error) {
+	topic, err := renderTopic(s.topic, p)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.pub.Publish(topic, s.QoS, s.Retained, append([]byte(nil), p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Will returns the last will and testament to configure on the MQTT client:
+// the status topic, a retained "offline" payload, and QoS 1.
+//
+//	topic, payload, qos, retained := sink.Will()
+//	opts.SetBinaryWill(topic, payload, qos, retained)
+func (s *MQTTSink) Will() (topic string, payload []byte, qos byte, retained bool) {
+	return s.StatusTopic, []byte(MQTTOffline), 1, true
+}
+
+// Online publishes a retained "online" message to the status topic. Call it
+// after connecting. Nothing is published if StatusTopic is empty.
+func (s *MQTTSink) Online() error {
+	if s.StatusTopic == "" {
+		return nil
+	}
+	return s.pub.Publish(s.StatusTopic, 1, true, []byte(MQTTOnline))
+}
@@ -0,0 +1,43 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMQTTSink(t *testing.T) {
+	var published []string
+	pub := MQTTPublisherFunc(func(topic string, qos byte, retained bool, payload []byte) error {
+		msg := string(payload)
+		if strings.HasPrefix(msg, "{") {
+			msg = "entry"
+		}
+		published = append(published, fmt.Sprintf("%s %d %t %s", topic, qos, retained, msg))
+		return nil
+	})
+	sink, err := NewMQTTSink(pub, "gw1/logs/{{.Level}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.QoS = 1
+	sink.StatusTopic = "gw1/status"
+	if err := sink.Online(); err != nil {
+		t.Fatal(err)
+	}
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+	logr.Warningln("battery low")
+
+	expect := []string{"gw1/status 1 true online", "gw1/logs/warning 1 false entry"}
+	if strings.Join(published, "|") != strings.Join(expect, "|") {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", published, expect)
+	}
+	if topic, payload, _, retained := sink.Will(); topic != "gw1/status" ||
+		string(payload) != MQTTOffline || !retained {
+		t.Errorf("Will() = %q %q %t", topic, payload, retained)
+	}
+}
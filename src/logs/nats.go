@@ -0,0 +1,100 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// Publisher publishes a message on a subject. *nats.Conn from
+// github.com/nats-io/nats.go satisfies it.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface, for example to
+// publish with JetStream for persistence:
+//
+//	js, _ := nc.JetStream()
+//	pub := logs.PublisherFunc(func(subj string, data []byte) error {
+//		_, err := js.Publish(subj, data)
+//		return err
+//	})
+type PublisherFunc func(subject string, data []byte) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(subject string, data []byte) error { return f(subject, data) }
+
+// topicData holds the values available to subject and topic templates.
+type topicData struct {
+	Level  string // Lower case level name, for example "error"
+	Logger string // Value of the FieldLogger field, if any
+}
+
+// topicTemplate parses a subject or topic template.
+func topicTemplate(text string) (*template.Template, error) {
+	return template.New("topic").Parse(text)
+}
+
+// renderTopic executes t with the level and logger of p, an entry encoded by
+// JSONEncoder.
+func renderTopic(t *template.Template, p []byte) (string, error) {
+	var m struct {
+		Level  string `json:"level"`
+		Logger string `json:"logger"`
+	}
+	if err := json.Unmarshal(p, &m); err != nil {
+		return "", errNotEncoded
+	}
+	if m.Logger == "" {
+		m.Logger = "default"
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, topicData{m.Level, m.Logger}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// NATSSink is a stream publishing entries as JSON messages to NATS. Like
+// OTLPExporter it is both the stream and its encoder, so it is added with
+// AddSink:
+//
+//	nc, _ := nats.Connect(nats.DefaultURL)
+//	sink, err := logs.NewNATSSink(nc, "logs.{{.Logger}}.{{.Level}}")
+//	logr.SetStreams(os.Stderr)
+//	logr.AddSink(sink)
+//
+// The subject is a template with the fields Level, the lower case level name,
+// and Logger, the value of the FieldLogger field or "default".
+type NATSSink struct {
+	JSONEncoder
+	pub     Publisher
+	subject *template.Template
+}
+
+// NewNATSSink returns a sink publishing with pub to the subjects created by
+// the subject template.
+func NewNATSSink(pub Publisher, subject string) (*NATSSink, error) {
+	t, err := topicTemplate(subject)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSSink{pub: pub, subject: t}, nil
+}
+
+// Write publishes an entry encoded by Encode.
+func (s *NATSSink) Write(p []byte) (int, error) {
+	subject, err := renderTopic(s.subject, p)
+	if err != nil {
+		return 0, err
+	}
+	data := append([]byte(nil), p...)
+	if err := s.pub.Publish(subject, data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
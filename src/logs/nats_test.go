@@ -0,0 +1,38 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNATSSink(t *testing.T) {
+	var subjects []string
+	var messages []string
+	pub := PublisherFunc(func(subject string, data []byte) error {
+		subjects = append(subjects, subject)
+		messages = append(messages, string(data))
+		return nil
+	})
+	sink, err := NewNATSSink(pub, "logs.{{.Logger}}.{{.Level}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+	logr.ErrorE().Str(FieldLogger, "api").Msg("failed")
+	logr.Infoln("started")
+
+	expect := "logs.api.error,logs.default.info"
+	if got := strings.Join(subjects, ","); got != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+	if !strings.Contains(messages[0], `"msg":"failed"`) {
+		t.Errorf("Got message %q", messages[0])
+	}
+	if _, err := NewNATSSink(pub, "{{.Nope"); err == nil {
+		t.Errorf("NewNATSSink() with a bad template = nil; want: error")
+	}
+}
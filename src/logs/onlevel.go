@@ -0,0 +1,47 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+// levelHandler is a callback registered with OnLevel.
+type levelHandler struct {
+	level level
+	f     func(Entry)
+}
+
+// OnLevel calls f for every entry at or above lvl written by the standard
+// logging object. See (*Logger).OnLevel.
+func OnLevel(lvl level, f func(Entry)) (remove func()) {
+	return std.OnLevel(lvl, f)
+}
+
+// OnLevel calls f for every entry at or above lvl written by l, so that
+// applications can react to them, for example by tripping a circuit breaker
+// on LEVEL_CRITICAL. f is called by the logging goroutine after the entry is
+// written. Entries f writes to l at or above lvl call f again, so it should
+// not log at that level. The returned function removes the callback.
+func (l *Logger) OnLevel(lvl level, f func(Entry)) (remove func()) {
+	h := &levelHandler{level: lvl, f: f}
+	l.mu.Lock()
+	l.levelHandlers = append(l.levelHandlers, h)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, x := range l.levelHandlers {
+			if x == h {
+				l.levelHandlers = append(l.levelHandlers[:i:i], l.levelHandlers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// notify calls the handlers for e at or above their level.
+func notify(handlers []*levelHandler, e *Entry) {
+	for _, h := range handlers {
+		if e.Level >= h.level && e.Level != LEVEL_PRINT {
+			h.f(*e)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestOnLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	var got []string
+	remove := logr.OnLevel(LEVEL_ERROR, func(e Entry) {
+		got = append(got, levelName(e.Level)+": "+e.Message)
+	})
+	logr.Warningln("disk almost full")
+	logr.Errorln("disk full")
+	logr.Criticalln("out of space")
+	logr.Println("printed")
+	remove()
+	logr.Criticalln("not seen")
+	expect := []string{"error: disk full", "critical: out of space"}
+	if len(got) != len(expect) || got[0] != expect[0] || got[1] != expect[1] {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Field keys mapped to the trace and span ids of OTLP log records.
+const (
+	FieldTraceID = "trace_id"
+	FieldSpanID  = "span_id"
+)
+
+// otlpSeverity maps levels to OpenTelemetry severity numbers.
+var otlpSeverity = [...]int{
+	LEVEL_DEBUG:    5,
+	LEVEL_INFO:     9,
+	LEVEL_WARNING:  13,
+	LEVEL_ERROR:    17,
+	LEVEL_CRITICAL: 21,
+	LEVEL_PRINT:    9,
+}
+
+// OTLPExporter is a stream that ships entries to an OpenTelemetry collector
+// using OTLP over HTTP with JSON encoding. It is both the stream and its
+// encoder, so it is added with AddSink:
+//
+//	exp := logs.NewOTLPExporter("http://localhost:4318/v1/logs", "api")
+//	defer exp.Close()
+//	logr.SetStreams(os.Stderr)
+//	logr.AddSink(exp)
+//
+// Records are sent in batches of BatchSize by a background goroutine, when
+// BatchSize records are waiting and when the flush interval expires, and on
+// Flush or Close. If sending fails, the records are kept for the next
+// attempt. At most MaxQueue records are kept; the oldest are dropped when
+// the queue is full. The trace_id and span_id
+// fields of an entry become the trace and span ids of the log record, and all
+// other fields become attributes.
+type OTLPExporter struct {
+	URL         string            // Collector endpoint, usually ending in /v1/logs
+	ServiceName string            // Value of the service.name resource attribute
+	Headers     map[string]string // Extra request headers, for example for auth
+	BatchSize   int               // Records per request
+	MaxQueue    int               // Records kept while the collector is unavailable
+	Client      *http.Client
+
+	ctx     context.Context
+	flushMu sync.Mutex // Serializes sends so records stay in order
+	mu      sync.Mutex
+	records []json.RawMessage
+	dropped int
+	kick    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewOTLPExporter returns an exporter sending records to url and flushing
+// them at least every five seconds.
+func NewOTLPExporter(url, serviceName string) *OTLPExporter {
+	return NewOTLPExporterContext(context.Background(), url, serviceName)
+}
+
+// NewOTLPExporterContext is like NewOTLPExporter, but requests are made with
+// ctx. Canceling ctx aborts the request in flight and stops the flush timer,
+// so Close returns quickly during shutdown.
+func NewOTLPExporterContext(ctx context.Context, url, serviceName string) *OTLPExporter {
+	x := &OTLPExporter{
+		ctx:         ctx,
+		URL:         url,
+		ServiceName: serviceName,
+		BatchSize:   100,
+		MaxQueue:    10000,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		kick:        make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	go x.flushEvery(5 * time.Second)
+	return x
+}
+
+func (x *OTLPExporter) flushEvery(d time.Duration) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			x.Flush()
+		case <-x.kick:
+			x.Flush()
+		case <-x.done:
+			return
+		case <-x.ctx.Done():
+			return
+		}
+	}
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpValue      `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID        string         `json:"traceId,omitempty"`
+	SpanID         string         `json:"spanId,omitempty"`
+}
+
+func newOTLPValue(v interface{}) otlpValue {
+	switch x := v.(type) {
+	case bool:
+		return otlpValue{BoolValue: &x}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		s := fmt.Sprint(x)
+		return otlpValue{IntValue: &s}
+	case float32:
+		f := float64(x)
+		return otlpValue{DoubleValue: &f}
+	case float64:
+		return otlpValue{DoubleValue: &x}
+	}
+	s := fmt.Sprint(jsonValue(v))
+	return otlpValue{StringValue: &s}
+}
+
+// Encode converts e into an OTLP log record. It satisfies the Encoder
+// interface.
+func (x *OTLPExporter) Encode(e *Entry) ([]byte, error) {
+	r := otlpRecord{
+		TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+		SeverityNumber: otlpSeverity[e.Level],
+		SeverityText:   levelName(e.Level),
+		Body:           newOTLPValue(e.Message),
+	}
+	for _, f := range e.Fields {
+		switch f.Key {
+		case FieldTraceID:
+			r.TraceID = fmt.Sprint(f.Value)
+		case FieldSpanID:
+			r.SpanID = fmt.Sprint(f.Value)
+		default:
+			r.Attributes = append(r.Attributes, otlpKeyValue{f.Key, newOTLPValue(f.Value)})
+		}
+	}
+	return json.Marshal(r)
+}
+
+// Write queues a record created by Encode. A full batch is sent by the
+// background goroutine, so Write does not wait for the collector.
+func (x *OTLPExporter) Write(p []byte) (int, error) {
+	if !json.Valid(p) {
+		return 0, errNotEncoded
+	}
+	x.mu.Lock()
+	x.records = append(x.records, append(json.RawMessage(nil), p...))
+	x.trim()
+	full := len(x.records) >= x.BatchSize
+	x.mu.Unlock()
+	if full {
+		select {
+		case x.kick <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// trim drops the oldest records beyond MaxQueue. It must be called with x.mu
+// held.
+func (x *OTLPExporter) trim() {
+	if n := len(x.records) - x.MaxQueue; x.MaxQueue > 0 && n > 0 {
+		x.records = x.records[n:]
+		x.dropped += n
+	}
+}
+
+// Dropped returns the number of records dropped because the queue was full.
+func (x *OTLPExporter) Dropped() int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.dropped
+}
+
+// Flush sends the queued records to the collector, one request per batch.
+// Records that could not be sent are queued again.
+func (x *OTLPExporter) Flush() error {
+	x.flushMu.Lock()
+	defer x.flushMu.Unlock()
+	x.mu.Lock()
+	records := x.records
+	x.records = nil
+	x.mu.Unlock()
+	for len(records) > 0 {
+		n := len(records)
+		if x.BatchSize > 0 && n > x.BatchSize {
+			n = x.BatchSize
+		}
+		if err := x.send(records[:n]); err != nil {
+			x.mu.Lock()
+			x.records = append(records, x.records...)
+			x.trim()
+			x.mu.Unlock()
+			return err
+		}
+		records = records[n:]
+	}
+	return nil
+}
+
+// send posts records to the collector in one request.
+func (x *OTLPExporter) send(records []json.RawMessage) error {
+	body := map[string]interface{}{
+		"resourceLogs": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": []otlpKeyValue{{"service.name", newOTLPValue(x.ServiceName)}},
+			},
+			"scopeLogs": []interface{}{map[string]interface{}{
+				"scope":      map[string]string{"name": "go-logs"},
+				"logRecords": records,
+			}},
+		}},
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(x.ctx, "POST", x.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range x.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := x.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("logs: otlp collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close stops the flush timer and sends the queued records.
+func (x *OTLPExporter) Close() error {
+	x.once.Do(func() { close(x.done) })
+	return x.Flush()
+}
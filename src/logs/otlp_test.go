@@ -0,0 +1,107 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var got struct {
+		ResourceLogs []struct {
+			ScopeLogs []struct {
+				LogRecords []otlpRecord
+			}
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, "test")
+	logr := New(LEVEL_DEBUG, exp)
+	logr.SetStreamEncoder(exp, exp)
+	logr.ErrorE().Str(FieldTraceID, "0af7651916cd43dd8448eb211c80319c").
+		Int("attempt", 2).Msg("request failed")
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("Got %d records; want: 1", len(records))
+	}
+	r := records[0]
+	if r.SeverityNumber != 17 || *r.Body.StringValue != "request failed" ||
+		r.TraceID != "0af7651916cd43dd8448eb211c80319c" ||
+		len(r.Attributes) != 1 || *r.Attributes[0].Value.IntValue != "2" {
+		t.Errorf("Got record %+v", r)
+	}
+}
+
+func TestOTLPExporterRetry(t *testing.T) {
+	var mu sync.Mutex
+	fail := true
+	var got []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ResourceLogs []struct {
+				ScopeLogs []struct {
+					LogRecords []otlpRecord
+				}
+			}
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		got = append(got, len(body.ResourceLogs[0].ScopeLogs[0].LogRecords))
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, "test")
+	exp.BatchSize = 2
+	logr := New(LEVEL_DEBUG)
+	logr.AddSink(exp)
+	for i := 0; i < 3; i++ {
+		logr.Infof("entry %d\n", i)
+	}
+	if err := exp.Flush(); err == nil {
+		t.Error("expected an error from the collector")
+	}
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	if err := exp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(got) != "[2 1]" {
+		t.Errorf("\nGot:\t%v\nExpect:\t%v\n", got, "[2 1]")
+	}
+}
+
+func TestSinkWithoutEncoder(t *testing.T) {
+	exp := NewOTLPExporter("http://127.0.0.1:0", "test")
+	defer exp.Close()
+	logr := New(LEVEL_DEBUG, exp)
+	var handled error
+	logr.SetErrorHandler(func(se *StreamError) { handled = se.Err })
+	logr.Infoln("plain text")
+	if handled != errNotEncoded {
+		t.Errorf("\nGot:\t%v\nExpect:\t%v\n", handled, errNotEncoded)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Fields holds the values of named placeholders, see Infot.
+type Fields map[string]interface{}
+
+// inText marks the value of a field that was substituted into the message
+// text, so it is not appended to the text again.
+type inText struct {
+	value interface{}
+}
+
+// expandPlaceholders replaces each {name} in msg with the value of the field
+// name. "{{" and "}}" produce literal braces, and placeholders without a field
+// are left as is. All fields are returned sorted by key, with the values of
+// the substituted ones wrapped in inText.
+func expandPlaceholders(msg string, fields Fields) (string, []Field) {
+	used := make(map[string]bool)
+	var buf strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if (c == '{' || c == '}') && i+1 < len(msg) && msg[i+1] == c {
+			buf.WriteByte(c)
+			i++
+			continue
+		}
+		if c == '{' {
+			if j := strings.IndexByte(msg[i+1:], '}'); j >= 0 {
+				name := msg[i+1 : i+1+j]
+				if v, ok := fields[name]; ok {
+					buf.WriteString(valueText(v))
+					used[name] = true
+					i += j + 1
+					continue
+				}
+			}
+		}
+		buf.WriteByte(c)
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fs := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		if used[k] {
+			fs = append(fs, Field{k, inText{fields[k]}})
+		} else {
+			fs = append(fs, Field{k, fields[k]})
+		}
+	}
+	return buf.String(), fs
+}
+
+// unwrapFields returns fields with the inText values unwrapped, and the keys
+// of those fields. fields is not modified.
+func unwrapFields(fields []Field) ([]Field, map[string]bool) {
+	var out []Field
+	var keys map[string]bool
+	for i, f := range fields {
+		v, ok := f.Value.(inText)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]Field(nil), fields...)
+			keys = make(map[string]bool)
+		}
+		out[i].Value = v.value
+		keys[f.Key] = true
+	}
+	if out == nil {
+		return fields, nil
+	}
+	return out, keys
+}
+
+// Infot writes msg at the LEVEL_INFO level using the standard logging object
+// with named placeholders replaced by fields. See (*Logger).Infot.
+func Infot(msg string, fields Fields) { std.placeholders(LEVEL_INFO, msg, fields) }
+
+// Warningt is like Infot at the LEVEL_WARNING level.
+func Warningt(msg string, fields Fields) { std.placeholders(LEVEL_WARNING, msg, fields) }
+
+// Errort is like Infot at the LEVEL_ERROR level.
+func Errort(msg string, fields Fields) { std.placeholders(LEVEL_ERROR, msg, fields) }
+
+// Criticalt is like Infot at the LEVEL_CRITICAL level.
+func Criticalt(msg string, fields Fields) { std.placeholders(LEVEL_CRITICAL, msg, fields) }
+
+// Infot writes msg at the LEVEL_INFO level with each {name} placeholder
+// replaced by the value of the field name:
+//
+//	logr.Infot("user {user} logged in from {ip}", logs.Fields{
+//		"user": "ann", "ip": ip, "attempts": 2,
+//	})
+//	// [INFO] user ann logged in from 10.0.0.1 attempts=2
+//
+// Fields not used by a placeholder are appended in key=value form. All fields
+// are passed to the stream encoders, so the entry is both readable and
+// structured. Use "{{" and "}}" for literal braces.
+func (l *Logger) Infot(msg string, fields Fields) { l.placeholders(LEVEL_INFO, msg, fields) }
+
+// Warningt is like Infot at the LEVEL_WARNING level.
+func (l *Logger) Warningt(msg string, fields Fields) { l.placeholders(LEVEL_WARNING, msg, fields) }
+
+// Errort is like Infot at the LEVEL_ERROR level.
+func (l *Logger) Errort(msg string, fields Fields) { l.placeholders(LEVEL_ERROR, msg, fields) }
+
+// Criticalt is like Infot at the LEVEL_CRITICAL level.
+func (l *Logger) Criticalt(msg string, fields Fields) { l.placeholders(LEVEL_CRITICAL, msg, fields) }
+
+// placeholders implements the *t functions. It must be called directly by
+// them so that the caller depth is correct.
+func (l *Logger) placeholders(lvl level, msg string, fields Fields) {
+	text, fs := expandPlaceholders(msg, fields)
+	l.fprint(l.Flags(), lvl, 3, 0, fmt.Sprintln(text), fs, nil)
+}
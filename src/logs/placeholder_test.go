@@ -0,0 +1,34 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInfot(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.Infot("user {user} logged in from {ip} {missing} {{literal}}", Fields{
+		"user": "ann lee", "ip": "10.0.0.1", "attempts": 2, "note": "two words",
+	})
+	expect := "user ann lee logged in from 10.0.0.1 {missing} {literal} " +
+		"attempts=2 note=\"two words\"\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestInfotEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetStreamEncoder(&buf, JSONEncoder{})
+	logr.Errort("{n} failed", Fields{"n": 3})
+	expect := `"msg":"3 failed","n":3`
+	if !bytes.Contains(buf.Bytes(), []byte(expect)) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
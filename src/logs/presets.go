@@ -0,0 +1,102 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// preset is a named output format.
+type preset struct {
+	template   string
+	dateFormat string
+	flags      int // Flags needed by the template
+}
+
+// presets are the formats selectable with SetTemplateNamed.
+var presets = map[string]preset{
+	"default": {logFmt, defaultDate, 0},
+
+	// 2009/11/10 23:00:00 message
+	"stdlib": {"{{.Date}} {{.Text}}", "2006/01/02 15:04:05", Ldate},
+
+	// I1110 23:00:00.000000 main.go:12] message
+	"glog": {"{{initial .Level}}{{.Date}} {{.FileName}}:{{.LineNumber}}] {{.Text}}",
+		"0102 15:04:05.000000", Ldate | LshortFileName | LlineNumber},
+
+	// 2009-11-10T23:00:00.000Z	info	main.go:12	message
+	"zap": {"{{.Date}}\t{{lower .Level}}\t{{.FileName}}:{{.LineNumber}}\t{{.Text}}",
+		"2006-01-02T15:04:05.000Z0700", Ldate | LshortFileName | LlineNumber},
+
+	// <14>Nov 10 23:00:00 host prog[123]: message
+	"syslog": {"<{{syslogPri .LevelNum}}>{{.Date}} {{hostname}} {{program}}[{{pid}}]: {{.Text}}",
+		"Jan _2 15:04:05", Ldate},
+
+	// [Tue Nov 10 23:00:00.000000 2009] [info] [pid 123] message
+	"apache": {"[{{.Date}}] [{{lower .Level}}] [pid {{pid}}] {{.Text}}",
+		"Mon Jan 02 15:04:05.000000 2006", Ldate},
+}
+
+// syslogSeverity maps levels to syslog severities.
+var syslogSeverity = [...]int{
+	LEVEL_DEBUG:    7,
+	LEVEL_INFO:     6,
+	LEVEL_WARNING:  4,
+	LEVEL_ERROR:    3,
+	LEVEL_CRITICAL: 2,
+	LEVEL_PRINT:    5,
+}
+
+func init() {
+	funcMap["initial"] = func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return s[:1]
+	}
+	funcMap["lower"] = strings.ToLower
+	funcMap["hostname"] = func() string {
+		h, _ := os.Hostname()
+		return h
+	}
+	funcMap["program"] = func() string { return filepath.Base(os.Args[0]) }
+	funcMap["pid"] = os.Getpid
+	// Priority with the user facility.
+	funcMap["syslogPri"] = func(lvl int) int { return 8 + syslogSeverity[lvl] }
+}
+
+// TemplateNames returns the names accepted by SetTemplateNamed.
+func TemplateNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTemplateNamed sets a preset format for the standard logging object. See
+// (*Logger).SetTemplateNamed.
+func SetTemplateNamed(name string) error { return std.SetTemplateNamed(name) }
+
+// SetTemplateNamed sets the template and date format to a preset matching
+// the output of other tools: "stdlib", "glog", "zap", "syslog", "apache", or
+// "default". The flags the preset needs, for example Ldate and
+// LshortFileName for glog, are added to the flags of the logging object.
+func (l *Logger) SetTemplateNamed(name string) error {
+	p, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("logs: unknown template %q", name)
+	}
+	if err := l.SetTemplate(p.template); err != nil {
+		return err
+	}
+	l.SetDateFormat(p.dateFormat)
+	l.SetFlags(l.Flags() | p.flags)
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSetTemplateNamed(t *testing.T) {
+	var tests = []struct {
+		name   string
+		expect string // Regular expression, %d is the line number
+	}{
+		{"stdlib", `^\d{4}/\d\d/\d\d \d\d:\d\d:\d\d careful\n$`},
+		{"glog", `^W\d{4} \d\d:\d\d:\d\d\.\d{6} presets_test\.go:%d\] careful\n$`},
+		{"zap", `^\d{4}-\d\d-\d\dT[\d:.]+\S+\twarning\tpresets_test\.go:%d\tcareful\n$`},
+		{"syslog", `^<12>\w{3} [ \d]\d \d\d:\d\d:\d\d \S+ \S+\[\d+\]: careful\n$`},
+		{"apache", `^\[\w{3} \w{3} \d\d [\d:.]+ \d{4}\] \[warning\] \[pid \d+\] careful\n$`},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		logr := New(LEVEL_DEBUG, &buf)
+		logr.SetFlags(0)
+		if err := logr.SetTemplateNamed(test.name); err != nil {
+			t.Fatal(err)
+		}
+		_, _, line, _ := runtime.Caller(0)
+		logr.Warningln("careful")
+		re := regexp.MustCompile(strings.Replace(test.expect, "%d", strconv.Itoa(line+1), 1))
+		if !re.MatchString(buf.String()) {
+			t.Errorf("%s\nGot:\t%q\nExpect:\t%q\n", test.name, buf.String(), re)
+		}
+	}
+	if err := New(LEVEL_DEBUG, os.Stderr).SetTemplateNamed("nope"); err == nil {
+		t.Errorf("SetTemplateNamed(%q) = nil; want: error", "nope")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// badVerbRegexp matches the markers fmt writes for wrong verbs, missing or
+// extra arguments, and bad widths and precisions.
+var badVerbRegexp = regexp.MustCompile(`%!(\w\(|\((EXTRA|BADWIDTH|BADPREC|BADINDEX|NOVERB)|\w\(MISSING\))[^)]*\)`)
+
+// SetFormatCheck enables or disables the format check of the standard logging
+// object. See (*Logger).SetFormatCheck.
+func SetFormatCheck(enabled bool) { std.SetFormatCheck(enabled) }
+
+// SetFormatCheck enables a check for format mistakes at run time. When an
+// entry contains the markers fmt writes for mismatched verbs, like
+// "%!d(string=x)" or "%!v(MISSING)", a WARNING entry naming the problem is
+// written after it, with the file and line of the call if those flags are
+// set.
+func (l *Logger) SetFormatCheck(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.formatCheck = enabled
+}
+
+// fprintf formats the text of an entry written by a printf-style function
+// with fprint. If the format check is enabled and the entry was written, a
+// WARNING entry is written after it for format mistakes in the text.
+func (l *Logger) fprintf(flags int, logLevel level, calldepth, indentCount int,
+	fields []Field, format string, v ...interface{}) (n int, err error) {
+	text := fmt.Sprintf(format, v...)
+	n, err = l.fprint(flags, logLevel, calldepth+1, indentCount, text, fields, nil)
+	if n == 0 && err == nil {
+		return
+	}
+	l.mu.Lock()
+	check := l.formatCheck
+	l.mu.Unlock()
+	if !check {
+		return
+	}
+	if bad := badVerbRegexp.FindAllString(text, -1); len(bad) > 0 {
+		l.fprint(flags, LEVEL_WARNING, calldepth+1, indentCount,
+			"bad format in previous entry: "+strings.Join(bad, " ")+"\n", fields, nil)
+	}
+	return
+}
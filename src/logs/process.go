@@ -0,0 +1,29 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os"
+	"os/user"
+	"sync"
+)
+
+var (
+	userOnce    sync.Once
+	currentUser string
+)
+
+// userName returns the name of the user running the process. It is looked up
+// once. If the lookup fails, the USER or USERNAME environment variable is
+// used.
+func userName() string {
+	userOnce.Do(func() {
+		if u, err := user.Current(); err == nil {
+			currentUser = u.Username
+		} else if currentUser = os.Getenv("USER"); currentUser == "" {
+			currentUser = os.Getenv("USERNAME")
+		}
+	})
+	return currentUser
+}
@@ -0,0 +1,39 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProcess(t *testing.T) {
+	var text, js bytes.Buffer
+	logr := New(LEVEL_DEBUG, &text, &js)
+	logr.SetFlags(Lprocess)
+	logr.SetStreamEncoder(&js, JSONEncoder{})
+	logr.Infoln("started")
+
+	expect := fmt.Sprintf("%s[%d:%d] started\n", userName(), os.Getpid(), os.Getppid())
+	if text.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", text.String(), expect)
+	}
+	if want := fmt.Sprintf(`"pid":%d`, os.Getpid()); !strings.Contains(js.String(), want) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", js.String(), want)
+	}
+
+	var mp bytes.Buffer
+	b, _ := MsgpackEncoder{}.Encode(&Entry{Message: "m", Pid: 7, PPid: 1, User: "ann"})
+	mp.Write(b)
+	e, err := NewMsgpackDecoder(&mp).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Pid != 7 || e.PPid != 1 || e.User != "ann" {
+		t.Errorf("\nGot:\t%d %d %q\nExpect:\t7 1 \"ann\"\n", e.Pid, e.PPid, e.User)
+	}
+}
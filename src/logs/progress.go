@@ -0,0 +1,119 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// eraseLine moves the cursor to the start of the line and clears it.
+const eraseLine = "\r\x1b[2K"
+
+// defaultProgressInterval is the time between progress entries on streams
+// that are not terminals.
+var defaultProgressInterval = 5 * time.Second
+
+// Progress writes a progress line using the standard logging object. See
+// (*Logger).Progress for details.
+func Progress(format string, v ...interface{}) {
+	std.progressf(format, v...)
+}
+
+// ProgressDone erases the progress line of the standard logging object.
+func ProgressDone() { std.ProgressDone() }
+
+// SetProgressInterval sets the time between progress entries written to
+// streams that are not terminals by the standard logging object.
+func SetProgressInterval(d time.Duration) { std.SetProgressInterval(d) }
+
+// Progress writes a progress line at the LEVEL_INFO level. On streams that
+// are terminals, the previous progress line is replaced using a carriage
+// return and an ANSI erase sequence, and regular entries are written above
+// the progress line. On other streams, progress is written as a regular entry
+// at most once per progress interval.
+func (l *Logger) Progress(format string, v ...interface{}) {
+	l.progressf(format, v...)
+}
+
+// progressKey marks the context of progress entries, which are only written
+// to streams that are not terminals.
+type progressKey struct{}
+
+// progressEntry reports whether ctx is the context of a progress entry.
+func progressEntry(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(progressKey{}) != nil
+}
+
+// withoutTerminals returns the streams that are not terminals.
+func withoutTerminals(streams []io.Writer) []io.Writer {
+	var others []io.Writer
+	for _, w := range streams {
+		if !isTerminal(w) {
+			others = append(others, w)
+		}
+	}
+	return others
+}
+
+// progressf implements Progress. It must be called directly by an exported
+// function so that the caller depth is correct. The progress line and the
+// progress entries are written like other output, so muted streams, level
+// routes, color modes, and the order of the entries are respected.
+func (l *Logger) progressf(format string, v ...interface{}) {
+	text := fmt.Sprintf(format, v...)
+	l.mu.Lock()
+	if l.level != LEVEL_PRINT && LEVEL_INFO < l.level {
+		l.mu.Unlock()
+		return
+	}
+	l.progress = text
+	var terms []io.Writer
+	for _, w := range l.streams {
+		if isTerminal(w) {
+			terms = append(terms, w)
+		}
+	}
+	due := len(terms) < len(l.streams) && time.Since(l.progressLast) >= l.progressInterval
+	if due {
+		l.progressLast = time.Now()
+	}
+	l.mu.Unlock()
+	if len(terms) > 0 {
+		// The progress line is written after the erase sequence written
+		// for the active progress line.
+		l.writeStreams(isTerminal, []byte(text),
+			&Entry{Time: time.Now(), Level: LEVEL_INFO, Message: text, LoggerName: l.name})
+	}
+	if due {
+		ctx := context.WithValue(context.Background(), progressKey{}, true)
+		l.fprintCtx(ctx, nil, l.Flags(), LEVEL_INFO, 3, 0, text+"\n", nil, nil)
+	}
+}
+
+// ProgressDone erases the progress line from terminal streams. Regular
+// entries are no longer followed by the progress line.
+func (l *Logger) ProgressDone() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.progress == "" {
+		return
+	}
+	l.progress = ""
+	for _, w := range l.streams {
+		if isTerminal(w) {
+			io.WriteString(w, eraseLine)
+		}
+	}
+}
+
+// SetProgressInterval sets the time between progress entries written to
+// streams that are not terminals. The default is five seconds.
+func (l *Logger) SetProgressInterval(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.progressInterval = d
+}
@@ -0,0 +1,61 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestProgressTerminal(t *testing.T) {
+	var term, file bytes.Buffer
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(w io.Writer) bool { return w == &term }
+
+	logr := New(LEVEL_DEBUG, &term, &file)
+	logr.SetFlags(LfunctionName)
+	logr.SetProgressInterval(time.Hour)
+	logr.Progress("copied %d/%d", 1, 3)
+	logr.Progress("copied %d/%d", 2, 3)
+	logr.Infoln("regular entry")
+	logr.ProgressDone()
+	logr.Infoln("after progress")
+
+	expect := "\r\x1b[2Kcopied 1/3" +
+		"\r\x1b[2Kcopied 2/3" +
+		"\r\x1b[2KTestProgressTerminal: regular entry\ncopied 2/3" +
+		"\r\x1b[2KTestProgressTerminal: after progress\n"
+	if term.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", term.String(), expect)
+	}
+	expect = "TestProgressTerminal: copied 1/3\n" +
+		"TestProgressTerminal: regular entry\n" +
+		"TestProgressTerminal: after progress\n"
+	if file.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", file.String(), expect)
+	}
+}
+
+func TestProgressMutedStream(t *testing.T) {
+	var term, file bytes.Buffer
+	defer func(f func(io.Writer) bool) { isTerminal = f }(isTerminal)
+	isTerminal = func(w io.Writer) bool { return w == &term }
+
+	logr := New(LEVEL_DEBUG, &term, &file)
+	logr.SetFlags(0)
+	logr.MuteStream(&term)
+	logr.SetStreamLevels(&file, LEVEL_WARNING)
+	logr.Progress("copied %d/%d", 1, 3)
+	logr.Warningln("slow disk")
+
+	if term.String() != "" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", term.String(), "")
+	}
+	expect := "slow disk\n"
+	if file.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", file.String(), expect)
+	}
+}
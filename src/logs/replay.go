@@ -0,0 +1,59 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Replay reads entries written by JSONEncoder or MsgpackEncoder from r and
+// writes them again using target, for demos, testing dashboards, or
+// reproducing problems. The format is detected from the first byte.
+//
+// The time between entries is the original time divided by speed, so 1
+// replays in real time and 10 ten times faster. A speed of zero or less
+// replays without delay. The replayed entries are stamped with the current
+// time.
+func Replay(r io.Reader, target *Logger, speed float64) error {
+	br := bufio.NewReader(r)
+	c, err := br.Peek(1)
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var next func() (*Entry, error)
+	if c[0] == '{' {
+		dec := json.NewDecoder(br)
+		next = func() (*Entry, error) {
+			var m map[string]interface{}
+			if err := dec.Decode(&m); err != nil {
+				return nil, err
+			}
+			return entryFromMap(m), nil
+		}
+	} else {
+		next = NewMsgpackDecoder(br).Decode
+	}
+
+	var last time.Time
+	for {
+		e, err := next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if speed > 0 && !last.IsZero() && e.Time.After(last) {
+			time.Sleep(time.Duration(float64(e.Time.Sub(last)) / speed))
+		}
+		if !e.Time.IsZero() {
+			last = e.Time
+		}
+		target.fprint(target.Flags(), e.Level, 2, 0, e.Message+"\n", e.Fields, nil)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReplay(t *testing.T) {
+	for _, enc := range []Encoder{JSONEncoder{}, MsgpackEncoder{}} {
+		var captured bytes.Buffer
+		src := New(LEVEL_DEBUG, &captured)
+		src.SetStreamEncoder(&captured, enc)
+		src.InfoE().Str("user", "ann").Msg("login")
+		time.Sleep(20 * time.Millisecond)
+		src.Errorln("failed")
+
+		var out bytes.Buffer
+		target := New(LEVEL_DEBUG, &out)
+		target.SetFlags(Llabel)
+		start := time.Now()
+		if err := Replay(&captured, target, 2); err != nil {
+			t.Fatal(err)
+		}
+		if d := time.Since(start); d < 10*time.Millisecond {
+			t.Errorf("Replay took %s; want at least 10ms", d)
+		}
+		expect := "[INFO]     login user=ann\n[ERROR]    failed\n"
+		if out.String() != expect {
+			t.Errorf("%T\nGot:\t%q\nExpect:\t%q\n", enc, out.String(), expect)
+		}
+	}
+}
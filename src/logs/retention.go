@@ -0,0 +1,70 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Retention prunes old log files matching a glob pattern so long running
+// services do not fill the disk. Files are considered oldest first by
+// modification time. A zero limit is not enforced.
+//
+//	r := &logs.Retention{Pattern: "/var/log/app/app.log.*", MaxCount: 10}
+//	removed, err := r.PruneNow()
+//
+// File rotators should call PruneNow after every rotation, which
+// ReopenableFile.SetRetention arranges.
+type Retention struct {
+	Pattern  string        // Glob matching the rotated or compressed files
+	MaxAge   time.Duration // Remove files older than this
+	MaxSize  int64         // Remove the oldest files until the total size fits
+	MaxCount int           // Keep at most this many files
+}
+
+// PruneNow removes the files that exceed the limits and returns their paths.
+// Removal continues if a file cannot be removed; the first error is returned.
+func (r *Retention) PruneNow() ([]string, error) {
+	paths, err := filepath.Glob(r.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	type file struct {
+		path string
+		info os.FileInfo
+	}
+	var files []file
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err == nil && fi.Mode().IsRegular() {
+			files = append(files, file{p, fi})
+		}
+	}
+	// Newest first, so the files kept come first.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].info.ModTime().After(files[j].info.ModTime())
+	})
+
+	var removed []string
+	var firstErr error
+	var total int64
+	now := time.Now()
+	for i, f := range files {
+		total += f.info.Size()
+		if (r.MaxCount > 0 && i >= r.MaxCount) ||
+			(r.MaxSize > 0 && total > r.MaxSize) ||
+			(r.MaxAge > 0 && now.Sub(f.info.ModTime()) > r.MaxAge) {
+			if err := os.Remove(f.path); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			removed = append(removed, f.path)
+		}
+	}
+	return removed, firstErr
+}
@@ -0,0 +1,91 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeAged(t *testing.T, dir string, age []time.Duration, size int) []string {
+	var paths []string
+	for i, a := range age {
+		p := filepath.Join(dir, fmt.Sprintf("app.log.%d", i))
+		if err := os.WriteFile(p, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mt := time.Now().Add(-a)
+		if err := os.Chtimes(p, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func TestRetentionPruneNow(t *testing.T) {
+	h := time.Hour
+	var tests = []struct {
+		name   string
+		r      Retention
+		expect []int // Indexes of the removed files
+	}{
+		{"count", Retention{MaxCount: 2}, []int{2, 3}},
+		{"age", Retention{MaxAge: 90 * time.Minute}, []int{2, 3}},
+		{"size", Retention{MaxSize: 30}, []int{3}},
+		{"none", Retention{}, nil},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		paths := writeAged(t, dir, []time.Duration{0, h, 2 * h, 3 * h}, 10)
+		test.r.Pattern = filepath.Join(dir, "app.log.*")
+		removed, err := test.r.PruneNow()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expect []string
+		for _, i := range test.expect {
+			expect = append(expect, paths[i])
+		}
+		if !reflect.DeepEqual(removed, expect) {
+			t.Errorf("%s\nGot:\t%q\nExpect:\t%q\n", test.name, removed, expect)
+		}
+	}
+}
+
+func TestReopenableFileRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := NewReopenableFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.SetRetention(&Retention{Pattern: path + ".*", MaxCount: 2})
+	for i := 1; i <= 4; i++ {
+		f.Write([]byte("entry\n"))
+		rotated := fmt.Sprintf("%s.%d", path, i)
+		if err := os.Rename(path, rotated); err != nil {
+			t.Fatal(err)
+		}
+		// Keep the modification times apart, the newest are kept.
+		mt := time.Now().Add(time.Duration(i-4) * time.Hour)
+		os.Chtimes(rotated, mt, mt)
+		if err := f.Reopen(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	left, _ := filepath.Glob(path + ".*")
+	expect := []string{path + ".3", path + ".4"}
+	if !reflect.DeepEqual(left, expect) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", left, expect)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("current file removed: %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// Layout of a ring file: a header holding the magic, the size of the data
+// area, and the total number of bytes ever written, followed by the data
+// area. Each record is the entry followed by its length as a uint32, so the
+// records can be read backwards from the write position.
+const (
+	ringMagic  = "GOLOGSR1"
+	ringHeader = 64
+)
+
+var errNotRingFile = errors.New("logs: not a ring file")
+
+// RingFile is a stream that keeps the most recent output in a fixed size
+// file mapped into memory. Every write goes straight to the page cache, so
+// the entries survive a crash of the process, even one that never got to
+// flush or close its streams. They are written to disk when the operating
+// system flushes the page cache, so entries written shortly before a crash
+// of the machine can be lost. Use ReadRingFile to recover them:
+//
+//	ring, err := logs.NewRingFile("/var/lib/app/crash.ring", 1<<20)
+//	logr.SetStreams(os.Stderr, ring)
+//
+// Memory mapping is only supported on Unix systems.
+type RingFile struct {
+	mu   sync.Mutex
+	file *os.File
+	mem  []byte // The mapped file
+	data []byte // The data area of mem
+}
+
+// NewRingFile opens the ring file at path, creating it with a data area of
+// size bytes if it does not exist. An existing ring file keeps its entries
+// and size. The size must be larger than 4 bytes, even for an existing file.
+func NewRingFile(path string, size int) (*RingFile, error) {
+	if size <= 4 {
+		return nil, errors.New("logs: ring file size too small")
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	var hdr [ringHeader]byte
+	if _, err := f.ReadAt(hdr[:], 0); err == nil && string(hdr[:8]) == ringMagic {
+		n := binary.LittleEndian.Uint64(hdr[8:])
+		if !ringSizeValid(n, fi.Size()) {
+			f.Close()
+			return nil, errNotRingFile
+		}
+		size = int(n)
+	} else if fi.Size() == 0 {
+		copy(hdr[:], ringMagic)
+		binary.LittleEndian.PutUint64(hdr[8:], uint64(size))
+		if _, err := f.WriteAt(hdr[:], 0); err != nil {
+			f.Close()
+			return nil, err
+		}
+	} else {
+		f.Close()
+		return nil, errNotRingFile
+	}
+	if err := f.Truncate(int64(ringHeader + size)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	mem, err := mmapFile(f, ringHeader+size)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &RingFile{file: f, mem: mem, data: mem[ringHeader:]}, nil
+}
+
+// Write adds p as one record. Older records are overwritten when the file is
+// full. Entries longer than the data area are truncated to fit.
+func (r *RingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mem == nil {
+		return 0, os.ErrClosed
+	}
+	n := len(p)
+	if max := len(r.data) - 4; len(p) > max {
+		p = p[:max]
+	}
+	head := binary.LittleEndian.Uint64(r.mem[16:])
+	var l [4]byte
+	binary.LittleEndian.PutUint32(l[:], uint32(len(p)))
+	ringCopy(r.data, head, p)
+	ringCopy(r.data, head+uint64(len(p)), l[:])
+	// The record becomes visible once the position is updated.
+	binary.LittleEndian.PutUint64(r.mem[16:], head+uint64(len(p))+4)
+	return n, nil
+}
+
+// Close unmaps and closes the file.
+func (r *RingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mem == nil {
+		return nil
+	}
+	err := munmapFile(r.mem)
+	r.mem, r.data = nil, nil
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ringSizeValid reports whether size, read from the header of a ring file,
+// holds at least one record and matches the size of the file.
+func ringSizeValid(size uint64, fileSize int64) bool {
+	return size > 4 && fileSize >= ringHeader && size == uint64(fileSize-ringHeader)
+}
+
+// ringCopy copies p into data at the position off, wrapping around the end.
+func ringCopy(data []byte, off uint64, p []byte) {
+	pos := int(off % uint64(len(data)))
+	n := copy(data[pos:], p)
+	copy(data, p[n:])
+}
+
+// ringRead reads n bytes from data at the position off, wrapping around the
+// end.
+func ringRead(data []byte, off uint64, n int) []byte {
+	b := make([]byte, n)
+	pos := int(off % uint64(len(data)))
+	m := copy(b, data[pos:])
+	copy(b[m:], data)
+	return b
+}
+
+// ReadRingFile returns the last n entries written to the ring file at path,
+// oldest first, or all entries if n is zero or less. It works on files left
+// behind by a crashed process and does not need memory mapping.
+func ReadRingFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var hdr [ringHeader]byte
+	if _, err := io.ReadFull(f, hdr[:]); err != nil || string(hdr[:8]) != ringMagic {
+		return nil, errNotRingFile
+	}
+	size := binary.LittleEndian.Uint64(hdr[8:])
+	head := binary.LittleEndian.Uint64(hdr[16:])
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !ringSizeValid(size, fi.Size()) {
+		return nil, errNotRingFile
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+
+	avail := head
+	if avail > size {
+		avail = size
+	}
+	var entries []string
+	for pos := head; n <= 0 || len(entries) < n; {
+		used := head - pos
+		if used+4 > avail {
+			break
+		}
+		l := uint64(binary.LittleEndian.Uint32(ringRead(data, pos-4, 4)))
+		if used+4+l > avail {
+			// Partly overwritten by newer records.
+			break
+		}
+		entries = append(entries, string(ringRead(data, pos-4-l, int(l))))
+		pos -= 4 + l
+	}
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ring")
+	r, err := NewRingFile(path, 64)
+	if err != nil {
+		t.Skip(err)
+	}
+	l := New(LEVEL_DEBUG, r)
+	l.SetFlags(0)
+	for i := 0; i < 20; i++ {
+		l.Printf("entry %d\n", i)
+	}
+	// Read without closing, as after a crash.
+	got, err := ReadRingFile(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each record takes 13 bytes, so the last four fit in 64 bytes.
+	var expect []string
+	for i := 16; i < 20; i++ {
+		expect = append(expect, fmt.Sprintf("entry %d\n", i))
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+	if got, _ := ReadRingFile(path, 2); !reflect.DeepEqual(got, expect[2:]) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect[2:])
+	}
+	r.Close()
+
+	// Reopening keeps the entries.
+	r, err = NewRingFile(path, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	l.SetStreams(r)
+	l.Println("entry 20")
+	got, _ = ReadRingFile(path, 2)
+	if expect := []string{"entry 19\n", "entry 20\n"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, expect)
+	}
+}
+
+func TestReadRingFileBadSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.ring")
+	for _, size := range []uint64{0, 4, 1 << 62, 128} {
+		hdr := make([]byte, ringHeader+64)
+		copy(hdr, ringMagic)
+		binary.LittleEndian.PutUint64(hdr[8:], size)
+		binary.LittleEndian.PutUint64(hdr[16:], 10)
+		if err := os.WriteFile(path, hdr, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadRingFile(path, 0); err != errNotRingFile {
+			t.Errorf("\nGot:\t%d: %v\nExpect:\t%d: %v\n", size, err, size, errNotRingFile)
+		}
+	}
+}
+
+func TestNewRingFileBadSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "small.ring")
+	for _, size := range []int{-1, 0, 4} {
+		if _, err := NewRingFile(path, size); err == nil {
+			t.Errorf("NewRingFile(%d) = nil; want: error", size)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("NewRingFile(%d) created the file: %v", size, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// runtimeStats logs runtime statistics until stop is closed.
+type runtimeStats struct {
+	stop   chan struct{}
+	numGC  uint32 // NumGC at the last report
+	pauses uint64 // PauseTotalNs at the last report
+}
+
+// openFDs returns the number of open file descriptors, or -1 if it is not
+// known on this platform.
+func openFDs() int {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return -1
+	}
+	return len(fds)
+}
+
+// report writes one entry with the current statistics. Garbage collection
+// numbers are for the time since the previous report.
+func (s *runtimeStats) report(l *Logger) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	e := l.InfoE()
+	if e == nil {
+		return
+	}
+	e.Int("goroutines", runtime.NumGoroutine()).
+		Int64("heap_alloc", int64(ms.HeapAlloc)).
+		Int64("heap_sys", int64(ms.HeapSys)).
+		Int64("gc_count", int64(ms.NumGC-s.numGC)).
+		Dur("gc_pause", time.Duration(ms.PauseTotalNs-s.pauses))
+	if n := openFDs(); n >= 0 {
+		e.Int("open_fds", n)
+	}
+	s.numGC, s.pauses = ms.NumGC, ms.PauseTotalNs
+	e.Msg("runtime stats")
+}
+
+func (s *runtimeStats) run(l *Logger, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.report(l)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// RuntimeStats periodically logs runtime statistics using the standard logging
+// object. See (*Logger).RuntimeStats for details.
+func RuntimeStats(interval time.Duration) { std.RuntimeStats(interval) }
+
+// RuntimeStats logs the goroutine count, heap size, garbage collections and
+// their pause time, and open file descriptor count as fields of an INFO entry
+// every interval. Calling it again changes the interval, and an interval of
+// zero stops the reports.
+func (l *Logger) RuntimeStats(interval time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.runtimeStats != nil {
+		close(l.runtimeStats.stop)
+		l.runtimeStats = nil
+	}
+	if interval <= 0 {
+		return
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	l.runtimeStats = &runtimeStats{stop: make(chan struct{}),
+		numGC: ms.NumGC, pauses: ms.PauseTotalNs}
+	go l.runtimeStats.run(l, interval)
+}
@@ -0,0 +1,32 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStats(t *testing.T) {
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &lockedWriter{mu: &mu, w: &buf})
+	logr.SetFlags(0)
+	logr.RuntimeStats(10 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	logr.RuntimeStats(0)
+
+	mu.Lock()
+	defer mu.Unlock()
+	line := strings.SplitN(buf.String(), "\n", 2)[0]
+	for _, key := range []string{"runtime stats ", "goroutines=", "heap_alloc=",
+		"heap_sys=", "gc_count=", "gc_pause="} {
+		if !strings.Contains(line, key) {
+			t.Errorf("%q does not contain %q", line, key)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Scope is a section of output started with Group(). Entries written through
+// a scope are indented one level deeper than the line that opened it. A scope
+// can be used from multiple goroutines.
+//
+// Entries written directly to the logging object are not indented. To indent
+// the events of code that only has a context, for example a request handler,
+// pass it the context returned by (*Scope).Context.
+type Scope struct {
+	logr  *Logger
+	msg   string
+	depth int // Indent count of the entries in the scope
+	start time.Time
+	ended int32 // Set to 1 by End
+}
+
+// scopeDepthKey is the context key of the indent count set by scopes and
+// Push.
+type scopeDepthKey struct{}
+
+// scopeDepth returns the indent count carried by ctx, or 0.
+func scopeDepth(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	d, _ := ctx.Value(scopeDepthKey{}).(int)
+	return d
+}
+
+// Group writes msg at the LEVEL_INFO level using the standard logging object
+// and returns a scope for the entries that belong to it. Call End() on the
+// scope to write the closing line.
+func Group(msg string) *Scope { return std.group(msg, 0) }
+
+// Group writes msg at the LEVEL_INFO level and returns a scope for the
+// entries that belong to it. Call End() on the scope to write the closing
+// line.
+func (l *Logger) Group(msg string) *Scope { return l.group(msg, 0) }
+
+// GroupContext opens a scope on the standard logging object nested in the
+// scope carried by ctx. See (*Logger).GroupContext.
+func GroupContext(ctx context.Context, msg string) *Scope {
+	return std.group(msg, scopeDepth(ctx))
+}
+
+// GroupContext is like Group, but the scope is nested in the scope carried by
+// ctx, if any.
+func (l *Logger) GroupContext(ctx context.Context, msg string) *Scope {
+	return l.group(msg, scopeDepth(ctx))
+}
+
+// group opens a scope with depth indents. It must be called directly by an
+// exported function so that the caller depth is correct.
+func (l *Logger) group(msg string, depth int) *Scope {
+	l.fprint(l.Flags(), LEVEL_INFO, 3, depth, msg+"\n", nil, nil)
+	return &Scope{logr: l, msg: msg, depth: depth + 1, start: time.Now()}
+}
+
+// Group opens a nested scope.
+func (s *Scope) Group(msg string) *Scope { return s.logr.group(msg, s.depth) }
+
+// Context returns a copy of ctx carrying the scope. Events written with the
+// context are indented as if they were written through the scope, and scopes
+// opened with GroupContext are nested in it:
+//
+//	g := logr.Group("handling request")
+//	defer g.End()
+//	ctx = g.Context(ctx)
+//	logr.InfoE().Ctx(ctx).Msg("query done")
+func (s *Scope) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeDepthKey{}, s.depth)
+}
+
+// End writes the closing line of the scope at the indent level of the opening
+// line, for example "loading config done elapsed=12ms". Only the first call
+// to End writes output. Scopes may be ended in any order.
+func (s *Scope) End() {
+	if !atomic.CompareAndSwapInt32(&s.ended, 0, 1) {
+		return
+	}
+	s.logr.fprint(s.logr.Flags(), LEVEL_INFO, 2, s.depth-1,
+		fmt.Sprintf("%s done elapsed=%s\n", s.msg, time.Since(s.start)), nil, nil)
+}
+
+// Printf is equivalent to (*Logger).Printf() with the indentation of the scope.
+func (s *Scope) Printf(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_PRINT, 2, s.depth, nil, format, v...)
+}
+
+// Print is equivalent to (*Logger).Print() with the indentation of the scope.
+func (s *Scope) Print(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_PRINT, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Println is equivalent to (*Logger).Println() with the indentation of the scope.
+func (s *Scope) Println(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_PRINT, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
+
+// Debugf is equivalent to (*Logger).Debugf() with the indentation of the scope.
+func (s *Scope) Debugf(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_DEBUG, 2, s.depth, nil, format, v...)
+}
+
+// Debug is equivalent to (*Logger).Debug() with the indentation of the scope.
+func (s *Scope) Debug(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_DEBUG, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Debugln is equivalent to (*Logger).Debugln() with the indentation of the scope.
+func (s *Scope) Debugln(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_DEBUG, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
+
+// Infof is equivalent to (*Logger).Infof() with the indentation of the scope.
+func (s *Scope) Infof(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_INFO, 2, s.depth, nil, format, v...)
+}
+
+// Info is equivalent to (*Logger).Info() with the indentation of the scope.
+func (s *Scope) Info(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_INFO, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Infoln is equivalent to (*Logger).Infoln() with the indentation of the scope.
+func (s *Scope) Infoln(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_INFO, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
+
+// Warningf is equivalent to (*Logger).Warningf() with the indentation of the scope.
+func (s *Scope) Warningf(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_WARNING, 2, s.depth, nil, format, v...)
+}
+
+// Warning is equivalent to (*Logger).Warning() with the indentation of the scope.
+func (s *Scope) Warning(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_WARNING, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Warningln is equivalent to (*Logger).Warningln() with the indentation of the scope.
+func (s *Scope) Warningln(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_WARNING, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
+
+// Errorf is equivalent to (*Logger).Errorf() with the indentation of the scope.
+func (s *Scope) Errorf(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_ERROR, 2, s.depth, nil, format, v...)
+}
+
+// Error is equivalent to (*Logger).Error() with the indentation of the scope.
+func (s *Scope) Error(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_ERROR, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Errorln is equivalent to (*Logger).Errorln() with the indentation of the scope.
+func (s *Scope) Errorln(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_ERROR, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
+
+// Criticalf is equivalent to (*Logger).Criticalf() with the indentation of the scope.
+func (s *Scope) Criticalf(format string, v ...interface{}) {
+	s.logr.fprintf(s.logr.Flags(), LEVEL_CRITICAL, 2, s.depth, nil, format, v...)
+}
+
+// Critical is equivalent to (*Logger).Critical() with the indentation of the scope.
+func (s *Scope) Critical(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_CRITICAL, 2, s.depth, fmt.Sprint(v...), nil, nil)
+}
+
+// Criticalln is equivalent to (*Logger).Criticalln() with the indentation of the scope.
+func (s *Scope) Criticalln(v ...interface{}) {
+	s.logr.fprint(s.logr.Flags(), LEVEL_CRITICAL, 2, s.depth, fmt.Sprintln(v...), nil, nil)
+}
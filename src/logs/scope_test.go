@@ -0,0 +1,64 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LfunctionName)
+	g := logr.Group("loading config")
+	g.Debugln("reading file")
+	n := g.Group("parsing")
+	n.Warningln("unknown key")
+	n.End()
+	g.End()
+	g.End()
+	expect := regexp.MustCompile(`^TestGroup: loading config
+    TestGroup: reading file
+    TestGroup: parsing
+        TestGroup: unknown key
+    TestGroup: parsing done elapsed=\S+
+TestGroup: loading config done elapsed=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
+
+func TestGroupContext(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	g := logr.Group("worker 1")
+	ctx := g.Context(context.Background())
+	logr.InfoE().Ctx(ctx).Msg("inside worker 1")
+	done := make(chan struct{})
+	go func() {
+		logr.Infoln("other goroutine")
+		close(done)
+	}()
+	<-done
+	n := logr.GroupContext(ctx, "step")
+	g.End()
+	n.End()
+	logr.Infoln("after worker 1")
+	expect := regexp.MustCompile(`^worker 1
+    inside worker 1
+other goroutine
+    step
+worker 1 done elapsed=\S+
+    step done elapsed=\S+
+after worker 1
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\n%s\nExpect:\n%s\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "fmt"
+
+// FieldsLogger writes entries with a fixed set of fields, which are appended
+// to the text like the fields of events. It is returned by WithField,
+// WithFields, and WithError, whose names and methods match logrus to ease
+// migrating code from it. A FieldsLogger can be used from multiple
+// goroutines.
+//
+//	logr.WithField("user", id).WithError(err).Warn("login failed")
+type FieldsLogger struct {
+	logr   *Logger
+	fields []Field
+}
+
+// withFields returns a copy of fields with fs added, replacing existing keys.
+func withFields(fields []Field, fs ...Field) []Field {
+	out := make([]Field, 0, len(fields)+len(fs))
+	for _, f := range fields {
+		replaced := false
+		for _, n := range fs {
+			if n.Key == f.Key {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, f)
+		}
+	}
+	return append(out, fs...)
+}
+
+// mapFields returns the fields of m sorted by key.
+func mapFields(m Fields) []Field {
+	fs := make([]Field, 0, len(m))
+	for k, v := range m {
+		fs = append(fs, Field{k, v})
+	}
+	sortFields(fs)
+	return fs
+}
+
+// keyValues returns the fields of alternating keys and values, as taken by
+// the *w functions. A key without a value gets the key "!BADKEY", as in zap.
+func keyValues(kv []interface{}) []Field {
+	fs := make([]Field, 0, len(kv)/2+1)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 == len(kv) {
+			fs = append(fs, Field{"!BADKEY", kv[i]})
+			break
+		}
+		fs = append(fs, Field{fmt.Sprint(kv[i]), kv[i+1]})
+	}
+	return fs
+}
+
+// WithField returns a FieldsLogger for the standard logging object adding the
+// field key to every entry.
+func WithField(key string, value interface{}) *FieldsLogger { return std.WithField(key, value) }
+
+// WithFields returns a FieldsLogger for the standard logging object adding
+// fields to every entry.
+func WithFields(fields Fields) *FieldsLogger { return std.WithFields(fields) }
+
+// WithError returns a FieldsLogger for the standard logging object adding err
+// as the error field to every entry.
+func WithError(err error) *FieldsLogger { return std.WithError(err) }
+
+// WithField returns a FieldsLogger adding the field key to every entry.
+func (l *Logger) WithField(key string, value interface{}) *FieldsLogger {
+	return &FieldsLogger{logr: l, fields: []Field{{key, value}}}
+}
+
+// WithFields returns a FieldsLogger adding fields to every entry, sorted by
+// key.
+func (l *Logger) WithFields(fields Fields) *FieldsLogger {
+	return &FieldsLogger{logr: l, fields: mapFields(fields)}
+}
+
+// WithError returns a FieldsLogger adding err as the error field to every
+// entry.
+func (l *Logger) WithError(err error) *FieldsLogger { return l.WithField("error", err) }
+
+// WithField returns a FieldsLogger with the field key added.
+func (f *FieldsLogger) WithField(key string, value interface{}) *FieldsLogger {
+	return &FieldsLogger{logr: f.logr, fields: withFields(f.fields, Field{key, value})}
+}
+
+// WithFields returns a FieldsLogger with fields added.
+func (f *FieldsLogger) WithFields(fields Fields) *FieldsLogger {
+	return &FieldsLogger{logr: f.logr, fields: withFields(f.fields, mapFields(fields)...)}
+}
+
+// WithError returns a FieldsLogger with err added as the error field.
+func (f *FieldsLogger) WithError(err error) *FieldsLogger { return f.WithField("error", err) }
+
+// Printf is equivalent to (*Logger).Printf() with the fields added.
+func (f *FieldsLogger) Printf(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_PRINT, 2, 0, f.fields, format, v...)
+}
+
+// Print is equivalent to (*Logger).Print() with the fields added.
+func (f *FieldsLogger) Print(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_PRINT, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Println is equivalent to (*Logger).Println() with the fields added.
+func (f *FieldsLogger) Println(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_PRINT, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Debugf is equivalent to (*Logger).Debugf() with the fields added.
+func (f *FieldsLogger) Debugf(format string, v ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	f.logr.fprintf(f.logr.Flags(), LEVEL_DEBUG, 2, 0, f.fields, format, v...)
+}
+
+// Debug is equivalent to (*Logger).Debug() with the fields added.
+func (f *FieldsLogger) Debug(v ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	f.logr.fprint(f.logr.Flags(), LEVEL_DEBUG, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Debugln is equivalent to (*Logger).Debugln() with the fields added.
+func (f *FieldsLogger) Debugln(v ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	f.logr.fprint(f.logr.Flags(), LEVEL_DEBUG, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Infof is equivalent to (*Logger).Infof() with the fields added.
+func (f *FieldsLogger) Infof(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_INFO, 2, 0, f.fields, format, v...)
+}
+
+// Info is equivalent to (*Logger).Info() with the fields added.
+func (f *FieldsLogger) Info(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_INFO, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Infoln is equivalent to (*Logger).Infoln() with the fields added.
+func (f *FieldsLogger) Infoln(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_INFO, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Warnf is equivalent to (*Logger).Warningf() with the fields added.
+func (f *FieldsLogger) Warnf(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_WARNING, 2, 0, f.fields, format, v...)
+}
+
+// Warn is equivalent to (*Logger).Warning() with the fields added.
+func (f *FieldsLogger) Warn(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_WARNING, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Warnln is equivalent to (*Logger).Warningln() with the fields added.
+func (f *FieldsLogger) Warnln(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_WARNING, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Warningf is equivalent to (*Logger).Warningf() with the fields added.
+func (f *FieldsLogger) Warningf(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_WARNING, 2, 0, f.fields, format, v...)
+}
+
+// Warning is equivalent to (*Logger).Warning() with the fields added.
+func (f *FieldsLogger) Warning(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_WARNING, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Warningln is equivalent to (*Logger).Warningln() with the fields added.
+func (f *FieldsLogger) Warningln(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_WARNING, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Errorf is equivalent to (*Logger).Errorf() with the fields added.
+func (f *FieldsLogger) Errorf(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_ERROR, 2, 0, f.fields, format, v...)
+}
+
+// Error is equivalent to (*Logger).Error() with the fields added.
+func (f *FieldsLogger) Error(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_ERROR, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Errorln is equivalent to (*Logger).Errorln() with the fields added.
+func (f *FieldsLogger) Errorln(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_ERROR, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// Criticalf is equivalent to (*Logger).Criticalf() with the fields added.
+func (f *FieldsLogger) Criticalf(format string, v ...interface{}) {
+	f.logr.fprintf(f.logr.Flags(), LEVEL_CRITICAL, 2, 0, f.fields, format, v...)
+}
+
+// Critical is equivalent to (*Logger).Critical() with the fields added.
+func (f *FieldsLogger) Critical(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_CRITICAL, 2, 0, fmt.Sprint(v...), f.fields, nil)
+}
+
+// Criticalln is equivalent to (*Logger).Criticalln() with the fields added.
+func (f *FieldsLogger) Criticalln(v ...interface{}) {
+	f.logr.fprint(f.logr.Flags(), LEVEL_CRITICAL, 2, 0, fmt.Sprintln(v...), f.fields, nil)
+}
+
+// sugared implements the *w functions. It must be called directly by them so
+// that the caller depth is correct.
+func (l *Logger) sugared(lvl level, fields []Field, msg string, kv []interface{}) {
+	l.fprint(l.Flags(), lvl, 3, 0, msg+"\n", withFields(fields, keyValues(kv)...), nil)
+}
+
+// Debugw writes msg at the LEVEL_DEBUG level using the standard logging
+// object, with keysAndValues as alternating keys and values, like the method
+// of the zap SugaredLogger.
+func Debugw(msg string, keysAndValues ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	std.sugared(LEVEL_DEBUG, nil, msg, keysAndValues)
+}
+
+// Infow writes msg at the LEVEL_INFO level using the standard logging
+// object, with keysAndValues as alternating keys and values, like the method
+// of the zap SugaredLogger.
+func Infow(msg string, keysAndValues ...interface{}) {
+	std.sugared(LEVEL_INFO, nil, msg, keysAndValues)
+}
+
+// Warnw writes msg at the LEVEL_WARNING level using the standard logging
+// object, with keysAndValues as alternating keys and values, like the method
+// of the zap SugaredLogger.
+func Warnw(msg string, keysAndValues ...interface{}) {
+	std.sugared(LEVEL_WARNING, nil, msg, keysAndValues)
+}
+
+// Errorw writes msg at the LEVEL_ERROR level using the standard logging
+// object, with keysAndValues as alternating keys and values, like the method
+// of the zap SugaredLogger.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	std.sugared(LEVEL_ERROR, nil, msg, keysAndValues)
+}
+
+// Debugw writes msg at the LEVEL_DEBUG level with keysAndValues as alternating
+// keys and values, like the method of the zap SugaredLogger.
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	l.sugared(LEVEL_DEBUG, nil, msg, keysAndValues)
+}
+
+// Infow writes msg at the LEVEL_INFO level with keysAndValues as alternating
+// keys and values, like the method of the zap SugaredLogger.
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.sugared(LEVEL_INFO, nil, msg, keysAndValues)
+}
+
+// Warnw writes msg at the LEVEL_WARNING level with keysAndValues as alternating
+// keys and values, like the method of the zap SugaredLogger.
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.sugared(LEVEL_WARNING, nil, msg, keysAndValues)
+}
+
+// Errorw writes msg at the LEVEL_ERROR level with keysAndValues as alternating
+// keys and values, like the method of the zap SugaredLogger.
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.sugared(LEVEL_ERROR, nil, msg, keysAndValues)
+}
+
+// Debugw is equivalent to (*Logger).Debugw() with the fields added.
+func (f *FieldsLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	if !DebugEnabled {
+		return
+	}
+	f.logr.sugared(LEVEL_DEBUG, f.fields, msg, keysAndValues)
+}
+
+// Infow is equivalent to (*Logger).Infow() with the fields added.
+func (f *FieldsLogger) Infow(msg string, keysAndValues ...interface{}) {
+	f.logr.sugared(LEVEL_INFO, f.fields, msg, keysAndValues)
+}
+
+// Warnw is equivalent to (*Logger).Warnw() with the fields added.
+func (f *FieldsLogger) Warnw(msg string, keysAndValues ...interface{}) {
+	f.logr.sugared(LEVEL_WARNING, f.fields, msg, keysAndValues)
+}
+
+// Errorw is equivalent to (*Logger).Errorw() with the fields added.
+func (f *FieldsLogger) Errorw(msg string, keysAndValues ...interface{}) {
+	f.logr.sugared(LEVEL_ERROR, f.fields, msg, keysAndValues)
+}
@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(Llabel | LfunctionName)
+	l := logr.WithFields(Fields{"user": "bob", "attempt": 3})
+	l.WithError(errors.New("bad password")).Warnln("login failed")
+	l.WithField("user", "alice").Infoln("login ok")
+	l.Debugln("not shown")
+	expect := "[WARNING]  TestWithFields: login failed attempt=3 user=bob " +
+		"error=\"bad password\"\n" +
+		"[INFO]     TestWithFields: login ok attempt=3 user=alice\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestSugared(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(Llabel | LfunctionName)
+	logr.Infow("request done", "path", "/", "status", 200)
+	logr.WithField("id", 7).Errorw("request failed", "status", 500, "dangling")
+	logr.Debugw("not shown", "a", 1)
+	expect := "[INFO]     TestSugared: request done path=/ status=200\n" +
+		"[ERROR]    TestSugared: request failed id=7 status=500 !BADKEY=dangling\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
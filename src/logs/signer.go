@@ -0,0 +1,155 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// macSeparator separates a line from its signature.
+const macSeparator = " hmac="
+
+// SigningWriter is a stream wrapper that makes output tamper evident. Each
+// line written is followed by an HMAC-SHA256 signature of the line chained
+// with the signature of the previous line, so modified, reordered, or removed
+// lines are detected by Verify. Incomplete lines are held until their newline
+// is written.
+type SigningWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	key     []byte
+	prev    []byte // Signature of the previous line
+	partial []byte // Incomplete line waiting for a newline
+}
+
+// NewSigningWriter returns a writer signing every line written to w with
+// key.
+func NewSigningWriter(w io.Writer, key []byte) *SigningWriter {
+	return &SigningWriter{w: w, key: key}
+}
+
+// NewSigningWriterChain is like NewSigningWriter, but continues the chain
+// ending with the signature prev, so a file can be appended to after a
+// restart and still pass Verify:
+//
+//	prev, err := logs.LastSignature(path)
+//	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+//	w := logs.NewSigningWriterChain(f, key, prev)
+func NewSigningWriterChain(w io.Writer, key, prev []byte) *SigningWriter {
+	return &SigningWriter{w: w, key: key, prev: append([]byte(nil), prev...)}
+}
+
+// LastSignature returns the signature of the last signed line of the file at
+// path, or nil if the file is empty or does not exist.
+func LastSignature(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<24)
+	var last []byte
+	for sc.Scan() {
+		line := sc.Bytes()
+		i := bytes.LastIndex(line, []byte(macSeparator))
+		if i < 0 {
+			return nil, fmt.Errorf("logs: %s has an unsigned line", path)
+		}
+		if last, err = hex.DecodeString(string(line[i+len(macSeparator):])); err != nil {
+			return nil, fmt.Errorf("logs: %s has an invalid signature", path)
+		}
+	}
+	return last, sc.Err()
+}
+
+// sign returns the chained signature of line.
+func sign(key, prev, line []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(prev)
+	mac.Write(line)
+	return mac.Sum(nil)
+}
+
+// Write signs and writes every complete line in p. The chain only advances
+// if the lines were written, so a failed write can be retried.
+func (s *SigningWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := append(s.partial[:len(s.partial):len(s.partial)], p...)
+	prev := s.prev
+	var out bytes.Buffer
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := data[:i]
+		prev = sign(s.key, prev, line)
+		out.Write(line)
+		out.WriteString(macSeparator)
+		out.WriteString(hex.EncodeToString(prev))
+		out.WriteByte('\n')
+		data = data[i+1:]
+	}
+	if out.Len() > 0 {
+		if _, err := s.w.Write(out.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	s.prev = prev
+	s.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// Verify checks the signatures of the lines read from r and returns the number
+// of lines that were verified. An error is returned for the first line that
+// is unsigned or does not match the chain. Lines removed from the end cannot
+// be detected by the chain alone, so the returned count should be compared
+// with the expected number of lines, for example the last sequence number
+// written in audit mode.
+func Verify(r io.Reader, key []byte) (int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	var prev []byte
+	n := 0
+	for sc.Scan() {
+		line := sc.Bytes()
+		i := bytes.LastIndex(line, []byte(macSeparator))
+		if i < 0 {
+			return n, fmt.Errorf("logs: line %d is not signed", n+1)
+		}
+		got, err := hex.DecodeString(string(line[i+len(macSeparator):]))
+		if err != nil {
+			return n, fmt.Errorf("logs: line %d has an invalid signature", n+1)
+		}
+		want := sign(key, prev, line[:i])
+		if !hmac.Equal(got, want) {
+			return n, fmt.Errorf("logs: line %d has been modified", n+1)
+		}
+		prev = want
+		n++
+	}
+	return n, sc.Err()
+}
+
+// VerifyFile checks the signatures of the file at path. See Verify.
+func VerifyFile(path string, key []byte) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return Verify(f, key)
+}
@@ -0,0 +1,76 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSigningWriter(t *testing.T) {
+	key := []byte("secret")
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, NewSigningWriter(&buf, key))
+	logr.SetFlags(0)
+	logr.Println("first")
+	logr.Print("second ")
+	logr.Println("line")
+	logr.Println("third")
+
+	if n, err := Verify(bytes.NewReader(buf.Bytes()), key); n != 3 || err != nil {
+		t.Errorf("Verify() = %d, %v; want: 3, nil", n, err)
+	}
+	lines := strings.SplitAfter(buf.String(), "\n")
+	if !strings.HasPrefix(lines[1], "second line hmac=") {
+		t.Errorf("Line 2 = %q; want prefix %q", lines[1], "second line hmac=")
+	}
+
+	modified := strings.Replace(buf.String(), "second", "Second", 1)
+	if n, err := Verify(strings.NewReader(modified), key); n != 1 || err == nil {
+		t.Errorf("Verify(modified) = %d, %v; want: 1, error", n, err)
+	}
+	removed := lines[0] + lines[2]
+	if n, err := Verify(strings.NewReader(removed), key); n != 1 || err == nil {
+		t.Errorf("Verify(removed) = %d, %v; want: 1, error", n, err)
+	}
+}
+
+func TestSigningWriterChain(t *testing.T) {
+	key := []byte("secret")
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, line := range []string{"first run\n", "second run\n"} {
+		prev, err := LastSignature(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		NewSigningWriterChain(f, key, prev).Write([]byte(line))
+		f.Close()
+	}
+	if n, err := VerifyFile(path, key); n != 2 || err != nil {
+		t.Errorf("VerifyFile() = %d, %v; want: 2, nil", n, err)
+	}
+}
+
+func TestSigningWriterRetry(t *testing.T) {
+	key := []byte("secret")
+	w := &flakyWriter{}
+	s := NewSigningWriter(w, key)
+	s.Write([]byte("first\n"))
+	w.down = true
+	if _, err := s.Write([]byte("lost\n")); err == nil {
+		t.Error("Write() = nil; want: error")
+	}
+	w.down = false
+	s.Write([]byte("second\n"))
+	if n, err := Verify(bytes.NewReader(w.Bytes()), key); n != 2 || err != nil {
+		t.Errorf("Verify() = %d, %v; want: 2, nil", n, err)
+	}
+}
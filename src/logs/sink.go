@@ -0,0 +1,41 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"errors"
+	"io"
+)
+
+// errNotEncoded is returned by sinks written without their own encoder.
+var errNotEncoded = errors.New("logs: sink written without its encoder, add it with AddSink")
+
+// Sink is a stream that is its own encoder, like OTLPExporter, SQLSink,
+// NATSSink, and MQTTSink. It only accepts entries encoded by its Encode
+// method.
+type Sink interface {
+	io.Writer
+	Encoder
+}
+
+// AddSink adds s to the streams of the standard logging object with itself as
+// its encoder. See (*Logger).AddSink.
+func AddSink(s Sink) { std.AddSink(s) }
+
+// AddSink adds s to the streams of the logging object and sets s as its own
+// encoder, in one step so no entry reaches it unencoded:
+//
+//	exp := logs.NewOTLPExporter("http://localhost:4318/v1/logs", "api")
+//	defer exp.Close()
+//	logr.AddSink(exp)
+//
+// It replaces calling AddStream and SetStreamEncoder(s, s) separately.
+func (l *Logger) AddSink(s Sink) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setStreamEncoder(s, s)
+	streams := make([]io.Writer, len(l.streams), len(l.streams)+1)
+	copy(streams, l.streams)
+	l.streams = append(streams, s)
+}
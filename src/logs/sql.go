@@ -0,0 +1,344 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field keys stored in the logger and caller columns of SQL sinks.
+const (
+	FieldLogger = "logger"
+	FieldCaller = "caller"
+)
+
+// sqlDialect holds the differences between databases.
+type sqlDialect struct {
+	create   string // Table definition, %s is the quoted table name
+	textTime bool   // Store the time as RFC 3339 text
+	dollar   bool   // Use $1 style placeholders
+	multiRow bool   // Insert a batch with a single multi-row INSERT
+}
+
+var (
+	sqliteDialect = sqlDialect{create: `CREATE TABLE IF NOT EXISTS %s (
+	ts TEXT NOT NULL,
+	level TEXT NOT NULL,
+	logger TEXT,
+	caller TEXT,
+	message TEXT,
+	fields TEXT
+)`, textTime: true}
+
+	postgresDialect = sqlDialect{create: `CREATE TABLE IF NOT EXISTS %s (
+	ts TIMESTAMPTZ NOT NULL,
+	level TEXT NOT NULL,
+	logger TEXT,
+	caller TEXT,
+	message TEXT,
+	fields JSONB
+)`, dollar: true, multiRow: true}
+
+	// ClickHouse drivers batch the rows inserted with a prepared
+	// statement within a transaction.
+	clickhouseDialect = sqlDialect{create: `CREATE TABLE IF NOT EXISTS %s (
+	ts DateTime64(9),
+	level LowCardinality(String),
+	logger String,
+	caller String,
+	message String,
+	fields String
+) ENGINE = MergeTree ORDER BY ts`}
+)
+
+// sqlRow is a row of the log table.
+type sqlRow struct {
+	Time    time.Time `json:"ts"`
+	Level   string    `json:"level"`
+	Logger  string    `json:"logger"`
+	Caller  string    `json:"caller"`
+	Message string    `json:"message"`
+	Fields  string    `json:"fields"`
+}
+
+// SQLSink is a stream that inserts entries into a database table with the
+// columns ts, level, logger, caller, message, and fields, where fields is a
+// JSON object. Like OTLPExporter it is both the stream and its encoder, so it
+// is added with AddSink:
+//
+//	db, err := sql.Open("sqlite3", "/var/lib/app/logs.db")
+//	sink, err := logs.NewSQLiteSink(db, "logs")
+//	defer sink.Close()
+//	logr.SetStreams(os.Stderr)
+//	logr.AddSink(sink)
+//
+// Rows are inserted by a background goroutine, one transaction per batch of
+// BatchSize rows, when BatchSize rows are waiting and every second, and on
+// Flush or Close. If inserting fails, the rows are kept for the next attempt.
+// At most MaxQueue rows are kept; the oldest are dropped when the queue is
+// full. The logger and caller columns are
+// filled from the fields with the FieldLogger and FieldCaller keys, or else
+// from the logger name and the caller of the entry. The table name is quoted
+// as an identifier. The database driver is not a dependency of this package; register one, for
+// example github.com/mattn/go-sqlite3, in the program.
+type SQLSink struct {
+	BatchSize int // Rows per transaction
+	MaxQueue  int // Rows kept while the database is unavailable
+
+	ctx     context.Context
+	db      *sql.DB
+	table   string
+	dialect sqlDialect
+	dropped int
+
+	flushMu sync.Mutex // Serializes inserts so rows stay in order
+	mu      sync.Mutex
+	rows    []sqlRow
+	kick    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewSQLiteSink creates table in db if it does not exist and returns a sink
+// inserting into it.
+func NewSQLiteSink(db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(context.Background(), db, table, sqliteDialect)
+}
+
+// NewPostgresSink is like NewSQLiteSink for PostgreSQL. Each batch is
+// inserted with a single multi-row INSERT and the fields column is JSONB.
+func NewPostgresSink(db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(context.Background(), db, table, postgresDialect)
+}
+
+// NewClickHouseSink is like NewSQLiteSink for ClickHouse. The table uses the
+// MergeTree engine ordered by time.
+func NewClickHouseSink(db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(context.Background(), db, table, clickhouseDialect)
+}
+
+// NewSQLiteSinkContext is like NewSQLiteSink, but all statements are run
+// with ctx. Canceling ctx aborts the insert in flight and stops the flush
+// timer.
+func NewSQLiteSinkContext(ctx context.Context, db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(ctx, db, table, sqliteDialect)
+}
+
+// NewPostgresSinkContext is like NewPostgresSink with a context. See
+// NewSQLiteSinkContext.
+func NewPostgresSinkContext(ctx context.Context, db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(ctx, db, table, postgresDialect)
+}
+
+// NewClickHouseSinkContext is like NewClickHouseSink with a context. See
+// NewSQLiteSinkContext.
+func NewClickHouseSinkContext(ctx context.Context, db *sql.DB, table string) (*SQLSink, error) {
+	return newSQLSink(ctx, db, table, clickhouseDialect)
+}
+
+func newSQLSink(ctx context.Context, db *sql.DB, table string, dialect sqlDialect) (*SQLSink, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(dialect.create, quoteIdent(table))); err != nil {
+		return nil, err
+	}
+	s := &SQLSink{BatchSize: 100, MaxQueue: 10000, ctx: ctx, db: db, table: table,
+		dialect: dialect, kick: make(chan struct{}, 1), done: make(chan struct{})}
+	go s.flushEvery(time.Second)
+	return s, nil
+}
+
+func (s *SQLSink) flushEvery(d time.Duration) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.Flush()
+		case <-s.kick:
+			s.Flush()
+		case <-s.done:
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// quoteIdent quotes name as an SQL identifier, doubling any double quotes.
+func quoteIdent(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// Encode converts e into a row. It satisfies the Encoder interface.
+func (s *SQLSink) Encode(e *Entry) ([]byte, error) {
+	r := sqlRow{Time: e.Time, Level: levelName(e.Level), Logger: e.LoggerName,
+		Message: e.Message, Fields: "{}"}
+	if e.Caller.File != "" {
+		r.Caller = fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(e.Caller.File)),
+			filepath.Base(e.Caller.File), e.Caller.Line)
+	}
+	fields := make(map[string]interface{}, len(e.Fields))
+	for _, f := range e.Fields {
+		switch f.Key {
+		case FieldLogger:
+			r.Logger = fmt.Sprint(f.Value)
+		case FieldCaller:
+			r.Caller = fmt.Sprint(f.Value)
+		default:
+			fields[f.Key] = jsonValue(f.Value)
+		}
+	}
+	if len(fields) > 0 {
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		r.Fields = string(b)
+	}
+	return json.Marshal(r)
+}
+
+// Write queues a row created by Encode. A full batch is inserted by the
+// background goroutine, so Write does not wait for the database.
+func (s *SQLSink) Write(p []byte) (int, error) {
+	var r sqlRow
+	if err := json.Unmarshal(p, &r); err != nil {
+		return 0, errNotEncoded
+	}
+	s.mu.Lock()
+	s.rows = append(s.rows, r)
+	s.trim()
+	full := len(s.rows) >= s.BatchSize
+	s.mu.Unlock()
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// trim drops the oldest rows beyond MaxQueue. It must be called with s.mu
+// held.
+func (s *SQLSink) trim() {
+	if n := len(s.rows) - s.MaxQueue; s.MaxQueue > 0 && n > 0 {
+		s.rows = s.rows[n:]
+		s.dropped += n
+	}
+}
+
+// Dropped returns the number of rows dropped because the queue was full.
+func (s *SQLSink) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Flush inserts the queued rows, one transaction per batch. Rows that could
+// not be inserted are queued again.
+func (s *SQLSink) Flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	s.mu.Lock()
+	rows := s.rows
+	s.rows = nil
+	s.mu.Unlock()
+	for len(rows) > 0 {
+		n := len(rows)
+		if s.BatchSize > 0 && n > s.BatchSize {
+			n = s.BatchSize
+		}
+		if err := s.insert(rows[:n]); err != nil {
+			s.mu.Lock()
+			s.rows = append(rows, s.rows...)
+			s.trim()
+			s.mu.Unlock()
+			return err
+		}
+		rows = rows[n:]
+	}
+	return nil
+}
+
+// values returns the column values of r.
+func (s *SQLSink) values(r sqlRow) []interface{} {
+	var ts interface{} = r.Time
+	if s.dialect.textTime {
+		ts = r.Time.Format(time.RFC3339Nano)
+	}
+	return []interface{}{ts, r.Level, r.Logger, r.Caller, r.Message, r.Fields}
+}
+
+// maxInsertRows is the most rows in one multi-row INSERT, since PostgreSQL
+// takes at most 65535 parameters per statement and every row has six.
+const maxInsertRows = 65535 / 6
+
+// insert inserts rows in one transaction. Dialects using multi-row INSERTs
+// split the rows into statements of at most maxInsertRows.
+func (s *SQLSink) insert(rows []sqlRow) error {
+	tx, err := s.db.BeginTx(s.ctx, nil)
+	if err != nil {
+		return err
+	}
+	query := "INSERT INTO " + quoteIdent(s.table) + " (ts, level, logger, caller, message, fields) VALUES "
+	if s.dialect.multiRow {
+		for len(rows) > 0 {
+			n := len(rows)
+			if n > maxInsertRows {
+				n = maxInsertRows
+			}
+			var q strings.Builder
+			q.WriteString(query)
+			var args []interface{}
+			for i, r := range rows[:n] {
+				if i > 0 {
+					q.WriteString(", ")
+				}
+				q.WriteString(s.placeholders(len(args)))
+				args = append(args, s.values(r)...)
+			}
+			if _, err := tx.ExecContext(s.ctx, q.String(), args...); err != nil {
+				tx.Rollback()
+				return err
+			}
+			rows = rows[n:]
+		}
+		return tx.Commit()
+	}
+	stmt, err := tx.PrepareContext(s.ctx, query+s.placeholders(0))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, r := range rows {
+		if _, err := stmt.ExecContext(s.ctx, s.values(r)...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// placeholders returns the placeholders for one row, numbered from n+1 for
+// dialects using $1 style placeholders.
+func (s *SQLSink) placeholders(n int) string {
+	if !s.dialect.dollar {
+		return "(?, ?, ?, ?, ?, ?)"
+	}
+	return fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6)
+}
+
+// Close stops the flush timer and inserts the queued rows.
+func (s *SQLSink) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.Flush()
+}
@@ -0,0 +1,237 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordDriver is a database driver that records the statements executed and
+// the arguments inserted.
+type recordDriver struct {
+	mu      sync.Mutex
+	queries []string
+	rows    [][]driver.Value
+	commits int
+	fail    bool // Begin returns an error
+}
+
+func (d *recordDriver) Open(name string) (driver.Conn, error) { return &recordConn{d}, nil }
+
+type recordConn struct{ d *recordDriver }
+
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	c.d.queries = append(c.d.queries, query)
+	c.d.mu.Unlock()
+	return &recordStmt{c.d, query}, nil
+}
+
+func (c *recordConn) Close() error { return nil }
+func (c *recordConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	if c.d.fail {
+		return nil, errors.New("database unavailable")
+	}
+	return c, nil
+}
+func (c *recordConn) Rollback() error { return nil }
+
+func (c *recordConn) Commit() error {
+	c.d.mu.Lock()
+	c.d.commits++
+	c.d.mu.Unlock()
+	return nil
+}
+
+type recordStmt struct {
+	d     *recordDriver
+	query string
+}
+
+func (s *recordStmt) Close() error  { return nil }
+func (s *recordStmt) NumInput() int { return -1 }
+
+func (s *recordStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "INSERT") {
+		s.d.mu.Lock()
+		s.d.rows = append(s.d.rows, args)
+		s.d.mu.Unlock()
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, io.EOF }
+
+var recordDrivers int
+
+// openRecordDB returns a database using a new recordDriver.
+func openRecordDB(t *testing.T) (*sql.DB, *recordDriver) {
+	d := new(recordDriver)
+	recordDrivers++
+	name := fmt.Sprintf("record%d", recordDrivers)
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, d
+}
+
+func TestSQLiteSink(t *testing.T) {
+	db, d := openRecordDB(t)
+	sink, err := NewSQLiteSink(db, "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.BatchSize = 2
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+	logr.ErrorE().Str(FieldLogger, "api").Str(FieldCaller, "main.go:12").
+		Int("attempt", 2).Msg("request failed")
+	logr.Infoln("second")
+	logr.Infoln("third")
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !strings.HasPrefix(d.queries[0], `CREATE TABLE IF NOT EXISTS "logs"`) {
+		t.Errorf("Got first query %q", d.queries[0])
+	}
+	if len(d.rows) != 3 || d.commits != 2 {
+		t.Fatalf("Got %d rows in %d transactions; want: 3 in 2", len(d.rows), d.commits)
+	}
+	expect := []driver.Value{"error", "api", "main.go:12", "request failed", `{"attempt":2}`}
+	for i, v := range expect {
+		if d.rows[0][i+1] != v {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", d.rows[0][i+1], v)
+		}
+	}
+}
+
+func TestPostgresSink(t *testing.T) {
+	db, d := openRecordDB(t)
+	sink, err := NewPostgresSink(db, "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+	logr.Infoln("one")
+	logr.Infoln("two")
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	expect := `INSERT INTO "logs" (ts, level, logger, caller, message, fields) VALUES ` +
+		"($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12)"
+	if q := d.queries[len(d.queries)-1]; q != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", q, expect)
+	}
+	if len(d.rows) != 1 || len(d.rows[0]) != 12 {
+		t.Errorf("Got %d inserts; want: 1 with 12 values", len(d.rows))
+	} else if caller, _ := d.rows[0][3].(string); !strings.HasSuffix(caller, "/sql_test.go:131") {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", caller, ".../sql_test.go:131")
+	}
+}
+
+func TestPostgresSinkParamLimit(t *testing.T) {
+	db, d := openRecordDB(t)
+	sink, err := NewPostgresSink(db, "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	rows := make([]sqlRow, maxInsertRows+10)
+	if err := sink.insert(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	total := 0
+	for _, r := range d.rows {
+		if len(r) > 65535 {
+			t.Errorf("Got an insert with %d parameters; want: at most 65535", len(r))
+		}
+		total += len(r) / 6
+	}
+	if total != len(rows) || len(d.rows) != 2 {
+		t.Errorf("Got %d rows in %d inserts; want: %d in 2", total, len(d.rows), len(rows))
+	}
+}
+
+func TestSQLSinkQuoteTable(t *testing.T) {
+	db, d := openRecordDB(t)
+	sink, err := NewSQLiteSink(db, `logs"; DROP TABLE users; --`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+	logr.Infoln("one")
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	table := `"logs""; DROP TABLE users; --"`
+	for _, q := range d.queries {
+		if !strings.Contains(q, table) {
+			t.Errorf("\nGot:\t%q\nExpect:\ttable %s\n", q, table)
+		}
+	}
+}
+
+func TestSQLSinkQueue(t *testing.T) {
+	db, d := openRecordDB(t)
+	sink, err := NewClickHouseSink(db, "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	sink.BatchSize = 10
+	sink.MaxQueue = 3
+	logr := New(LEVEL_DEBUG, sink)
+	logr.SetStreamEncoder(sink, sink)
+
+	d.mu.Lock()
+	d.fail = true
+	d.mu.Unlock()
+	for i := 0; i < 5; i++ {
+		logr.Infof("entry %d\n", i)
+	}
+	if n := sink.Dropped(); n != 2 {
+		t.Errorf("\nGot:\t%d\nExpect:\t%d\n", n, 2)
+	}
+
+	d.mu.Lock()
+	d.fail = false
+	d.mu.Unlock()
+	if err := sink.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var got []string
+	for _, r := range d.rows {
+		got = append(got, r[4].(string))
+	}
+	if expect := "entry 2,entry 3,entry 4"; strings.Join(got, ",") != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", strings.Join(got, ","), expect)
+	}
+}
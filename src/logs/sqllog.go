@@ -0,0 +1,324 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLLogOptions configures the query logging of WrapDriver and
+// WrapConnector.
+type SQLLogOptions struct {
+	// Slow is the duration from which queries are logged at
+	// LEVEL_WARNING instead of LEVEL_DEBUG. Zero disables it.
+	Slow time.Duration
+
+	// Redact returns the value logged for an argument. If nil, RedactArg
+	// is used.
+	Redact func(arg driver.NamedValue) interface{}
+}
+
+// RedactArg is the default argument redaction. Strings and byte slices, which
+// may hold passwords or personal data, are replaced with "[redacted]"; numbers,
+// booleans, times, and nil are logged as they are.
+func RedactArg(arg driver.NamedValue) interface{} {
+	switch arg.Value.(type) {
+	case string, []byte:
+		return "[redacted]"
+	}
+	return arg.Value
+}
+
+// sqlLogger writes the query entries.
+type sqlLogger struct {
+	l    *Logger
+	opts SQLLogOptions
+}
+
+// log writes an entry for a query or exec that started at start. res is nil
+// for queries.
+func (s *sqlLogger) log(ctx context.Context, op, query string, args []driver.NamedValue,
+	start time.Time, res driver.Result, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+	d := time.Since(start)
+	var e *Event
+	msg := "sql " + op
+	if s.opts.Slow > 0 && d >= s.opts.Slow {
+		e = s.l.WarningE()
+		msg = "slow sql " + op
+	} else {
+		e = s.l.DebugE()
+	}
+	if e == nil {
+		return
+	}
+	e.Ctx(ctx).Str("query", strings.TrimSpace(query))
+	if len(args) > 0 {
+		redact := s.opts.Redact
+		if redact == nil {
+			redact = RedactArg
+		}
+		vals := make([]interface{}, len(args))
+		for i, a := range args {
+			vals[i] = redact(a)
+		}
+		e.Str("args", fmt.Sprint(vals))
+	}
+	if res != nil {
+		if n, rerr := res.RowsAffected(); rerr == nil {
+			e.Int64("rows", n)
+		}
+	}
+	e.Dur("duration", d)
+	if err != nil {
+		e.Err(err)
+	}
+	e.Msg(msg)
+}
+
+// namedValues converts the arguments of the deprecated driver interfaces.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nv
+}
+
+// plainValues converts arguments for the deprecated driver interfaces.
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	v := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, fmt.Errorf("logs: driver does not support named argument %q", a.Name)
+		}
+		v[i] = a.Value
+	}
+	return v, nil
+}
+
+// WrapDriver returns a driver logging the queries of d to the standard
+// logging object. See (*Logger).WrapDriver.
+func WrapDriver(d driver.Driver, opts SQLLogOptions) driver.Driver {
+	return std.WrapDriver(d, opts)
+}
+
+// WrapDriver returns a driver that logs every query and exec made through d
+// at LEVEL_DEBUG with the query, the redacted arguments, the rows affected,
+// and the duration as fields, and queries slower than opts.Slow at
+// LEVEL_WARNING. Register it under a new name:
+//
+//	sql.Register("sqlite3-logged", logr.WrapDriver(&sqlite3.SQLiteDriver{},
+//		logs.SQLLogOptions{Slow: 100 * time.Millisecond}))
+//	db, err := sql.Open("sqlite3-logged", "app.db")
+func (l *Logger) WrapDriver(d driver.Driver, opts SQLLogOptions) driver.Driver {
+	return &sqlLogDriver{d, &sqlLogger{l, opts}}
+}
+
+// WrapConnector is like WrapDriver for drivers opened with sql.OpenDB.
+func (l *Logger) WrapConnector(c driver.Connector, opts SQLLogOptions) driver.Connector {
+	return &sqlLogConnector{c, &sqlLogger{l, opts}}
+}
+
+type sqlLogDriver struct {
+	driver.Driver
+	log *sqlLogger
+}
+
+func (d *sqlLogDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlLogConn{c, d.log}, nil
+}
+
+type sqlLogConnector struct {
+	driver.Connector
+	log *sqlLogger
+}
+
+func (c *sqlLogConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlLogConn{conn, c.log}, nil
+}
+
+func (c *sqlLogConnector) Driver() driver.Driver {
+	return &sqlLogDriver{c.Connector.Driver(), c.log}
+}
+
+// sqlLogConn passes the optional interfaces of the wrapped connection
+// through, returning driver.ErrSkip where database/sql has a fallback.
+type sqlLogConn struct {
+	driver.Conn
+	log *sqlLogger
+}
+
+func (c *sqlLogConn) Prepare(query string) (driver.Stmt, error) {
+	s, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlLogStmt{s, query, c.log}, nil
+}
+
+func (c *sqlLogConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	pc, ok := c.Conn.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	s, err := pc.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlLogStmt{s, query, c.log}, nil
+}
+
+func (c *sqlLogConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	// The checks database/sql makes for drivers without BeginTx.
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *sqlLogConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if ec, ok := c.Conn.(driver.ExecerContext); ok {
+		res, err = ec.ExecContext(ctx, query, args)
+	} else if e, ok := c.Conn.(driver.Execer); ok {
+		var v []driver.Value
+		if v, err = plainValues(args); err == nil {
+			res, err = e.Exec(query, v)
+		}
+	} else {
+		return nil, driver.ErrSkip
+	}
+	c.log.log(ctx, "exec", query, args, start, res, err)
+	return res, err
+}
+
+func (c *sqlLogConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if qc, ok := c.Conn.(driver.QueryerContext); ok {
+		rows, err = qc.QueryContext(ctx, query, args)
+	} else if q, ok := c.Conn.(driver.Queryer); ok {
+		var v []driver.Value
+		if v, err = plainValues(args); err == nil {
+			rows, err = q.Query(query, v)
+		}
+	} else {
+		return nil, driver.ErrSkip
+	}
+	c.log.log(ctx, "query", query, args, start, nil, err)
+	return rows, err
+}
+
+func (c *sqlLogConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqlLogConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqlLogConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *sqlLogConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type sqlLogStmt struct {
+	driver.Stmt
+	query string
+	log   *sqlLogger
+}
+
+func (s *sqlLogStmt) Exec(args []driver.Value) (driver.Result, error) {
+	start := time.Now()
+	res, err := s.Stmt.Exec(args)
+	s.log.log(context.Background(), "exec", s.query, namedValues(args), start, res, err)
+	return res, err
+}
+
+func (s *sqlLogStmt) Query(args []driver.Value) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := s.Stmt.Query(args)
+	s.log.log(context.Background(), "query", s.query, namedValues(args), start, nil, err)
+	return rows, err
+}
+
+func (s *sqlLogStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = ec.ExecContext(ctx, args)
+	} else {
+		var v []driver.Value
+		if v, err = plainValues(args); err == nil {
+			res, err = s.Stmt.Exec(v)
+		}
+	}
+	s.log.log(ctx, "exec", s.query, args, start, res, err)
+	return res, err
+}
+
+func (s *sqlLogStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		var v []driver.Value
+		if v, err = plainValues(args); err == nil {
+			rows, err = s.Stmt.Query(v)
+		}
+	}
+	s.log.log(ctx, "query", s.query, args, start, nil, err)
+	return rows, err
+}
+
+func (s *sqlLogStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
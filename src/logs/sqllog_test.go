@@ -0,0 +1,82 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var sqlLogDrivers int
+
+// openLoggedDB returns a database using a recordDriver wrapped by l.
+func openLoggedDB(t *testing.T, l *Logger, opts SQLLogOptions) *sql.DB {
+	sqlLogDrivers++
+	name := fmt.Sprintf("logged%d", sqlLogDrivers)
+	sql.Register(name, l.WrapDriver(new(recordDriver), opts))
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestWrapDriver(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	l := New(LEVEL_DEBUG, &buf)
+	l.SetFlags(Llabel)
+	db := openLoggedDB(t, l, SQLLogOptions{})
+	defer db.Close()
+	if _, err := db.Exec("INSERT INTO users VALUES (?, ?)", 42, "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	expect := regexp.MustCompile(`^\[DEBUG\]    sql exec query="INSERT INTO users VALUES \(\?, \?\)" args="\[42 \[redacted\]\]" rows=1 duration=\S+
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%s\n", buf.String(), expect)
+	}
+}
+
+func TestWrapDriverSlow(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(LEVEL_WARNING, &buf)
+	l.SetFlags(Llabel)
+	db := openLoggedDB(t, l, SQLLogOptions{
+		Slow:   time.Nanosecond,
+		Redact: func(arg driver.NamedValue) interface{} { return arg.Value },
+	})
+	defer db.Close()
+	db.Query("SELECT * FROM users WHERE name = ?", "bob")
+	expect := regexp.MustCompile(`^\[WARNING\]  slow sql query query="SELECT \* FROM users WHERE name = \?" args=\[bob\] duration=\S+ error=EOF
+$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%s\n", buf.String(), expect)
+	}
+}
+
+func TestWrapDriverBeginTxOptions(t *testing.T) {
+	db := openLoggedDB(t, New(LEVEL_DEBUG, new(bytes.Buffer)), SQLLogOptions{})
+	defer db.Close()
+	for _, opts := range []*sql.TxOptions{
+		{Isolation: sql.LevelSerializable},
+		{ReadOnly: true},
+	} {
+		if tx, err := db.BeginTx(context.Background(), opts); err == nil {
+			tx.Rollback()
+			t.Errorf("BeginTx(%+v) = nil; want: error", *opts)
+		}
+	}
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.Rollback()
+}
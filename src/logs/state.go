@@ -0,0 +1,152 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "io"
+
+// State is the configuration of a logging object saved by Snapshot.
+type State struct {
+	l *Logger
+}
+
+// copyConfig copies the configuration of l to dst. Slices and maps are copied
+// so that later changes to one logger do not affect the other. Streams and
+// hooks themselves are shared. Runtime state such as the recent entries, open
+// scopes, and the audit sequence number is not copied.
+func (l *Logger) copyConfig(dst *Logger) {
+	dst.dateFormat = l.dateFormat
+	dst.location = l.location
+	dst.flags = l.flags
+	dst.level = l.level
+	dst.template = l.template
+	dst.seperator = l.seperator
+	dst.streams = append([]io.Writer(nil), l.streams...)
+	dst.indent = l.indent
+	dst.indentLevel = l.indentLevel
+	dst.tabStop = l.tabStop
+	dst.excludeIDs = append([]int(nil), l.excludeIDs...)
+	dst.excludeFuncNames = append([]string(nil), l.excludeFuncNames...)
+	dst.excludeStrings = append([]string(nil), l.excludeStrings...)
+	dst.filters = append([]*Filter(nil), l.filters...)
+	dst.fieldRoutes = append([]*fieldRoute(nil), l.fieldRoutes...)
+	dst.fieldProviders = append([]*fieldProvider(nil), l.fieldProviders...)
+	dst.includeCallers = append([]string(nil), l.includeCallers...)
+	dst.excludeCallers = append([]string(nil), l.excludeCallers...)
+	dst.filePrefixes = append([]string(nil), l.filePrefixes...)
+	dst.hyperlinkFormat = l.hyperlinkFormat
+	dst.labelWidth = l.labelWidth
+	dst.hexDumpMax = l.hexDumpMax
+	dst.maxEntrySize = l.maxEntrySize
+	dst.alerts = append([]*Alert(nil), l.alerts...)
+	dst.statsd = l.statsd
+	dst.crashDir = l.crashDir
+	dst.fatalHooks = append(([]func(Entry))(nil), l.fatalHooks...)
+	dst.fatalHookTimeout = l.fatalHookTimeout
+	dst.levelHandlers = append([]*levelHandler(nil), l.levelHandlers...)
+	dst.formatCheck = l.formatCheck
+	dst.verbosity = l.verbosity
+	dst.vmodules = append([]vmodule(nil), l.vmodules...)
+	dst.maxIds = l.maxIds
+	dst.mutedIds = copyIntMap(l.mutedIds)
+	dst.soloIds = copyIntMap(l.soloIds)
+	dst.idLevels = nil
+	if l.idLevels != nil {
+		dst.idLevels = make(map[int]level, len(l.idLevels))
+		for k, v := range l.idLevels {
+			dst.idLevels[k] = v
+		}
+	}
+	dst.progressInterval = l.progressInterval
+	dst.bannerWidth = l.bannerWidth
+	dst.errorHandler = l.errorHandler
+	dst.deadLetter = l.deadLetter
+	dst.closeTimeout = l.closeTimeout
+	dst.writeTimeout = l.writeTimeout
+	dst.accessLog = l.accessLog
+	dst.encoders = nil
+	if l.encoders != nil {
+		dst.encoders = make(map[io.Writer]Encoder, len(l.encoders))
+		for k, v := range l.encoders {
+			dst.encoders[k] = v
+		}
+	}
+	// Color modes, muted streams, routes, and global fields are replaced
+	// rather than modified, so they are shared.
+	dst.colorModes = l.colorModes
+	dst.muted = l.muted
+	dst.routes = l.routes
+	dst.globalFields = l.globalFields
+	dst.traceExtractor = l.traceExtractor
+	dst.spanEventHook = l.spanEventHook
+	dst.requestIDHeader = l.requestIDHeader
+	dst.name = l.name
+}
+
+func copyIntMap(m map[int]bool) map[int]bool {
+	if m == nil {
+		return nil
+	}
+	c := make(map[int]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// Snapshot returns the configuration of the standard logging object, for
+// example to undo changes made by a test or a library.
+func Snapshot() State { return std.Snapshot() }
+
+// Restore sets the configuration of the standard logging object to s.
+func Restore(s State) { std.Restore(s) }
+
+// RestoreOnCleanup saves the configuration of the standard logging object and
+// restores it when the test finishes. t is usually a *testing.T:
+//
+//	func TestQuiet(t *testing.T) {
+//		logs.RestoreOnCleanup(t)
+//		logs.SetQuiet(true)
+//		...
+//	}
+func RestoreOnCleanup(t interface{ Cleanup(func()) }) {
+	s := Snapshot()
+	t.Cleanup(func() { Restore(s) })
+}
+
+// Snapshot returns the configuration of l, including the level, flags,
+// template, and streams.
+func (l *Logger) Snapshot() State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s := State{l: new(Logger)}
+	l.copyConfig(s.l)
+	return s
+}
+
+// Restore sets the configuration of l to s. A State can be restored more than
+// once.
+func (l *Logger) Restore(s State) {
+	if s.l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s.l.copyConfig(l)
+}
+
+// Clone returns a new logging object with the configuration of l, for
+// example for a request handler that changes the flags or template without
+// racing with other users of l. The clone has its own lock, buffer, and id
+// map, which starts as a copy of the ids of l. Streams are shared.
+func (l *Logger) Clone() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c := &Logger{ids: make(map[string]int, len(l.ids)), lastId: l.lastId}
+	for k, v := range l.ids {
+		c.ids[k] = v
+	}
+	c.idOrder = append([]string(nil), l.idOrder...)
+	l.copyConfig(c)
+	return c
+}
@@ -0,0 +1,82 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	skipNoDebug(t)
+	var buf1, buf2 bytes.Buffer
+	logr := New(LEVEL_INFO, &buf1)
+	logr.SetFlags(Llabel)
+	s := logr.Snapshot()
+
+	logr.SetLevel(LEVEL_DEBUG)
+	logr.SetFlags(0)
+	logr.SetStreams(&buf2)
+	if err := logr.SetTemplate("{{.Text}}!"); err != nil {
+		t.Fatal(err)
+	}
+	logr.Debugln("changed")
+
+	logr.Restore(s)
+	logr.Debugln("filtered")
+	logr.Infoln("restored")
+	if expect := "changed\n!"; buf2.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf2.String(), expect)
+	}
+	if expect := "[INFO]     restored\n"; buf1.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf1.String(), expect)
+	}
+}
+
+func TestRestoreOnCleanup(t *testing.T) {
+	level := Level()
+	t.Run("change", func(t *testing.T) {
+		RestoreOnCleanup(t)
+		SetLevel(LEVEL_DEBUG)
+	})
+	if Level() != level {
+		t.Errorf("\nGot:\t%s\nExpect:\t%s\n", Level(), level)
+	}
+}
+
+func TestClone(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(Llabel)
+	c := logr.Clone()
+	c.SetFlags(0)
+	c.SetLevel(LEVEL_DEBUG)
+	c.Debugln("clone")
+	logr.Debugln("filtered")
+	logr.Infoln("parent")
+	if expect := "clone\n[INFO]     parent\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestCloneHandlers(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(0)
+	logr.SetFormatCheck(true)
+	var got []string
+	logr.OnLevel(LEVEL_ERROR, func(e Entry) { got = append(got, e.Message) })
+	c := logr.Clone()
+	// The format is built at run time so vet accepts the wrong verb.
+	verb := "d"
+	c.Errorf("%"+verb+" failed\n", "job")
+	expect := "%!d(string=job) failed\nbad format in previous entry: %!d(string=job)\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if len(got) != 1 || got[0] != "%!d(string=job) failed" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, []string{"%!d(string=job) failed"})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"net"
+)
+
+// StatsD sends a counter to a StatsD or Datadog agent for every entry, so log
+// volume can be graphed without a metrics library. With the default settings
+// an error entry increments "logs.error". If Name is set it is added to the
+// metric, as in "logs.api.error". With Tags set, the DogStatsD tag format is
+// used instead: "logs.entries:1|c|#level:error,logger:api".
+type StatsD struct {
+	Prefix string // Metric prefix, "logs" by default
+	Name   string // Name of the logging object
+	Tags   bool   // Use DogStatsD tags instead of metric names
+
+	conn net.Conn
+}
+
+// NewStatsD returns an emitter sending UDP packets to addr, for example
+// "127.0.0.1:8125".
+func NewStatsD(addr string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsD{Prefix: "logs", conn: conn}, nil
+}
+
+// metric returns the packet sent for an entry at lvl.
+func (s *StatsD) metric(lvl level) string {
+	name := levelName(lvl)
+	if s.Tags {
+		tags := "level:" + name
+		if s.Name != "" {
+			tags += ",logger:" + s.Name
+		}
+		return fmt.Sprintf("%s.entries:1|c|#%s", s.Prefix, tags)
+	}
+	if s.Name != "" {
+		return fmt.Sprintf("%s.%s.%s:1|c", s.Prefix, s.Name, name)
+	}
+	return fmt.Sprintf("%s.%s:1|c", s.Prefix, name)
+}
+
+// count sends the counter for an entry at lvl. Errors are ignored, metrics
+// are best effort.
+func (s *StatsD) count(lvl level) { s.conn.Write([]byte(s.metric(lvl))) }
+
+// Close closes the connection to the agent.
+func (s *StatsD) Close() error { return s.conn.Close() }
+
+// SetStatsD sets the StatsD emitter of the standard logging object.
+func SetStatsD(s *StatsD) { std.SetStatsD(s) }
+
+// SetStatsD counts every entry written by l with s. Entries filtered by the
+// logging level are not counted. Use nil to stop counting.
+func (l *Logger) SetStatsD(s *StatsD) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.statsd = s
+}
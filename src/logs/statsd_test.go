@@ -0,0 +1,56 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestStatsDMetric(t *testing.T) {
+	var tests = []struct {
+		s      StatsD
+		expect string
+	}{
+		{StatsD{Prefix: "logs"}, "logs.error:1|c"},
+		{StatsD{Prefix: "logs", Name: "api"}, "logs.api.error:1|c"},
+		{StatsD{Prefix: "app", Tags: true}, "app.entries:1|c|#level:error"},
+		{StatsD{Prefix: "logs", Name: "api", Tags: true},
+			"logs.entries:1|c|#level:error,logger:api"},
+	}
+	for _, test := range tests {
+		if got := test.s.metric(LEVEL_ERROR); got != test.expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, test.expect)
+		}
+	}
+}
+
+func TestSetStatsD(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer pc.Close()
+	s, err := NewStatsD(pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	logr := New(LEVEL_INFO, ioutil.Discard)
+	logr.SetStatsD(s)
+	logr.Debugln("not counted")
+	logr.Warningln("counted")
+
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	b := make([]byte, 512)
+	n, _, err := pc.ReadFrom(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "logs.warning:1|c"; string(b[:n]) != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", b[:n], expect)
+	}
+}
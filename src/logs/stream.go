@@ -0,0 +1,310 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StreamError records a failed write to one of the streams of a logging
+// object.
+type StreamError struct {
+	Stream  io.Writer // The stream that failed
+	Written int       // Number of bytes written to the stream
+	Err     error     // The error returned by the stream
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("logs: write to %T failed after %d bytes: %v",
+		e.Stream, e.Written, e.Err)
+}
+
+// WriteError is returned by Write when writing to one or more streams failed.
+type WriteError []*StreamError
+
+func (e WriteError) Error() string {
+	msgs := make([]string, len(e))
+	for i, se := range e {
+		msgs[i] = se.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// isStdStream reports whether w is os.Stdout or os.Stderr.
+func isStdStream(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && (f == os.Stdout || f == os.Stderr)
+}
+
+// turnstile lets goroutines through in the order they took their tickets.
+// Tickets are taken while the logger lock is held, so entries are written to
+// each stream in the order they were logged, while the writes themselves
+// happen without the logger lock.
+type turnstile struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	issued uint64 // Number of tickets taken
+	next   uint64 // Ticket allowed through
+}
+
+// take returns the next ticket.
+func (t *turnstile) take() ticket {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.issued++
+	return ticket{t, t.issued - 1}
+}
+
+// ticket is a place in the line of a turnstile.
+type ticket struct {
+	t *turnstile
+	n uint64
+}
+
+// wait blocks until it is the turn of k.
+func (k ticket) wait() {
+	t := k.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cond == nil {
+		t.cond = sync.NewCond(&t.mu)
+	}
+	for t.next != k.n {
+		t.cond.Wait()
+	}
+}
+
+// done lets the next ticket through.
+func (k ticket) done() {
+	t := k.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	if t.cond != nil {
+		t.cond.Broadcast()
+	}
+}
+
+// pass waits for the turn of k and lets the next ticket through, for tickets
+// that are not used.
+func (k ticket) pass() {
+	k.wait()
+	k.done()
+}
+
+// takeTurns returns a ticket for each of streams. It must be called with the
+// logger lock held. Streams that cannot be map keys share a turnstile.
+func (l *Logger) takeTurns(streams []io.Writer) []ticket {
+	if l.turns == nil {
+		l.turns = make(map[io.Writer]*turnstile)
+	}
+	tickets := make([]ticket, len(streams))
+	for i, w := range streams {
+		var key io.Writer
+		if comparable(w) {
+			key = w
+		}
+		t := l.turns[key]
+		if t == nil {
+			t = new(turnstile)
+			l.turns[key] = t
+		}
+		tickets[i] = t.take()
+	}
+	return tickets
+}
+
+// comparable reports whether w can be compared and used as a map key.
+func comparable(w io.Writer) bool { return w != nil && reflect.TypeOf(w).Comparable() }
+
+// sameStream reports whether a and b are the same stream. Streams that cannot
+// be compared are never the same.
+func sameStream(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && comparable(a) && a == b
+}
+
+// AddStream adds w to the streams of the standard logging object.
+func AddStream(w io.Writer) { std.AddStream(w) }
+
+// RemoveStream removes w from the streams of the standard logging object.
+func RemoveStream(w io.Writer) bool { return std.RemoveStream(w) }
+
+// ReplaceStream replaces old with new in the streams of the standard logging
+// object.
+func ReplaceStream(old, new io.Writer) bool { return std.ReplaceStream(old, new) }
+
+// AddStream adds w to the streams of the logging object, for example to
+// attach a debug file for a while:
+//
+//	logr.AddStream(f)
+//	defer logr.RemoveStream(f)
+//
+// The stream list is replaced rather than modified, so entries being written
+// are not affected.
+func (l *Logger) AddStream(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	streams := make([]io.Writer, len(l.streams), len(l.streams)+1)
+	copy(streams, l.streams)
+	l.streams = append(streams, w)
+}
+
+// RemoveStream removes every occurrence of w from the streams of the logging
+// object and reports whether it was found. Entries being written may still be
+// written to w after RemoveStream returns.
+func (l *Logger) RemoveStream(w io.Writer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	streams := make([]io.Writer, 0, len(l.streams))
+	for _, s := range l.streams {
+		if !sameStream(s, w) {
+			streams = append(streams, s)
+		}
+	}
+	if len(streams) == len(l.streams) {
+		return false
+	}
+	l.streams = streams
+	l.dropTurns(w)
+	return true
+}
+
+// ReplaceStream replaces every occurrence of old in the streams of the
+// logging object with new, keeping its position, and reports whether old was
+// found.
+func (l *Logger) ReplaceStream(old, new io.Writer) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	streams := make([]io.Writer, len(l.streams))
+	found := false
+	for i, s := range l.streams {
+		if sameStream(s, old) {
+			s = new
+			found = true
+		}
+		streams[i] = s
+	}
+	if !found {
+		return false
+	}
+	l.streams = streams
+	l.dropTurns(old)
+	return true
+}
+
+// hasStream reports whether w is one of the streams of l. It must be called
+// with the logger lock held.
+func (l *Logger) hasStream(w io.Writer) bool {
+	for _, s := range l.streams {
+		if sameStream(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropTurns forgets the turnstile of w if no entries are waiting for it. It
+// must be called with the logger lock held.
+func (l *Logger) dropTurns(w io.Writer) {
+	if !comparable(w) {
+		return
+	}
+	t := l.turns[w]
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	idle := t.next == t.issued
+	t.mu.Unlock()
+	if idle {
+		delete(l.turns, w)
+	}
+}
+
+// MuteStream stops writing to w for the standard logging object.
+func MuteStream(w io.Writer) { std.MuteStream(w) }
+
+// UnmuteStream reverses MuteStream for the standard logging object.
+func UnmuteStream(w io.Writer) { std.UnmuteStream(w) }
+
+// MuteStream stops writing to w until UnmuteStream is called, while the other
+// streams are written as usual, for example to silence the console while an
+// interactive prompt is shown:
+//
+//	logr.MuteStream(os.Stderr)
+//	answer := prompt()
+//	logr.UnmuteStream(os.Stderr)
+//
+// Output for a muted stream is dropped. Streams that cannot be compared
+// cannot be muted.
+func (l *Logger) MuteStream(w io.Writer) { l.setMuted(w, true) }
+
+// UnmuteStream writes to w again after MuteStream.
+func (l *Logger) UnmuteStream(w io.Writer) { l.setMuted(w, false) }
+
+// setMuted mutes or unmutes w.
+func (l *Logger) setMuted(w io.Writer, mute bool) {
+	if !comparable(w) {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The map is replaced rather than modified since output in progress
+	// may still use the old one.
+	muted := make(map[io.Writer]bool, len(l.muted)+1)
+	for k, v := range l.muted {
+		muted[k] = v
+	}
+	if mute {
+		muted[w] = true
+	} else {
+		delete(muted, w)
+	}
+	l.muted = muted
+}
+
+// SetStreamLevels routes entries of the standard logging object by level.
+// See (*Logger).SetStreamLevels.
+func SetStreamLevels(w io.Writer, levels ...level) { std.SetStreamLevels(w, levels...) }
+
+// SetStreamLevels writes only entries at one of levels to w, for example to
+// send warnings and errors to os.Stderr and other output to os.Stdout:
+//
+//	logr.SetStreams(os.Stdout, os.Stderr)
+//	logr.SetStreamLevels(os.Stdout, logs.LEVEL_INFO, logs.LEVEL_PRINT)
+//	logr.SetStreamLevels(os.Stderr, logs.LEVEL_WARNING, logs.LEVEL_ERROR,
+//		logs.LEVEL_CRITICAL)
+//
+// Output written with Write has the level LEVEL_PRINT. Without levels, w
+// receives all entries again. Streams that cannot be compared cannot be
+// routed.
+func (l *Logger) SetStreamLevels(w io.Writer, levels ...level) {
+	if !comparable(w) {
+		return
+	}
+	var mask uint
+	for _, lvl := range levels {
+		mask |= 1 << uint(lvl)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	routes := make(map[io.Writer]uint, len(l.routes)+1)
+	for k, v := range l.routes {
+		routes[k] = v
+	}
+	if mask == 0 {
+		delete(routes, w)
+	} else {
+		routes[w] = mask
+	}
+	l.routes = routes
+}
@@ -0,0 +1,206 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type failWriter struct{ n int }
+
+func (w failWriter) Write(p []byte) (int, error) {
+	return w.n, errors.New("disk full")
+}
+
+func TestWritePartialFailure(t *testing.T) {
+	var before, after bytes.Buffer
+	bad := failWriter{3}
+	logr := New(LEVEL_DEBUG, &before, bad, &after)
+	logr.SetFlags(0)
+	var handled []*StreamError
+	logr.SetErrorHandler(func(se *StreamError) { handled = append(handled, se) })
+
+	n, err := logr.Write([]byte("entry\n"))
+	if before.String() != "entry\n" || after.String() != "entry\n" {
+		t.Errorf("Streams got %q and %q; want: %q", before.String(),
+			after.String(), "entry\n")
+	}
+	werr, ok := err.(WriteError)
+	if n != 0 || !ok || len(werr) != 1 || werr[0].Stream != bad || werr[0].Written != 3 {
+		t.Errorf("Write() = %d, %v; want: 0, WriteError for failWriter", n, err)
+	}
+	if len(handled) != 1 || handled[0] != werr[0] {
+		t.Errorf("Error handler got %v; want: %v", handled, werr)
+	}
+}
+
+func TestSlowStreamReleasesLock(t *testing.T) {
+	w := &gateWriter{open: make(chan struct{})}
+	l := New(LEVEL_DEBUG, w)
+	l.SetFlags(0)
+	go l.Println("stuck")
+	time.Sleep(10 * time.Millisecond)
+
+	// The logger lock is not held while the stream blocks.
+	done := make(chan bool)
+	go func() {
+		l.SetErrorHandler(nil)
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("logger locked while writing to a stream")
+	}
+	close(w.open)
+}
+
+func TestStreamOrder(t *testing.T) {
+	var a, b syncBuffer
+	l := New(LEVEL_DEBUG, &a, &b)
+	l.SetFlags(0)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Printf("%d %d\n", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	// Every stream receives the entries in the same order.
+	if a.String() != b.String() {
+		t.Error("streams received entries in different orders")
+	}
+	next := make(map[int]int)
+	for _, line := range strings.Split(strings.TrimSpace(a.String()), "\n") {
+		var g, i int
+		if _, err := fmt.Sscanf(line, "%d %d", &g, &i); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		if i != next[g] {
+			t.Fatalf("\nGot:\t%d %d\nExpect:\t%d %d\n", g, i, g, next[g])
+		}
+		next[g]++
+	}
+	if len(next) != 8 {
+		t.Errorf("\nGot:\t%d goroutines\nExpect:\t8\n", len(next))
+	}
+}
+
+func TestAddRemoveStream(t *testing.T) {
+	var a, b, c bytes.Buffer
+	logr := New(LEVEL_DEBUG, &a)
+	logr.SetFlags(0)
+	logr.AddStream(&b)
+	logr.Println("1")
+	if !logr.ReplaceStream(&b, &c) {
+		t.Error("ReplaceStream did not find the stream")
+	}
+	logr.Println("2")
+	if !logr.RemoveStream(&a) {
+		t.Error("RemoveStream did not find the stream")
+	}
+	if logr.RemoveStream(&b) {
+		t.Error("RemoveStream found a replaced stream")
+	}
+	logr.Println("3")
+	for _, v := range []struct{ got, expect string }{
+		{a.String(), "1\n2\n"}, {b.String(), "1\n"}, {c.String(), "2\n3\n"},
+	} {
+		if v.got != v.expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", v.got, v.expect)
+		}
+	}
+}
+
+func TestAddStreamConcurrent(t *testing.T) {
+	var a syncBuffer
+	logr := New(LEVEL_DEBUG, &a)
+	logr.SetFlags(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				var b syncBuffer
+				logr.AddStream(&b)
+				logr.Println("x")
+				logr.RemoveStream(&b)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := strings.Count(a.String(), "x\n"); n != 200 {
+		t.Errorf("Got %d entries, expect 200", n)
+	}
+}
+
+func TestMuteStream(t *testing.T) {
+	var console, file bytes.Buffer
+	logr := New(LEVEL_DEBUG, &console, &file)
+	logr.SetFlags(0)
+	logr.Println("1")
+	logr.MuteStream(&console)
+	logr.Println("2")
+	logr.UnmuteStream(&console)
+	logr.Println("3")
+	if expect := "1\n3\n"; console.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", console.String(), expect)
+	}
+	if expect := "1\n2\n3\n"; file.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", file.String(), expect)
+	}
+}
+
+func TestStreamLevels(t *testing.T) {
+	skipNoDebug(t)
+	var out, errs bytes.Buffer
+	logr := New(LEVEL_DEBUG, &out, &errs)
+	logr.SetFlags(0)
+	logr.SetStreamLevels(&out, LEVEL_INFO, LEVEL_PRINT)
+	logr.SetStreamLevels(&errs, LEVEL_WARNING, LEVEL_ERROR)
+	logr.Debugln("debug")
+	logr.Infoln("info")
+	logr.Errorln("error")
+	logr.Write([]byte("raw\n"))
+	logr.SetStreamLevels(&errs)
+	logr.Debugln("all")
+	if expect := "info\nraw\n"; out.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", out.String(), expect)
+	}
+	if expect := "error\nall\n"; errs.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", errs.String(), expect)
+	}
+}
+
+func TestFprintDropsTurns(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	for i := 0; i < 100; i++ {
+		var w bytes.Buffer
+		logr.Fprint(logr.Flags(), LEVEL_INFO, 1, "explicit\n", &w)
+		if w.String() != "explicit\n" {
+			t.Fatalf("\nGot:\t%q\nExpect:\t%q\n", w.String(), "explicit\n")
+		}
+	}
+	logr.Infoln("own stream")
+	logr.Fprint(logr.Flags(), LEVEL_INFO, 1, "nil stream\n", nil)
+	logr.mu.Lock()
+	n := len(logr.turns)
+	logr.mu.Unlock()
+	if n != 1 {
+		t.Errorf("\nGot:\t%d turnstiles\nExpect:\t1\n", n)
+	}
+}
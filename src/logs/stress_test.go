@@ -0,0 +1,84 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentUse changes the level, flags, and streams of a logger while
+// other goroutines write to it. Run with -race.
+func TestConcurrentUse(t *testing.T) {
+	var a, b syncBuffer
+	logr := New(LEVEL_DEBUG, &a)
+	logr.SetFlags(Llabel | Lid | LshortFileName | LlineNumber)
+
+	const writers, count = 8, 200
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < count; j++ {
+				if j%2 == 0 {
+					logr.Debugln("stress")
+				} else {
+					logr.DebugE().Msg("stress")
+				}
+			}
+		}()
+	}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < count; j++ {
+			if j%2 == 0 {
+				logr.SetLevel(LEVEL_INFO)
+			} else {
+				logr.SetLevel(LEVEL_DEBUG)
+			}
+			_ = logr.Level()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for j := 0; j < count; j++ {
+			if j%2 == 0 {
+				logr.SetFlags(Llabel | LshortFileName)
+			} else {
+				logr.SetFlags(Llabel | Lid | LshortFileName | LlineNumber)
+			}
+			_ = logr.Flags()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for j := 0; j < count; j++ {
+			if j%2 == 0 {
+				logr.SetStreams(&b)
+			} else {
+				logr.SetStreams(&a, &b)
+			}
+			_ = logr.Streams()
+		}
+	}()
+	wg.Wait()
+
+	for _, out := range []string{a.String(), b.String()} {
+		if out == "" {
+			continue
+		}
+		if !strings.HasSuffix(out, "\n") {
+			t.Errorf("\nGot:\t%q\nExpect:\tcomplete lines\n", out)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+			if !strings.HasSuffix(line, "stress") {
+				t.Errorf("\nGot:\t%q\nExpect:\t%q\n", line, "... stress")
+				break
+			}
+		}
+	}
+}
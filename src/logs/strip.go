@@ -0,0 +1,66 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// partialEscape matches the start of an escape at the end of the output.
+var partialEscape = regexp.MustCompile("\x1b(\\[[\\d;]*|\\]8?)?$")
+
+// stripWriter removes ansi escapes from the output written to w.
+type stripWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	partial []byte // Incomplete escape held until the next write
+}
+
+// StripANSI returns a stream that removes color and hyperlink escapes from
+// everything written to it before writing it to w, so any destination, for
+// example a buffer, a pipe, or a socket, receives clean output regardless of
+// the Lcolor flag:
+//
+//	log.SetStreams(os.Stdout, logs.StripANSI(conn))
+//
+// Escapes split across writes are handled.
+func StripANSI(w io.Writer) io.Writer { return &stripWriter{w: w} }
+
+// Write writes p to the underlying writer without escapes. n is len(p) if
+// the write succeeded.
+func (s *stripWriter) Write(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := p
+	if len(s.partial) > 0 {
+		b = append(s.partial, p...)
+		s.partial = nil
+	}
+	if i := incompleteEscape(b); i >= 0 {
+		s.partial = append([]byte(nil), b[i:]...)
+		b = b[:i]
+	}
+	if _, err = s.w.Write(stripAnsiByte(b)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// incompleteEscape returns the index of an escape at the end of b that is not
+// complete yet, or -1.
+func incompleteEscape(b []byte) int {
+	if i := bytes.LastIndex(b, []byte("\x1b]8;")); i >= 0 {
+		rest := b[i+4:]
+		if !bytes.Contains(rest, []byte("\x1b\\")) && bytes.IndexByte(rest, '\a') < 0 {
+			return i
+		}
+	}
+	if loc := partialEscape.FindIndex(b); loc != nil {
+		return loc[0]
+	}
+	return -1
+}
@@ -0,0 +1,40 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aybabtme/rgbterm"
+)
+
+func TestStripANSI(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, StripANSI(&buf))
+	logr.SetFlags(Lcolor | Llabel | Lhyperlink | LshortFileName)
+	logr.Warningln(rgbterm.FgString("disk", 255, 0, 0), "full")
+	expect := "[WARNING]  strip_test.go: disk full\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestStripANSISplit(t *testing.T) {
+	var buf bytes.Buffer
+	w := StripANSI(&buf)
+	text := rgbterm.FgString("red", 255, 0, 0) + " " + hyperlink("file:///a", "a") + "\n"
+	for i := 0; i < len(text); i += 3 {
+		end := i + 3
+		if end > len(text) {
+			end = len(text)
+		}
+		if n, err := w.Write([]byte(text[i:end])); err != nil || n != end-i {
+			t.Fatalf("Write: %d, %v", n, err)
+		}
+	}
+	if expect := "red a\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
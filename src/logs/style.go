@@ -0,0 +1,108 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// colorReset ends colored text, like the sequence used by rgbterm.
+const colorReset = "\x1b[0;00m"
+
+// Text attributes that can be combined in a Style.
+const (
+	AttrBold = 1 << iota
+	AttrUnderline
+	AttrReverse
+)
+
+// attrCodes are the SGR parameters of the text attributes.
+var attrCodes = [...]int{1, 4, 7}
+
+// Style describes how text is colored. The zero value leaves text unchanged.
+type Style struct {
+	Fg    *[3]uint8 // Foreground RGB color, nil for the terminal default
+	Bg    *[3]uint8 // Background RGB color, nil for the terminal default
+	Attrs int       // Text attributes, for example AttrBold|AttrUnderline
+}
+
+// RGB returns a pointer to the color r, g, b, for use in a Style.
+func RGB(r, g, b uint8) *[3]uint8 { return &[3]uint8{r, g, b} }
+
+// Render returns text with the escape sequences of the style. Colors are
+// written as 24-bit colors, which the logging objects convert to the color
+// mode of each stream.
+func (s Style) Render(text string) string {
+	var params []string
+	if s.Fg != nil {
+		params = append(params, fmt.Sprintf("38;2;%d;%d;%d", s.Fg[0], s.Fg[1], s.Fg[2]))
+	}
+	if s.Bg != nil {
+		params = append(params, fmt.Sprintf("48;2;%d;%d;%d", s.Bg[0], s.Bg[1], s.Bg[2]))
+	}
+	if params != nil {
+		text = "\x1b[" + strings.Join(params, ";") + "m" + text + colorReset
+	}
+	return attrString(s.Attrs, text, params == nil)
+}
+
+// attrString prefixes text with the codes of attrs. reset appends the reset
+// sequence, which colored text already ends with.
+func attrString(attrs int, text string, reset bool) string {
+	if attrs == 0 {
+		return text
+	}
+	prefix := "\x1b["
+	for i, code := range attrCodes {
+		if attrs&(1<<uint(i)) != 0 {
+			if len(prefix) > 2 {
+				prefix += ";"
+			}
+			prefix += strconv.Itoa(code)
+		}
+	}
+	text = prefix + "m" + text
+	if reset {
+		text += "\x1b[0m"
+	}
+	return text
+}
+
+// LabelStyle returns the style of the label for lvl.
+func LabelStyle(lvl level) Style { return Labels[lvl].style }
+
+// SetLabelStyle sets the style of the label for lvl, for example to show
+// critical entries in bold white on red:
+//
+//	logs.SetLabelStyle(logs.LEVEL_CRITICAL, logs.Style{Fg: logs.RGB(255, 255, 255),
+//		Bg: logs.RGB(255, 0, 0), Attrs: logs.AttrBold})
+//
+// Label styles are shared by all logging objects.
+func SetLabelStyle(lvl level, s Style) { Labels[lvl].style = s }
+
+// styled returns text rendered by f with trailing newlines kept after the
+// reset sequence.
+func styled(text string, f func(string) string) string {
+	body := strings.TrimRight(text, "\n")
+	return f(body) + text[len(body):]
+}
+
+func init() {
+	// Template helpers, for example {{bold .Text}} or
+	// {{.FileName | fg 0 135 175}}.
+	funcMap["bold"] = func(s string) string {
+		return styled(s, Style{Attrs: AttrBold}.Render)
+	}
+	funcMap["underline"] = func(s string) string {
+		return styled(s, Style{Attrs: AttrUnderline}.Render)
+	}
+	funcMap["fg"] = func(r, g, b uint8, s string) string {
+		return styled(s, Style{Fg: RGB(r, g, b)}.Render)
+	}
+	funcMap["bg"] = func(r, g, b uint8, s string) string {
+		return styled(s, Style{Bg: RGB(r, g, b)}.Render)
+	}
+}
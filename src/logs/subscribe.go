@@ -0,0 +1,52 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+// subscriber receives the entries written by a logger.
+type subscriber struct {
+	level level
+	ch    chan Entry
+}
+
+// Subscribe returns a subscription to the entries of the standard logging
+// object. See (*Logger).Subscribe.
+func Subscribe(lvl level, buffer int) (<-chan Entry, func()) {
+	return std.Subscribe(lvl, buffer)
+}
+
+// Subscribe returns a channel receiving every entry at lvl or above written
+// by l, for forwarding entries to other processes, for example over gRPC. Up
+// to buffer entries are queued; entries are dropped while the queue is full so
+// a slow subscriber never blocks logging. The returned function ends the
+// subscription and closes the channel.
+func (l *Logger) Subscribe(lvl level, buffer int) (<-chan Entry, func()) {
+	s := &subscriber{level: lvl, ch: make(chan Entry, buffer)}
+	l.mu.Lock()
+	if l.subscribers == nil {
+		l.subscribers = make(map[*subscriber]bool)
+	}
+	l.subscribers[s] = true
+	l.mu.Unlock()
+	cancel := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.subscribers[s] {
+			delete(l.subscribers, s)
+			close(s.ch)
+		}
+	}
+	return s.ch, cancel
+}
+
+// send queues e if it is at or above the level of the subscriber. It must be
+// called with the logger lock held.
+func (s *subscriber) send(e *Entry) {
+	if e.Level < s.level && e.Level != LEVEL_PRINT {
+		return
+	}
+	select {
+	case s.ch <- *e:
+	default:
+	}
+}
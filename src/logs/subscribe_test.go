@@ -0,0 +1,27 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	logr := New(LEVEL_DEBUG, ioutil.Discard)
+	ch, cancel := logr.Subscribe(LEVEL_WARNING, 1)
+	logr.Infoln("filtered")
+	logr.Warningln("first")
+	logr.Errorln("dropped, the buffer is full")
+	e := <-ch
+	if e.Level != LEVEL_WARNING || e.Message != "first" {
+		t.Errorf("\nGot:\t%s %q\nExpect:\t%s %q\n", e.Level, e.Message, LEVEL_WARNING, "first")
+	}
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Errorf("Channel is open after cancel")
+	}
+	logr.Errorln("after cancel")
+}
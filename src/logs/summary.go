@@ -0,0 +1,117 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// summaryNumbers matches the numbers replaced in summarized messages.
+var summaryNumbers = regexp.MustCompile(`\d+`)
+
+// summaryKey groups entries in the summary.
+type summaryKey struct {
+	level   level
+	caller  string
+	message string
+}
+
+// summary counts the WARNING, ERROR, and CRITICAL entries of a logger.
+type summary struct {
+	counts map[summaryKey]int
+	order  []summaryKey // Keys in the order they were first seen
+}
+
+func newSummary() *summary { return &summary{counts: make(map[summaryKey]int)} }
+
+// add counts an entry. Numbers in text are replaced by "N" so that entries
+// created with the same format string are counted together.
+func (s *summary) add(lvl level, file string, line int, text string) {
+	k := summaryKey{lvl, fmt.Sprintf("%s:%d", filepath.Base(file), line),
+		summaryNumbers.ReplaceAllString(strings.TrimSpace(text), "N")}
+	if s.counts[k] == 0 {
+		s.order = append(s.order, k)
+	}
+	s.counts[k]++
+}
+
+// table returns the summary with the most frequent entries first.
+func (s *summary) table() string {
+	keys := append([]summaryKey(nil), s.order...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return s.counts[keys[i]] > s.counts[keys[j]]
+	})
+	var totals [LEVEL_PRINT]int
+	width := len("CALLER")
+	for _, k := range keys {
+		totals[k.level] += s.counts[k]
+		if len(k.caller) > width {
+			width = len(k.caller)
+		}
+	}
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Summary: %d warnings, %d errors, %d critical\n",
+		totals[LEVEL_WARNING], totals[LEVEL_ERROR], totals[LEVEL_CRITICAL])
+	if len(keys) == 0 {
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "%6s  %-8s  %-*s  %s\n", "COUNT", "LEVEL", width, "CALLER", "MESSAGE")
+	for _, k := range keys {
+		msg := k.message
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i] + " ..."
+		}
+		fmt.Fprintf(&buf, "%6d  %-8s  %-*s  %s\n", s.counts[k],
+			strings.ToUpper(levelName(k.level)), width, k.caller, msg)
+	}
+	return buf.String()
+}
+
+// EnableSummary starts counting the WARNING, ERROR, and CRITICAL entries of
+// the standard logging object. See (*Logger).EnableSummary for details.
+func EnableSummary() { std.EnableSummary() }
+
+// Summary writes the summary table of the standard logging object.
+func Summary() {
+	if table := std.summaryTable(); table != "" {
+		std.Fprint(std.Flags(), LEVEL_PRINT, 2, table, nil)
+	}
+}
+
+// EnableSummary starts counting WARNING, ERROR, and CRITICAL entries by call
+// site and message. The counts are written as a table by Summary, which is
+// useful at the end of batch jobs:
+//
+//	logs.EnableSummary()
+//	defer logs.Summary()
+//
+// The summary is also written by the Fatal functions before exiting.
+func (l *Logger) EnableSummary() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.summary == nil {
+		l.summary = newSummary()
+	}
+}
+
+// Summary writes the summary table regardless of the logging level. Nothing is
+// written if EnableSummary was not called.
+func (l *Logger) Summary() {
+	if table := l.summaryTable(); table != "" {
+		l.Fprint(l.Flags(), LEVEL_PRINT, 2, table, nil)
+	}
+}
+
+func (l *Logger) summaryTable() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.summary == nil {
+		return ""
+	}
+	return l.summary.table()
+}
@@ -0,0 +1,38 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_INFO, &buf)
+	logr.SetFlags(0)
+	logr.Summary()
+	if buf.Len() != 0 {
+		t.Errorf("Summary() without EnableSummary wrote %q", buf.String())
+	}
+
+	logr.EnableSummary()
+	_, _, line, _ := runtime.Caller(0)
+	for i := 0; i < 3; i++ {
+		logr.Errorf("retry %d failed\n", i)
+	}
+	logr.Warningln("disk almost full")
+	logr.Infoln("not counted")
+	buf.Reset()
+	logr.Summary()
+	expect := "Summary: 1 warnings, 3 errors, 0 critical\n" +
+		" COUNT  LEVEL     CALLER              MESSAGE\n" +
+		fmt.Sprintf("     3  ERROR     summary_test.go:%d  retry N failed\n", line+2) +
+		fmt.Sprintf("     1  WARNING   summary_test.go:%d  disk almost full\n", line+4)
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import "io"
+
+// SuspendOutput holds the output of the standard logging object. See
+// (*Logger).SuspendOutput.
+func SuspendOutput() { std.SuspendOutput() }
+
+// ResumeOutput writes the held output of the standard logging object.
+func ResumeOutput() { std.ResumeOutput() }
+
+// SuspendOutput holds the output of the logging object until ResumeOutput is
+// called, so command line programs can draw prompts, progress bars, or full
+// screen interfaces without entries written in between:
+//
+//	logr.SuspendOutput()
+//	answer := prompt()
+//	logr.ResumeOutput()
+//
+// Entries are kept in memory while the output is suspended, and hooks and
+// subscribers receive them as usual. Close and the Fatal functions resume
+// the output. Calling SuspendOutput again has no effect.
+func (l *Logger) SuspendOutput() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held == nil {
+		l.held = []asyncItem{}
+	}
+}
+
+// ResumeOutput writes the output held since SuspendOutput, and writes new
+// output directly again. The held output is written to each stream before any
+// output logged after ResumeOutput.
+func (l *Logger) ResumeOutput() {
+	l.mu.Lock()
+	items := l.held
+	l.held = nil
+	if len(items) == 0 {
+		l.mu.Unlock()
+		return
+	}
+	var streams []io.Writer
+	for _, it := range items {
+		for _, w := range it.streams {
+			found := false
+			for _, s := range streams {
+				if sameStream(s, w) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				streams = append(streams, w)
+			}
+		}
+	}
+	tickets := l.takeTurns(streams)
+	l.mu.Unlock()
+
+	for i, w := range streams {
+		tickets[i].wait()
+		for _, it := range items {
+			for _, s := range it.streams {
+				if sameStream(s, w) {
+					it.cfg.output([]io.Writer{w}, nil, it.p, it.e)
+					break
+				}
+			}
+		}
+		tickets[i].done()
+	}
+}
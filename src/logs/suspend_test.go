@@ -0,0 +1,42 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSuspendOutput(t *testing.T) {
+	var a, b bytes.Buffer
+	logr := New(LEVEL_DEBUG, &a, &b)
+	logr.SetFlags(0)
+	logr.Println("1")
+	logr.SuspendOutput()
+	logr.Println("2")
+	logr.Write([]byte("3\n"))
+	if expect := "1\n"; a.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", a.String(), expect)
+	}
+	logr.ResumeOutput()
+	logr.Println("4")
+	expect := "1\n2\n3\n4\n"
+	if a.String() != expect || b.String() != expect {
+		t.Errorf("\nGot:\t%q\n\t%q\nExpect:\t%q\n", a.String(), b.String(), expect)
+	}
+}
+
+func TestSuspendOutputClose(t *testing.T) {
+	var a bytes.Buffer
+	logr := New(LEVEL_DEBUG, &a)
+	logr.SetFlags(0)
+	logr.SuspendOutput()
+	logr.Println("held")
+	if err := logr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "held\n"; a.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", a.String(), expect)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// tailBuffer is the number of entries buffered per tail client. Entries are
+// dropped for clients that fall further behind.
+const tailBuffer = 256
+
+// tailClient receives the entries written by a logger.
+type tailClient struct {
+	level level
+	match *regexp.Regexp
+	ch    chan string
+}
+
+// send queues text for the client if it passes the filters. It must be called
+// with the logger lock held.
+func (c *tailClient) send(lvl level, text string) {
+	if lvl < c.level && lvl != LEVEL_PRINT {
+		return
+	}
+	if c.match != nil && !c.match.MatchString(text) {
+		return
+	}
+	select {
+	case c.ch <- text:
+	default:
+	}
+}
+
+// TailHandler returns a live tail handler for the standard logging object.
+// See (*Logger).TailHandler.
+func TailHandler() http.Handler { return std.TailHandler() }
+
+// TailHandler returns a handler that streams the entries written by l to
+// connected clients as server-sent events, so logs can be followed from a
+// browser with EventSource or from a terminal with "curl -N". Each event
+// contains one entry without color escapes.
+//
+// The query parameters "level" and "match" filter the entries of a client
+// by minimum level and regular expression:
+//
+//	http.Handle("/debug/logs", logr.TailHandler())
+//	// curl -N 'localhost:8080/debug/logs?level=warning&match=db'
+//
+// Entries are dropped for clients that do not keep up. WebSocket is not
+// supported, server-sent events need no extra dependency.
+func (l *Logger) TailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+		c := &tailClient{level: LEVEL_DEBUG, ch: make(chan string, tailBuffer)}
+		if lvl := r.FormValue("level"); lvl != "" {
+			c.level = LevelFromString(lvl)
+		}
+		if expr := r.FormValue("match"); expr != "" {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			c.match = re
+		}
+
+		l.mu.Lock()
+		if l.tails == nil {
+			l.tails = make(map[*tailClient]bool)
+		}
+		l.tails[c] = true
+		l.mu.Unlock()
+		defer func() {
+			l.mu.Lock()
+			delete(l.tails, c)
+			l.mu.Unlock()
+		}()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+		for {
+			select {
+			case text := <-c.ch:
+				text = strings.TrimRight(text, "\n")
+				for _, line := range strings.Split(text, "\n") {
+					fmt.Fprintf(w, "data: %s\n", line)
+				}
+				fmt.Fprint(w, "\n")
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTailHandler(t *testing.T) {
+	logr := New(LEVEL_DEBUG, ioutil.Discard)
+	logr.SetFlags(Llabel)
+	srv := httptest.NewServer(logr.TailHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?level=warning&match=disk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", ct, "text/event-stream")
+	}
+
+	// Wait for the client to be registered.
+	for i := 0; ; i++ {
+		logr.mu.Lock()
+		n := len(logr.tails)
+		logr.mu.Unlock()
+		if n > 0 {
+			break
+		} else if i > 500 {
+			t.Fatal("Client was not registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	logr.Warningln("network slow")
+	logr.Infoln("disk info")
+	logr.Errorln("disk full")
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "data: [ERROR]    disk full\n"; line != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", line, expect)
+	}
+}
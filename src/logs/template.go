@@ -6,12 +6,20 @@ package logs
 import "text/template"
 
 // funcMap contains the available functions to the log format template.
+//
+// dump returns a multi-line, type annotated representation of its argument.
 var (
-	funcMap = template.FuncMap{}
-	logFmt  = "{{if .Date}}{{.Date}} {{end}}" +
+	funcMap = template.FuncMap{
+		"dump": dump,
+	}
+	logFmt = "{{if .Seq}}#{{.Seq}} {{end}}" +
+		"{{if .Date}}{{.Date}} {{end}}" +
+		"{{if .Pid}}{{.User}}[{{.Pid}}:{{.PPid}}] {{end}}" +
+		"{{if .EntryID}}{{.EntryID}} {{end}}" +
 		"{{if .LogLabel}}{{.LogLabel}} {{end}}" +
 		"{{if .Seperator}}{{.Seperator}} {{end}}" +
 		"{{if .Id}}{{.Id}} {{end}}" +
+		"{{if .RequestID}}{{.RequestID}} {{end}}" +
 		"{{if .Indent}}{{.Indent}}{{end}}" +
 		"{{if .FileName}}{{.FileName}}: {{end}}" +
 		"{{if .FunctionName}}{{.FunctionName}}: {{end}}" +
@@ -23,11 +31,22 @@ var (
 type format struct {
 	Seperator    string
 	LogLabel     string
+	Level        string // The level name without color, for example "ERROR"
+	LevelNum     int    // The level as a number, LEVEL_DEBUG is 0
 	Date         string
+	Unix         int64 // The time of the entry in seconds since the Unix epoch
+	UnixMilli    int64 // The time in milliseconds since the Unix epoch
+	UnixNano     int64 // The time in nanoseconds since the Unix epoch
 	FileName     string
 	FunctionName string
 	LineNumber   int
 	Indent       string
 	Id           string
+	RequestID    string
+	Seq          uint64
+	EntryID      string // The ULID of the entry with Lulid
+	Pid          int    // The process id with Lprocess, otherwise zero
+	PPid         int    // The parent process id with Lprocess
+	User         string // The user name with Lprocess
 	Text         string
 }
@@ -0,0 +1,50 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestTemplateCopy(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Llabel)
+	logr.SetTemplate("{{.LogLabel}}{{.Text}}")
+	tmpl := logr.Template()
+	template.Must(tmpl.Parse("{{.Level}}: {{.Text}}"))
+	logr.Infoln("unchanged")
+	if err := logr.SetParsedTemplate(tmpl); err != nil {
+		t.Fatal(err)
+	}
+	template.Must(tmpl.Parse("{{.Text}}"))
+	logr.Infoln("changed")
+	expect := "[INFO]    unchanged\nINFO: changed\n"
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+func TestTemplateUnix(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.SetTemplate("{{.Unix}} {{.UnixMilli}} {{.UnixNano}} {{.Text}}")
+	before := time.Now().UnixNano()
+	logr.Infoln("tick")
+	after := time.Now().UnixNano()
+
+	var sec, ms, ns int64
+	var text string
+	if _, err := fmt.Sscan(buf.String(), &sec, &ms, &ns, &text); err != nil {
+		t.Fatalf("%q: %v", buf.String(), err)
+	}
+	if ns < before || ns > after || ms != ns/1e6 || sec != ns/1e9 || text != "tick" {
+		t.Errorf("\nGot:\t%q\nExpect:\tone time between %d and %d\n", buf.String(), before, after)
+	}
+}
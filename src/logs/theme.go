@@ -0,0 +1,94 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Theme holds the label styles of the levels LEVEL_DEBUG to LEVEL_CRITICAL.
+// Single levels can be overridden before the theme is set:
+//
+//	t := logs.ColorblindTheme
+//	t[logs.LEVEL_INFO] = logs.Style{Fg: logs.RGB(0, 158, 115)}
+//	logs.SetTheme(t)
+type Theme [LEVEL_PRINT]Style
+
+var (
+	// DefaultTheme is the theme used unless another one is set.
+	DefaultTheme = Theme{
+		LEVEL_DEBUG:    {Fg: RGB(255, 255, 255)}, // White
+		LEVEL_INFO:     {Fg: RGB(0, 215, 95)},    // Green
+		LEVEL_WARNING:  {Fg: RGB(255, 255, 135)}, // Yellow
+		LEVEL_ERROR:    {Fg: RGB(255, 99, 0)},    // Orange
+		LEVEL_CRITICAL: {Fg: RGB(255, 0, 0)},     // Red
+	}
+
+	// ColorblindTheme uses colors of the Okabe-Ito palette, which can be
+	// told apart with the common forms of color blindness. The levels also
+	// differ in brightness and attributes.
+	ColorblindTheme = Theme{
+		LEVEL_DEBUG:    {Fg: RGB(170, 170, 170)},                               // Gray
+		LEVEL_INFO:     {Fg: RGB(86, 180, 233)},                                // Sky blue
+		LEVEL_WARNING:  {Fg: RGB(240, 228, 66)},                                // Yellow
+		LEVEL_ERROR:    {Fg: RGB(230, 159, 0), Attrs: AttrBold},                // Orange
+		LEVEL_CRITICAL: {Fg: RGB(213, 94, 0), Attrs: AttrBold | AttrUnderline}, // Vermillion
+	}
+
+	// HighContrastTheme uses bright colors, and backgrounds for the levels
+	// that need attention.
+	HighContrastTheme = Theme{
+		LEVEL_DEBUG:    {Fg: RGB(255, 255, 255)},
+		LEVEL_INFO:     {Fg: RGB(0, 255, 255), Attrs: AttrBold},
+		LEVEL_WARNING:  {Fg: RGB(0, 0, 0), Bg: RGB(255, 255, 0), Attrs: AttrBold},
+		LEVEL_ERROR:    {Fg: RGB(255, 255, 255), Bg: RGB(205, 0, 0), Attrs: AttrBold},
+		LEVEL_CRITICAL: {Fg: RGB(255, 255, 255), Bg: RGB(255, 0, 255), Attrs: AttrBold | AttrUnderline},
+	}
+)
+
+// themes are the themes selectable with SetThemeNamed.
+var themes = map[string]*Theme{
+	"default":       &DefaultTheme,
+	"colorblind":    &ColorblindTheme,
+	"high-contrast": &HighContrastTheme,
+}
+
+// CurrentTheme returns the label styles in use.
+func CurrentTheme() Theme {
+	var t Theme
+	for i := range t {
+		t[i] = Labels[i].style
+	}
+	return t
+}
+
+// SetTheme sets the label styles of all levels. Like the styles set with
+// SetLabelStyle, the theme is shared by all logging objects.
+func SetTheme(t Theme) {
+	for i, s := range t {
+		Labels[i].style = s
+	}
+}
+
+// ThemeNames returns the names accepted by SetThemeNamed.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetThemeNamed sets a preset theme: "default", "colorblind", or
+// "high-contrast".
+func SetThemeNamed(name string) error {
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("logs: unknown theme %q", name)
+	}
+	SetTheme(*t)
+	return nil
+}
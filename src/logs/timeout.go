@@ -0,0 +1,110 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrWriteTimeout is the error of a stream write that did not complete
+// within the write timeout.
+var ErrWriteTimeout = errors.New("logs: write timed out")
+
+// deadliner is implemented by net.Conn and by os.File for pipes and sockets.
+type deadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// hungWrites tracks streams with a timed out write still in progress.
+type hungWrites struct {
+	mu      sync.Mutex
+	streams map[io.Writer]bool
+}
+
+// busy reports whether a timed out write to w has not returned yet.
+func (h *hungWrites) busy(w io.Writer) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.streams[w]
+}
+
+func (h *hungWrites) set(w io.Writer, hung bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !hung {
+		delete(h.streams, w)
+		return
+	}
+	if h.streams == nil {
+		h.streams = make(map[io.Writer]bool)
+	}
+	h.streams[w] = true
+}
+
+// timedWrite writes p to w, giving up after d. Streams supporting write
+// deadlines use them. Otherwise the write is done in a separate goroutine,
+// and later writes to the stream fail with ErrWriteTimeout without being
+// attempted until that write returns, so a hung stream costs at most one
+// goroutine.
+func (h *hungWrites) timedWrite(w io.Writer, p []byte, d time.Duration) (int, error) {
+	if dl, ok := w.(deadliner); ok && dl.SetWriteDeadline(time.Now().Add(d)) == nil {
+		n, err := w.Write(p)
+		dl.SetWriteDeadline(time.Time{})
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			err = ErrWriteTimeout
+		}
+		return n, err
+	}
+	// Streams that cannot be map keys are written without a timeout.
+	if !reflect.TypeOf(w).Comparable() {
+		return w.Write(p)
+	}
+	if h.busy(w) {
+		return 0, ErrWriteTimeout
+	}
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := w.Write(p)
+		done <- result{n, err}
+	}()
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-t.C:
+	}
+	h.set(w, true)
+	go func() {
+		<-done
+		h.set(w, false)
+	}()
+	return 0, ErrWriteTimeout
+}
+
+// SetWriteTimeout sets the write timeout of the standard logging object. See
+// (*Logger).SetWriteTimeout for details.
+func SetWriteTimeout(d time.Duration) { std.SetWriteTimeout(d) }
+
+// SetWriteTimeout limits how long a write to a single stream may take, so a
+// hung NFS mount or TCP sink does not block every caller waiting for the
+// logging object. A write that times out fails with ErrWriteTimeout and is
+// passed to the error handler. Streams with a SetWriteDeadline method, such
+// as network connections, use a write deadline; for other streams the write
+// continues in the background and the stream is skipped until it returns.
+// Zero, the default, disables the timeout.
+func (l *Logger) SetWriteTimeout(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writeTimeout = d
+}
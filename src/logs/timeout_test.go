@@ -0,0 +1,57 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteTimeout(t *testing.T) {
+	hung := &gateWriter{open: make(chan struct{})}
+	var ok syncBuffer
+	l := New(LEVEL_DEBUG, hung, &ok)
+	l.SetFlags(0)
+	l.SetWriteTimeout(20 * time.Millisecond)
+	var errs []*StreamError
+	l.SetErrorHandler(func(se *StreamError) { errs = append(errs, se) })
+
+	start := time.Now()
+	l.Println("one")
+	l.Println("two")
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("writes took %v", d)
+	}
+	if len(errs) != 2 || errs[0].Err != ErrWriteTimeout || errs[1].Err != ErrWriteTimeout {
+		t.Errorf("\nGot:\t%v\nExpect:\ttwo write timeouts\n", errs)
+	}
+	if expect := "one\ntwo\n"; ok.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", ok.String(), expect)
+	}
+
+	// The stream is used again once the hung write returns.
+	close(hung.open)
+	for i := 0; i < 100 && l.hung.busy(hung); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	l.Println("three")
+	if expect := "one\nthree\n"; hung.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", hung.String(), expect)
+	}
+}
+
+func TestWriteTimeoutDeadline(t *testing.T) {
+	// Nobody reads from the other end, so writes block once the pipe is full.
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	l := New(LEVEL_DEBUG, c1)
+	l.SetWriteTimeout(20 * time.Millisecond)
+	if _, err := l.Write([]byte("hello\n")); err == nil {
+		t.Error("expected a timeout")
+	} else if we, ok := err.(WriteError); !ok || we[0].Err != ErrWriteTimeout {
+		t.Errorf("\nGot:\t%v\nExpect:\t%v\n", err, ErrWriteTimeout)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timer measures the time taken by an operation. Timers are created with
+// Start().
+type Timer struct {
+	logr  *Logger
+	msg   string
+	start time.Time
+}
+
+// Start returns a timer for the operation described by msg using the
+// standard logging object. Nothing is written until Stop is called.
+func Start(msg string) *Timer { return std.Start(msg) }
+
+// Start returns a timer for the operation described by msg. Nothing is
+// written until Stop is called.
+func (l *Logger) Start(msg string) *Timer {
+	return &Timer{logr: l, msg: msg, start: time.Now()}
+}
+
+// Stop writes the message of the timer followed by the elapsed time at the
+// LEVEL_INFO level, for example "rebuild index elapsed=1.52s". The elapsed
+// duration uses the time.Duration format and can be read back with
+// time.ParseDuration. The elapsed duration is returned.
+func (t *Timer) Stop() time.Duration {
+	d := time.Since(t.start)
+	t.logr.Fprint(t.logr.Flags(), LEVEL_INFO, 2,
+		fmt.Sprintf("%s elapsed=%s\n", t.msg, d), nil)
+	return d
+}
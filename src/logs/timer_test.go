@@ -0,0 +1,29 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(LfunctionName)
+	timer := logr.Start("rebuild index")
+	time.Sleep(time.Millisecond)
+	d := timer.Stop()
+	expect := fmt.Sprintf("TestTimer: rebuild index elapsed=%s\n", d)
+	if buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	field := strings.TrimSpace(buf.String()[strings.Index(buf.String(), "=")+1:])
+	if pd, err := time.ParseDuration(field); err != nil || pd != d {
+		t.Errorf("ParseDuration(%q) = %s, %v; want: %s, nil", field, pd, err, d)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Configure12Factor configures the standard logging object from the
+// environment. See (*Logger).Configure12Factor.
+func Configure12Factor() error { return std.Configure12Factor() }
+
+// Configure12Factor configures the logging object from environment variables,
+// as usual for twelve-factor apps:
+//
+//	LOG_LEVEL   debug, info, warning or warn, error, or critical
+//	LOG_FORMAT  text for the template, json for JSONEncoder, or auto to
+//	            choose by stream with SetAutoFormat
+//	LOG_COLOR   true or always, false or never, or auto for color on
+//	            terminals only
+//
+// Settings of unset variables are left alone. Invalid values are reported in
+// the returned error and ignored; the other variables are still applied.
+func (l *Logger) Configure12Factor() error {
+	var bad []string
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		name := strings.ToLower(v)
+		if name == "warn" {
+			name = "warning"
+		}
+		if lvl := LevelFromString(name); lvl != LEVEL_PRINT {
+			l.SetLevel(lvl)
+		} else {
+			bad = append(bad, "LOG_LEVEL="+v)
+		}
+	}
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		switch strings.ToLower(v) {
+		case "text":
+			for _, w := range l.Streams() {
+				l.SetStreamEncoder(w, nil)
+			}
+		case "json":
+			for _, w := range l.Streams() {
+				l.SetStreamEncoder(w, JSONEncoder{})
+			}
+		case "auto":
+			l.SetAutoFormat()
+		default:
+			bad = append(bad, "LOG_FORMAT="+v)
+		}
+	}
+	if v := os.Getenv("LOG_COLOR"); v != "" {
+		on, err := strconv.ParseBool(v)
+		switch {
+		case strings.EqualFold(v, "auto"):
+			l.SetFlags(l.Flags() | Lcolor)
+			for _, w := range l.Streams() {
+				if !isTerminal(w) {
+					l.SetColorMode(w, ColorNone)
+				}
+			}
+		case strings.EqualFold(v, "always") || err == nil && on:
+			l.SetFlags((l.Flags() | Lcolor) &^ LnoFileAnsi)
+		case strings.EqualFold(v, "never") || err == nil:
+			l.SetFlags(l.Flags() &^ Lcolor)
+		default:
+			bad = append(bad, "LOG_COLOR="+v)
+		}
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("logs: invalid environment: %s", strings.Join(bad, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// setenv sets the environment variables in vars and returns a function
+// restoring them.
+func setenv(vars map[string]string) func() {
+	old := make(map[string]string)
+	for k, v := range vars {
+		old[k] = os.Getenv(k)
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range old {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+func TestConfigure12Factor(t *testing.T) {
+	defer setenv(map[string]string{"LOG_LEVEL": "warn", "LOG_FORMAT": "json",
+		"LOG_COLOR": "false"})()
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	if err := logr.Configure12Factor(); err != nil {
+		t.Fatal(err)
+	}
+	logr.Infoln("not shown")
+	logr.Warningln("shown")
+	if logr.Flags()&Lcolor != 0 {
+		t.Error("Lcolor still set")
+	}
+	if !strings.HasPrefix(buf.String(), `{"level":"warning","msg":"shown",`) {
+		t.Errorf("\nGot:\t%q\n", buf.String())
+	}
+}
+
+func TestConfigure12FactorInvalid(t *testing.T) {
+	defer setenv(map[string]string{"LOG_LEVEL": "loud", "LOG_FORMAT": "",
+		"LOG_COLOR": "always"})()
+	logr := New(LEVEL_INFO)
+	err := logr.Configure12Factor()
+	if err == nil || err.Error() != "logs: invalid environment: LOG_LEVEL=loud" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if logr.Level() != LEVEL_INFO || logr.Flags()&Lcolor == 0 {
+		t.Errorf("Level: %v, flags: %b", logr.Level(), logr.Flags())
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// crockford is the base32 alphabet of ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidState makes the ULIDs of one millisecond increase monotonically, so
+// that they sort in the order they were created.
+var ulidState struct {
+	sync.Mutex
+	ms      uint64
+	entropy [10]byte
+}
+
+// newULID returns a ULID for t: 48 bits of milliseconds since the Unix epoch
+// followed by 80 random bits, encoded as 26 characters. Within the same
+// millisecond the random part of the previous id is incremented.
+func newULID(t time.Time) string {
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	s := &ulidState
+	s.Lock()
+	if ms > s.ms {
+		s.ms = ms
+		rand.Read(s.entropy[:])
+	} else {
+		// Same millisecond, or the clock went back: keep increasing.
+		for i := len(s.entropy) - 1; i >= 0; i-- {
+			s.entropy[i]++
+			if s.entropy[i] != 0 {
+				break
+			}
+		}
+	}
+	var b [16]byte
+	for i := 0; i < 6; i++ {
+		b[i] = byte(s.ms >> uint(40-8*i))
+	}
+	copy(b[6:], s.entropy[:])
+	s.Unlock()
+	return encodeULID(b)
+}
+
+// encodeULID encodes the 128 bits of b as 26 base32 characters, the first
+// holding the top 3 bits.
+func encodeULID(b [16]byte) string {
+	var out [26]byte
+	// Read the bits from the end, 5 at a time.
+	var acc uint
+	var bits uint
+	j := len(out) - 1
+	for i := len(b) - 1; i >= 0; i-- {
+		acc |= uint(b[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			out[j] = crockford[acc&31]
+			j--
+			acc >>= 5
+			bits -= 5
+		}
+	}
+	out[0] = crockford[acc&31]
+	return string(out[:])
+}
@@ -0,0 +1,69 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeULID(t *testing.T) {
+	var b [16]byte
+	if got := encodeULID(b); got != "00000000000000000000000000" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, "00000000000000000000000000")
+	}
+	for i := range b {
+		b[i] = 0xff
+	}
+	if got := encodeULID(b); got != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	}
+	// The timestamp of the example in the ULID specification.
+	var ms uint64 = 1469918176385
+	b = [16]byte{}
+	for i := 0; i < 6; i++ {
+		b[i] = byte(ms >> uint(40-8*i))
+	}
+	if got := encodeULID(b)[:10]; got != "01ARYZ6S41" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, "01ARYZ6S41")
+	}
+}
+
+func TestULIDMonotonic(t *testing.T) {
+	now := time.Now()
+	prev := newULID(now)
+	for i := 0; i < 1000; i++ {
+		id := newULID(now)
+		if len(id) != 26 || id <= prev {
+			t.Fatalf("\nGot:\t%q after %q\nExpect:\ta larger id\n", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestLulid(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Lulid)
+
+	id := logr.InfoE().Str("user", "ann").Msg("login")
+	if len(id) != 26 {
+		t.Fatalf("\nGot:\t%q\nExpect:\ta ULID\n", id)
+	}
+	if expect := id + " login user=ann\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if next := logr.InfoE().Msgf("logout %s", "ann"); next <= id {
+		t.Errorf("\nGot:\t%q after %q\nExpect:\ta larger id\n", next, id)
+	}
+	logr.SetFlags(0)
+	if id := logr.InfoE().Msg("no id"); id != "" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", id, "")
+	}
+	if strings.Count(buf.String(), "\n") != 3 {
+		t.Errorf("\nGot:\t%q\nExpect:\t3 lines\n", buf.String())
+	}
+}
@@ -4,18 +4,181 @@
 package logs
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
+// ansiRegexp matches color escapes and OSC 8 hyperlink escapes.
+var ansiRegexp = regexp.MustCompile("\x1b\\[[\\d;]+m|\x1b\\]8;[^\x1b\a]*(\x1b\\\\|\a)")
+
 // stripAnsi removes all ansi escapes from a string.
 func stripAnsi(text string) string {
-	reg := regexp.MustCompile("\x1b\\[[\\d;]+m")
-	return reg.ReplaceAllString(text, "")
+	return ansiRegexp.ReplaceAllString(text, "")
 }
 
 // stripAnsiByte removes all ansi escapes from a string and returns the clean
 // string.
 func stripAnsiByte(text []byte) []byte {
-	reg := regexp.MustCompile("\x1b\\[[\\d;]+m")
-	return reg.ReplaceAll(text, []byte(""))
+	return ansiRegexp.ReplaceAll(text, []byte(""))
+}
+
+// hyperlink wraps text in an OSC 8 escape sequence linking to url.
+func hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// formatDate returns t formatted using layout. The DateUnixMillis preset is
+// handled without calling time.Format.
+func formatDate(t time.Time, layout string) string {
+	if layout == DateUnixMillis {
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
+	return t.Format(layout)
+}
+
+// modulePath is the path of the main module as reported by the build info.
+// It is empty if the binary was built without module support.
+var modulePath = func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}()
+
+// utf8Locale is true if the locale environment variables specify UTF-8
+// encoding. It is used to fall back to ASCII icons.
+var utf8Locale = func() bool {
+	for _, env := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
+	}
+	return false
+}()
+
+// isTerminal reports whether w is a character device such as a terminal.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// trimFilePath returns file relative to the first matching prefix in
+// prefixes. A prefix only matches whole directories, so "/src/app" matches
+// "/src/app/main.go" but not "/src/application/main.go". If none match, file
+// is made relative to the main module root, or to the GOPATH source directory
+// without module support. The file is returned unchanged if it could not be
+// trimmed.
+func trimFilePath(file string, prefixes []string) string {
+	for _, p := range prefixes {
+		p = strings.TrimRight(p, "/")
+		if strings.HasPrefix(file, p+"/") {
+			return strings.TrimLeft(file[len(p):], "/")
+		}
+	}
+	if modulePath == "" {
+		for _, p := range gopathSources {
+			if strings.HasPrefix(file, p+"/") {
+				return file[len(p)+1:]
+			}
+		}
+		return file
+	}
+	// Binaries built with -trimpath name files by import path.
+	if strings.HasPrefix(file, modulePath+"/") {
+		return file[len(modulePath)+1:]
+	}
+	if root := mainModuleRoot(path.Dir(file)); root != "" {
+		return strings.TrimLeft(file[len(root):], "/")
+	}
+	return file
+}
+
+// gopathSources are the source directories of the GOPATH entries.
+var gopathSources = func() []string {
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+	var dirs []string
+	for _, p := range filepath.SplitList(gopath) {
+		if p != "" {
+			dirs = append(dirs, filepath.ToSlash(filepath.Join(p, "src")))
+		}
+	}
+	return dirs
+}()
+
+// moduleRoots caches the result of mainModuleRoot by directory.
+var moduleRoots sync.Map
+
+// mainModuleRoot returns the directory of the go.mod file declaring the main
+// module if dir is in it, or "" if it is not. The go.mod file is looked up in
+// dir and its parents, so the root is only found where the source files are.
+func mainModuleRoot(dir string) string {
+	if root, ok := moduleRoots.Load(dir); ok {
+		return root.(string)
+	}
+	root := ""
+	for d := dir; ; {
+		if b, err := os.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			if goModPath(b) == modulePath {
+				root = d
+			}
+			break
+		}
+		parent := path.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+	moduleRoots.Store(dir, root)
+	return root
+}
+
+// goModPath returns the module path declared by the go.mod file b.
+func goModPath(b []byte) string {
+	for _, line := range strings.Split(string(b), "\n") {
+		f := strings.Fields(line)
+		if len(f) >= 2 && f[0] == "module" {
+			if p, err := strconv.Unquote(f[1]); err == nil {
+				return p
+			}
+			return f[1]
+		}
+	}
+	return ""
+}
+
+// truncateText cuts text to max bytes, not counting trailing new lines, and
+// appends a marker with the number of bytes removed. The cut is moved back to
+// the start of a UTF-8 sequence if needed.
+func truncateText(text string, max int) string {
+	body := strings.TrimRight(text, "\r\n")
+	if len(body) <= max {
+		return text
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]%s", body[:cut],
+		len(body)-cut, text[len(body):])
 }
@@ -0,0 +1,221 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Verbose writes entries if its verbosity level is enabled. It is returned by
+// V.
+type Verbose struct {
+	l *Logger // nil if disabled
+}
+
+// vmodule is a -vmodule style rule setting the verbosity of matching files.
+type vmodule struct {
+	pattern string
+	level   int
+}
+
+// V returns a Verbose for the standard logging object. See (*Logger).V for
+// details.
+func V(n int) Verbose { return std.v(n, 2) }
+
+// SetVerbosity sets the verbosity of the standard logging object.
+func SetVerbosity(n int) { std.SetVerbosity(n) }
+
+// SetVModule sets per file verbosity rules of the standard logging object.
+// See (*Logger).SetVModule for details.
+func SetVModule(spec string) error { return std.SetVModule(spec) }
+
+// V returns a Verbose that writes entries at LEVEL_DEBUG if n is less than or
+// equal to the verbosity, providing glog style numeric levels within the
+// debug level:
+//
+//	logr.V(2).Infof("cache hit for %q\n", key)
+//	if v := logr.V(3); v.Enabled() {
+//		v.Info(expensiveDump())
+//	}
+//
+// The logging level must include LEVEL_DEBUG for anything to be written.
+func (l *Logger) V(n int) Verbose { return l.v(n, 2) }
+
+// v implements V. calldepth is used to find the file for -vmodule rules.
+func (l *Logger) v(n, calldepth int) Verbose {
+	if !DebugEnabled {
+		return Verbose{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.level > LEVEL_DEBUG {
+		return Verbose{}
+	}
+	verbosity := l.verbosity
+	if len(l.vmodules) > 0 {
+		if _, file, _, ok := runtime.Caller(calldepth); ok {
+			name := strings.TrimSuffix(filepath.Base(file), ".go")
+			for _, m := range l.vmodules {
+				if ok, _ := filepath.Match(m.pattern, name); ok {
+					verbosity = m.level
+					break
+				}
+			}
+		}
+	}
+	if n > verbosity {
+		return Verbose{}
+	}
+	return Verbose{l}
+}
+
+// Verbosity returns the verbosity level.
+func (l *Logger) Verbosity() int { return l.verbosity }
+
+// SetVerbosity sets the verbosity level used by V, like the -v flag of glog.
+func (l *Logger) SetVerbosity(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.verbosity = n
+}
+
+// SetVModule sets the verbosity per file using the -vmodule syntax of glog,
+// a comma separated list of pattern=N. Patterns are matched against the
+// source file name without the ".go" suffix and may contain wildcards. The
+// first matching rule overrides the verbosity set by SetVerbosity. An empty
+// spec removes all rules.
+//
+//	logr.SetVModule("server=2,db*=4")
+func (l *Logger) SetVModule(spec string) error {
+	var mods []vmodule
+	for _, rule := range strings.Split(spec, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		i := strings.LastIndexByte(rule, '=')
+		if i <= 0 {
+			return fmt.Errorf("logs: invalid vmodule rule %q", rule)
+		}
+		n, err := strconv.Atoi(rule[i+1:])
+		if err != nil {
+			return fmt.Errorf("logs: invalid vmodule rule %q", rule)
+		}
+		if _, err := filepath.Match(rule[:i], ""); err != nil {
+			return fmt.Errorf("logs: invalid vmodule pattern %q", rule[:i])
+		}
+		mods = append(mods, vmodule{rule[:i], n})
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.vmodules = mods
+	return nil
+}
+
+// Enabled reports whether v writes entries.
+func (v Verbose) Enabled() bool { return v.l != nil }
+
+// Infof is like Debugf if v is enabled.
+func (v Verbose) Infof(format string, a ...interface{}) {
+	if v.l != nil {
+		v.l.fprintf(v.l.Flags(), LEVEL_DEBUG, 2, 0, nil, format, a...)
+	}
+}
+
+// Info is like Debug if v is enabled.
+func (v Verbose) Info(a ...interface{}) {
+	if v.l != nil {
+		v.l.Fprint(v.l.Flags(), LEVEL_DEBUG, 2, fmt.Sprint(a...), nil)
+	}
+}
+
+// Infoln is like Debugln if v is enabled.
+func (v Verbose) Infoln(a ...interface{}) {
+	if v.l != nil {
+		v.l.Fprint(v.l.Flags(), LEVEL_DEBUG, 2, fmt.Sprintln(a...), nil)
+	}
+}
+
+// LevelFromVerbosity returns the conventional logging level for a count of
+// -v flags: 0 is LEVEL_WARNING, 1 is LEVEL_INFO, and 2 or more is
+// LEVEL_DEBUG. Counts above 2 enable V levels, see VerbosityFlag.
+func LevelFromVerbosity(n int) level {
+	switch {
+	case n <= 0:
+		return LEVEL_WARNING
+	case n == 1:
+		return LEVEL_INFO
+	}
+	return LEVEL_DEBUG
+}
+
+// verbosityFlag is a flag.Value counting repeated boolean flags.
+type verbosityFlag struct {
+	l *Logger
+	n int
+}
+
+func (f *verbosityFlag) String() string {
+	if f == nil {
+		return "0"
+	}
+	return strconv.Itoa(f.n)
+}
+
+// Set increments the count for every -v, or sets it for -v=N.
+func (f *verbosityFlag) Set(s string) error {
+	switch s {
+	case "true":
+		f.n++
+	case "false":
+		f.n = 0
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.n = n
+	}
+	f.l.SetVerbosityCount(f.n)
+	return nil
+}
+
+func (f *verbosityFlag) IsBoolFlag() bool { return true }
+
+// VerbosityFlag defines a repeatable flag on fs that sets the level of the
+// standard logging object. See (*Logger).VerbosityFlag for details.
+func VerbosityFlag(fs *flag.FlagSet, name string) { std.VerbosityFlag(fs, name) }
+
+// SetVerbosityCount sets the level of the standard logging object from a
+// count of -v flags. See (*Logger).SetVerbosityCount.
+func SetVerbosityCount(n int) { std.SetVerbosityCount(n) }
+
+// VerbosityFlag defines a flag on fs that can be repeated to raise the
+// logging level, the common CLI convention:
+//
+//	logr.VerbosityFlag(flag.CommandLine, "v")
+//	flag.Parse() // prog -v -v ...
+//
+// The level is set to LEVEL_WARNING immediately and raised every time the
+// flag is given. "-v=N" sets the count directly. See SetVerbosityCount.
+func (l *Logger) VerbosityFlag(fs *flag.FlagSet, name string) {
+	l.SetVerbosityCount(0)
+	fs.Var(&verbosityFlag{l: l}, name, "increase logging verbosity, may be repeated")
+}
+
+// SetVerbosityCount sets the logging level to LevelFromVerbosity(n). Counts
+// above 2 also set the verbosity used by V to n-2, so that "-v -v -v"
+// enables V(1), the equivalent of a trace level.
+func (l *Logger) SetVerbosityCount(n int) {
+	l.SetLevel(LevelFromVerbosity(n))
+	if n > 2 {
+		l.SetVerbosity(n - 2)
+	} else {
+		l.SetVerbosity(0)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestV(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	logr.SetVerbosity(2)
+	logr.V(1).Infoln("one")
+	logr.V(2).Infof("two\n")
+	logr.V(3).Infoln("three")
+	if expect := "one\ntwo\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+
+	buf.Reset()
+	logr.SetLevel(LEVEL_INFO)
+	if logr.V(0).Enabled() {
+		t.Errorf("V(0).Enabled() = true above LEVEL_DEBUG")
+	}
+}
+
+func TestSetVModule(t *testing.T) {
+	skipNoDebug(t)
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(0)
+	if err := logr.SetVModule("other=9,verbose_*=4"); err != nil {
+		t.Fatal(err)
+	}
+	logr.V(4).Infoln("four")
+	logr.V(5).Infoln("five")
+	if expect := "four\n"; buf.String() != expect {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	for _, spec := range []string{"noequals", "x=y", "[=1"} {
+		if err := logr.SetVModule(spec); err == nil {
+			t.Errorf("SetVModule(%q) = nil; want: error", spec)
+		}
+	}
+}
+
+func TestVerbosityFlag(t *testing.T) {
+	var tests = []struct {
+		args      []string
+		level     level
+		verbosity int
+	}{
+		{nil, LEVEL_WARNING, 0},
+		{[]string{"-v"}, LEVEL_INFO, 0},
+		{[]string{"-v", "-v"}, LEVEL_DEBUG, 0},
+		{[]string{"-v", "-v", "-v"}, LEVEL_DEBUG, 1},
+		{[]string{"-v=4"}, LEVEL_DEBUG, 2},
+	}
+	for _, test := range tests {
+		logr := New(LEVEL_CRITICAL, &bytes.Buffer{})
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		logr.VerbosityFlag(fs, "v")
+		if err := fs.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+		if logr.Level() != test.level || logr.Verbosity() != test.verbosity {
+			t.Errorf("%q\nGot:\t%s %d\nExpect:\t%s %d\n", test.args,
+				logr.Level(), logr.Verbosity(), test.level, test.verbosity)
+		}
+	}
+}
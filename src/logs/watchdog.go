@@ -0,0 +1,89 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"sync"
+	"time"
+)
+
+// watchdog fires when no entries at or above a level are written for a
+// duration.
+type watchdog struct {
+	level level
+	limit time.Duration
+	timer *time.Timer
+	f     func(idle time.Duration)
+
+	mu      sync.Mutex
+	last    time.Time // Time of the last entry at or above level
+	stopped bool
+}
+
+// observe restarts the watchdog if e is at or above its level.
+func (w *watchdog) observe(e *Entry) {
+	if e.Level < w.level || e.Level == LEVEL_PRINT {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = e.Time
+	if !w.stopped {
+		w.timer.Reset(w.limit)
+	}
+}
+
+// fire calls the callback and rearms the watchdog, so that it fires again if
+// the silence continues.
+func (w *watchdog) fire() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	idle := time.Since(w.last)
+	w.timer.Reset(w.limit)
+	w.mu.Unlock()
+	w.f(idle)
+}
+
+// Watchdog starts a watchdog on the standard logging object. See
+// (*Logger).Watchdog for details.
+func Watchdog(lvl level, d time.Duration, f func(idle time.Duration)) (stop func()) {
+	return std.Watchdog(lvl, d, f)
+}
+
+// Watchdog calls f if no entries at or above lvl have been written for d, a
+// cheap way to notice a wedged worker. f is called again every d while the
+// silence continues. If f is nil, a WARNING entry like "no info entries for
+// 1m0s" is written instead. The returned function stops the watchdog.
+func (l *Logger) Watchdog(lvl level, d time.Duration, f func(idle time.Duration)) (stop func()) {
+	w := &watchdog{level: lvl, limit: d, f: f, last: time.Now()}
+	if w.f == nil {
+		w.f = func(idle time.Duration) {
+			l.Warningf("no %s entries for %s\n", levelName(lvl), idle.Round(time.Millisecond))
+		}
+	}
+	// The timer is set before fire can run, which reads it under w.mu.
+	w.mu.Lock()
+	w.timer = time.AfterFunc(d, w.fire)
+	w.mu.Unlock()
+	l.mu.Lock()
+	l.watchdogs = append(l.watchdogs, w)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, x := range l.watchdogs {
+			if x == w {
+				l.watchdogs = append(l.watchdogs[:i:i], l.watchdogs[i+1:]...)
+				break
+			}
+		}
+		w.mu.Lock()
+		w.stopped = true
+		w.timer.Stop()
+		w.mu.Unlock()
+	}
+}
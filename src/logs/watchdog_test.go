@@ -0,0 +1,70 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	fired := make(chan time.Duration, 10)
+	logr := New(LEVEL_DEBUG, &bytes.Buffer{})
+	stop := logr.Watchdog(LEVEL_INFO, 100*time.Millisecond,
+		func(idle time.Duration) { fired <- idle })
+	defer stop()
+
+	// Entries at the watched level keep the watchdog quiet.
+	for i := 0; i < 5; i++ {
+		time.Sleep(40 * time.Millisecond)
+		logr.Infoln("working")
+	}
+	select {
+	case <-fired:
+		t.Fatal("Watchdog fired while entries were written")
+	default:
+	}
+
+	// Debug entries do not.
+	logr.Debugln("still here")
+	select {
+	case idle := <-fired:
+		if idle < 100*time.Millisecond {
+			t.Errorf("Idle time %s; want at least 100ms", idle)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watchdog did not fire")
+	}
+}
+
+func TestWatchdogDefault(t *testing.T) {
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &lockedWriter{mu: &mu, w: &buf})
+	logr.SetFlags(Llabel)
+	stop := logr.Watchdog(LEVEL_ERROR, 10*time.Millisecond, nil)
+	time.Sleep(50 * time.Millisecond)
+	stop()
+	mu.Lock()
+	defer mu.Unlock()
+	if expect := "[WARNING]  no error entries for "; !strings.HasPrefix(buf.String(), expect) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+}
+
+// lockedWriter serializes writes to w with mu.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,44 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"strings"
+	"time"
+)
+
+// Location returns the time zone of the dates of the standard logging object.
+func Location() *time.Location { return std.Location() }
+
+// SetLocation sets the time zone of the dates of the standard logging object.
+// See (*Logger).SetLocation.
+func SetLocation(loc *time.Location) { std.SetLocation(loc) }
+
+// Location returns the time zone of the dates of l, or nil for the local
+// time zone.
+func (l *Logger) Location() *time.Location {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.location
+}
+
+// SetLocation sets the time zone of the dates written by l and of the time of
+// its entries, for example time.UTC for servers in several regions writing to
+// the same place. nil uses the local time zone. Combine it with the Lzone flag
+// to show the zone after the date.
+func (l *Logger) SetLocation(loc *time.Location) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.location = loc
+}
+
+// zoneName returns the abbreviation of the time zone of t, or the offset such
+// as "-0700" if the zone has none.
+func zoneName(t time.Time) string {
+	name, _ := t.Zone()
+	if name == "" || strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") {
+		return t.Format("-0700")
+	}
+	return name
+}
@@ -0,0 +1,51 @@
+// Copyright 2013,2014,2015 The go-logs Authors. All rights reserved.
+// This code is MIT licensed. See the LICENSE file for more info.
+
+package logs
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestLocation(t *testing.T) {
+	var buf bytes.Buffer
+	logr := New(LEVEL_DEBUG, &buf)
+	logr.SetFlags(Ldate | Lzone)
+	logr.SetDateFormat("15:04")
+
+	logr.SetLocation(time.UTC)
+	logr.Infoln("utc")
+	logr.SetLocation(time.FixedZone("", 3*60*60))
+	logr.Infoln("offset")
+	logr.SetLocation(time.FixedZone("CET", 60*60))
+	logr.SetFlags(Ldate)
+	logr.Infoln("no zone")
+
+	expect := regexp.MustCompile(`^\d\d:\d\d UTC utc\n\d\d:\d\d \+0300 offset\n\d\d:\d\d no zone\n$`)
+	if !expect.MatchString(buf.String()) {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", buf.String(), expect)
+	}
+	if loc := logr.Location(); loc.String() != "CET" {
+		t.Errorf("\nGot:\t%q\nExpect:\t%q\n", loc, "CET")
+	}
+}
+
+func TestZoneName(t *testing.T) {
+	tests := []struct {
+		loc    *time.Location
+		expect string
+	}{
+		{time.UTC, "UTC"},
+		{time.FixedZone("EST", -5*60*60), "EST"},
+		{time.FixedZone("", -7*60*60), "-0700"},
+		{time.FixedZone("+03", 3*60*60), "+0300"},
+	}
+	for _, tt := range tests {
+		if got := zoneName(time.Date(2009, 11, 10, 23, 0, 0, 0, tt.loc)); got != tt.expect {
+			t.Errorf("\nGot:\t%q\nExpect:\t%q\n", got, tt.expect)
+		}
+	}
+}